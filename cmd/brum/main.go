@@ -23,6 +23,7 @@ import (
 	"github.com/standardbeagle/brummer/internal/mcp"
 	"github.com/standardbeagle/brummer/internal/process"
 	"github.com/standardbeagle/brummer/internal/proxy"
+	"github.com/standardbeagle/brummer/internal/sourcemap"
 	"github.com/standardbeagle/brummer/internal/tui"
 	"github.com/standardbeagle/brummer/pkg/events"
 )
@@ -45,6 +46,12 @@ var (
 	debugMode     bool
 	mcpDebug      bool
 	mcpHub        bool
+	envProfile    string
+	adoptOrphans  bool
+	startGroups   []string
+	allPackages   bool
+	pkgFilters    []string
+	concurrency   int
 )
 
 var rootCmd = &cobra.Command{
@@ -60,6 +67,14 @@ Basic Usage:
   brum dev test                 # Start both 'dev' and 'test' scripts
   brum 'node server.js'         # Run arbitrary command
   brum -d ../app dev            # Run 'dev' in ../app directory
+  brum --group backend          # Start every service in the 'backend' group
+  brum --adopt                  # Reattach to processes left running by a previous session
+
+Workspace Examples:
+  brum run test --all-packages  # Run 'test' in every workspace package
+  brum test --filter 'pkg-*'    # Run 'test' in the packages matching a glob
+  brum build --all-packages --concurrency 2
+  brum web#build                # Run web's build after its upstream builds (turbo.json or Nx)
 
 Proxy Examples:
   brum --standard-proxy         # Start with traditional HTTP proxy (port 19888)
@@ -99,6 +114,12 @@ func init() {
 
 	// Directory and port flags
 	rootCmd.Flags().StringVarP(&workDir, "dir", "d", ".", "Working directory (package.json optional)")
+	rootCmd.Flags().StringVar(&envProfile, "profile", "", "Environment profile; loads .env.<profile> between .env and .env.local")
+	rootCmd.Flags().StringSliceVar(&startGroups, "group", nil, "Start every service in a group declared in .brum.toml (repeatable; 'procfile' is the whole Procfile)")
+	rootCmd.Flags().BoolVar(&allPackages, "all-packages", false, "Run the given scripts in every workspace package")
+	rootCmd.Flags().StringSliceVar(&pkgFilters, "filter", nil, "Run the given scripts in the workspace packages matching a glob (repeatable)")
+	rootCmd.Flags().IntVar(&concurrency, "concurrency", 4, "How many packages --all-packages and --filter run at once, and how many package#task tasks")
+	rootCmd.Flags().BoolVar(&adoptOrphans, "adopt", false, "Reattach to processes still running from a previous session (needs persist_processes)")
 	rootCmd.Flags().IntVarP(&mcpPort, "port", "p", 7777, "MCP server port")
 
 	// Proxy configuration
@@ -164,8 +185,37 @@ func runApp(cmd *cobra.Command, args []string) {
 	if err != nil {
 		log.Fatal("Failed to initialize process manager:", err)
 	}
+	if envProfile != "" {
+		processMgr.SetEnvProfile(envProfile)
+	}
 
 	logStore := logs.NewStore(10000, eventBus)
+	// Keep log history on disk beyond the in-memory window
+	startupCfg, _ := config.Load()
+	if logsCfg := startupCfg.GetLogs(); logsCfg.GetPersist() {
+		history, err := logs.OpenSegmentStore(logs.HistoryDir(logsCfg.GetDir(), absWorkDir), logs.Retention{
+			MaxBytes:     logsCfg.GetMaxSize(),
+			MaxAge:       logsCfg.GetMaxAge(),
+			SegmentBytes: logsCfg.GetSegmentSize(),
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Log history disabled: %v\n", err)
+		} else {
+			logStore.SetHistory(history)
+		}
+	}
+	// Map stack frames of bundled JavaScript back to their sources
+	logStore.SetStackResolver(sourcemap.NewResolver(absWorkDir))
+	// Count errors by fingerprint, next to the history when it is kept
+	fingerprintPath := ""
+	if logsCfg := startupCfg.GetLogs(); logsCfg.GetPersist() {
+		fingerprintPath = logs.FingerprintPath(logsCfg.GetDir(), absWorkDir)
+	}
+	if fingerprints, err := logs.OpenFingerprintStore(fingerprintPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Error fingerprints disabled: %v\n", err)
+	} else {
+		logStore.SetFingerprints(fingerprints)
+	}
 	detector := logs.NewEventDetector(eventBus)
 
 	// Initialize proxy server if enabled
@@ -245,6 +295,45 @@ func runApp(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	// startProxyOnDemand starts the reverse proxy the first time a URL needs it
+	startProxyOnDemand := func(reason string) {
+		if proxyServer.IsRunning() {
+			return
+		}
+		if err := proxyServer.Start(); err != nil {
+			if noTUI {
+				log.Printf("Failed to start proxy server: %v", err)
+			} else {
+				logStore.Add("system", "proxy", fmt.Sprintf("❌ Failed to start proxy server: %v", err), true)
+			}
+			return
+		}
+		actualPort := proxyServer.GetPort()
+		if noTUI {
+			fmt.Printf("Started HTTP proxy server on port %d (%s)\n", actualPort, reason)
+		} else {
+			logStore.Add("system", "proxy", fmt.Sprintf("🌐 Started HTTP proxy server on port %d for %s", actualPort, reason), false)
+		}
+	}
+
+	// Processes given a port are proxied as soon as they start, before they log a URL
+	if proxyServer != nil {
+		eventBus.Subscribe(events.ProcessStarted, func(e events.Event) {
+			port, ok := e.Data["port"].(int)
+			if !ok || port <= 0 {
+				return
+			}
+			name, _ := e.Data["name"].(string)
+			startProxyOnDemand("assigned ports")
+			url := fmt.Sprintf("http://localhost:%d", port)
+			if proxyServer.GetProxyURL(url) == url {
+				if proxyURL := proxyServer.RegisterURLWithLabel(url, name, name); proxyURL != url {
+					logStore.UpdateProxyURL(url, proxyURL)
+				}
+			}
+		})
+	}
+
 	// Set up log processing with event detection
 	processMgr.AddLogCallback(func(processID, line string, isError bool) {
 		if proc, exists := processMgr.GetProcess(processID); exists {
@@ -267,23 +356,7 @@ func runApp(cmd *cobra.Command, args []string) {
 				detectedURLs := logStore.DetectURLsInContent(line)
 				if len(detectedURLs) > 0 {
 					// Start proxy server if it's not running and URLs are detected
-					if !proxyServer.IsRunning() {
-						if err := proxyServer.Start(); err != nil {
-							if noTUI {
-								log.Printf("Failed to start proxy server: %v", err)
-							} else {
-								logStore.Add("system", "proxy", fmt.Sprintf("❌ Failed to start proxy server: %v", err), true)
-							}
-						} else {
-							// Proxy server started successfully due to URL detection
-							actualPort := proxyServer.GetPort()
-							if noTUI {
-								fmt.Printf("Started HTTP proxy server on port %d (detected URLs in logs)\n", actualPort)
-							} else {
-								logStore.Add("system", "proxy", fmt.Sprintf("🌐 Started HTTP proxy server on port %d for detected URLs", actualPort), false)
-							}
-						}
-					}
+					startProxyOnDemand("detected URLs in logs")
 
 					// Process detected URLs
 					for _, url := range detectedURLs {
@@ -301,63 +374,181 @@ func runApp(cmd *cobra.Command, args []string) {
 					}
 				}
 			}
+		} else if processID == "system" && line != "" {
+			// Manager notices that belong to no process, such as terminations of processes it did not start
+			if noTUI {
+				fmt.Printf("[%s] %s\n", time.Now().Format("15:04:05"), line)
+			}
+			logStore.Add("system", "System", line, isError)
 		}
 	})
 
+	// Reattach to processes a previous brummer left running, when persistence is enabled
+	orphans, err := processMgr.OpenSession(discovery.GetSessionDir(absWorkDir))
+	if err != nil {
+		if noTUI {
+			log.Printf("Failed to open process session: %v", err)
+		} else {
+			logStore.Add("system", "session", fmt.Sprintf("⚠️  Failed to open process session: %v", err), true)
+		}
+	} else if len(orphans) > 0 {
+		if adoptOrphans {
+			adopted, err := processMgr.AdoptOrphans()
+			for _, proc := range adopted {
+				if noTUI {
+					fmt.Printf("Adopted '%s' (PID %d)\n", proc.Name, proc.Cmd.Process.Pid)
+				}
+			}
+			if err != nil {
+				if noTUI {
+					log.Printf("%v", err)
+				} else {
+					logStore.Add("system", "session", fmt.Sprintf("❌ %v", err), true)
+				}
+			}
+		} else {
+			names := make([]string, 0, len(orphans))
+			for _, orphan := range orphans {
+				names = append(names, fmt.Sprintf("%s (PID %d)", orphan.Name, orphan.PID))
+			}
+			if noTUI {
+				fmt.Printf("Still running from a previous session: %s. Restart with --adopt to reattach.\n", strings.Join(names, ", "))
+			} else {
+				logStore.Add("system", "session", fmt.Sprintf("♻️ Still running from a previous session: %s. Use /adopt to reattach or /adopt kill to stop them.", strings.Join(names, ", ")), false)
+			}
+		}
+	}
+
+	// Run recurring commands declared under [schedules] in .brum.toml
+	processMgr.StartSchedules()
+
 	// Handle CLI arguments to start scripts
 	var startedFromCLI bool
-	if len(args) > 0 {
+	for _, group := range startGroups {
 		startedFromCLI = true
+		started, err := processMgr.StartGroup(group)
+		for _, proc := range started {
+			if noTUI {
+				fmt.Printf("Started service '%s' from group '%s' (PID: %s)\n", proc.Name, group, proc.ID)
+			} else {
+				logStore.Add("system", "startup", fmt.Sprintf("✅ Started service '%s' from group '%s' (PID: %s)", proc.Name, group, proc.ID), false)
+			}
+		}
+		if err != nil {
+			if noTUI {
+				log.Printf("Failed to start group '%s': %v", group, err)
+			} else {
+				logStore.Add("system", "startup", fmt.Sprintf("❌ Failed to start group '%s': %v", group, err), true)
+			}
+		}
+	}
 
-		if hasPackageJSON {
-			scripts := processMgr.GetScripts()
+	// With --all-packages or --filter the arguments are scripts to run in each package
+	var matrixRuns []*process.Run
+	if allPackages || len(pkgFilters) > 0 {
+		if _, isScript := processMgr.GetScripts()["run"]; len(args) > 0 && args[0] == "run" && !isScript {
+			args = args[1:] // brum run test --all-packages
+		}
+		if len(args) == 0 {
+			log.Fatal("--all-packages and --filter need the name of a script to run")
+		}
+		for _, script := range args {
+			startedFromCLI = true
+			run, err := processMgr.RunMatrix(script, process.MatrixOptions{Filters: pkgFilters, Concurrency: concurrency})
+			if err != nil {
+				if noTUI {
+					log.Printf("Failed to run '%s' across packages: %v", script, err)
+				} else {
+					logStore.Add("system", "startup", fmt.Sprintf("❌ Failed to run '%s' across packages: %v", script, err), true)
+				}
+				continue
+			}
+			if noTUI {
+				fmt.Printf("Running '%s' in %d packages\n", script, run.Summary().Total)
+			}
+			matrixRuns = append(matrixRuns, run)
+		}
+		args = nil
+	}
 
-			for _, arg := range args {
-				// Check if it's a known script
-				if _, exists := scripts[arg]; exists {
-					// Start the script
-					proc, err := processMgr.StartScript(arg)
-					if err != nil {
-						if noTUI {
-							log.Printf("Failed to start script '%s': %v", arg, err)
-						} else {
-							logStore.Add("system", "startup", fmt.Sprintf("❌ Failed to start script '%s': %v", arg, err), true)
-						}
-					} else {
-						if noTUI {
-							fmt.Printf("Started script '%s' (PID: %s)\n", arg, proc.ID)
-						} else {
-							logStore.Add("system", "startup", fmt.Sprintf("✅ Started script '%s' (PID: %s)", arg, proc.ID), false)
-						}
-					}
-					continue
+	// package#task runs a Turborepo or Nx task after the tasks it depends on
+	var taskRuns []*process.Run
+	if len(args) > 0 {
+		scripts := processMgr.GetScripts()
+		var remaining []string
+		for _, arg := range args {
+			if _, isScript := scripts[arg]; isScript || !process.IsTaskTarget(arg) {
+				remaining = append(remaining, arg)
+				continue
+			}
+			startedFromCLI = true
+			run, err := processMgr.RunTask(arg, concurrency)
+			if err != nil {
+				if noTUI {
+					log.Printf("Failed to run task '%s': %v", arg, err)
+				} else {
+					logStore.Add("system", "startup", fmt.Sprintf("❌ Failed to run task '%s': %v", arg, err), true)
 				}
+				continue
+			}
+			if noTUI {
+				fmt.Printf("Running task '%s' (%d tasks)\n", arg, run.Summary().Total)
+			}
+			taskRuns = append(taskRuns, run)
+		}
+		args = remaining
+	}
 
-				// Fallback to command execution if not a script
-				if len(args) == 1 && strings.Contains(arg, " ") {
-					// Single argument with spaces - treat as a command
-					parts := strings.Fields(arg)
-					if len(parts) > 0 {
-						proc, err := processMgr.StartCommand("custom", parts[0], parts[1:])
-						if err != nil {
-							log.Fatalf("Failed to start command '%s': %v", arg, err)
-						} else {
-							if noTUI {
-								fmt.Printf("Started command '%s' (PID: %s)\n", arg, proc.ID)
-							} else {
-								logStore.Add("system", "startup", fmt.Sprintf("✅ Started command '%s' (PID: %s)", arg, proc.ID), false)
-							}
-						}
+	// Headless matrix and task runs shut brummer down once every one has finished
+	var runsDone chan struct{}
+	if noTUI && len(args) == 0 && len(matrixRuns)+len(taskRuns) > 0 {
+		runsDone = make(chan struct{})
+		go func() {
+			for _, run := range matrixRuns {
+				<-run.Done()
+			}
+			for _, run := range taskRuns {
+				<-run.Done()
+			}
+			close(runsDone)
+		}()
+	}
+
+	if len(args) > 0 {
+		startedFromCLI = true
+
+		// Scripts are package.json scripts plus services from .brum.toml and the Procfile
+		scripts := processMgr.GetScripts()
+
+		for _, arg := range args {
+			// Check if it's a known script
+			if _, exists := scripts[arg]; exists {
+				// Start the script
+				proc, err := processMgr.StartScript(arg)
+				if err != nil {
+					if noTUI {
+						log.Printf("Failed to start script '%s': %v", arg, err)
+					} else {
+						logStore.Add("system", "startup", fmt.Sprintf("❌ Failed to start script '%s': %v", arg, err), true)
 					}
 				} else {
-					// Try to run it as a command
-					proc, err := processMgr.StartCommand(arg, arg, []string{})
+					if noTUI {
+						fmt.Printf("Started script '%s' (PID: %s)\n", arg, proc.ID)
+					} else {
+						logStore.Add("system", "startup", fmt.Sprintf("✅ Started script '%s' (PID: %s)", arg, proc.ID), false)
+					}
+				}
+				continue
+			}
+
+			// Fallback to command execution if not a script
+			if len(args) == 1 && strings.Contains(arg, " ") {
+				// Single argument with spaces - treat as a command
+				parts := strings.Fields(arg)
+				if len(parts) > 0 {
+					proc, err := processMgr.StartCommand("custom", parts[0], parts[1:])
 					if err != nil {
-						if noTUI {
-							log.Printf("Failed to start command '%s': %v", arg, err)
-						} else {
-							logStore.Add("system", "startup", fmt.Sprintf("❌ Failed to start command '%s': %v", arg, err), true)
-						}
+						log.Fatalf("Failed to start command '%s': %v", arg, err)
 					} else {
 						if noTUI {
 							fmt.Printf("Started command '%s' (PID: %s)\n", arg, proc.ID)
@@ -366,49 +557,29 @@ func runApp(cmd *cobra.Command, args []string) {
 						}
 					}
 				}
-			}
-		} else {
-			// No package.json - treat all args as commands
-			for _, arg := range args {
-				if len(args) == 1 && strings.Contains(arg, " ") {
-					// Single argument with spaces - treat as a command
-					parts := strings.Fields(arg)
-					if len(parts) > 0 {
-						proc, err := processMgr.StartCommand("custom", parts[0], parts[1:])
-						if err != nil {
-							log.Fatalf("Failed to start command '%s': %v", arg, err)
-						} else {
-							if noTUI {
-								fmt.Printf("Started command '%s' (PID: %s)\n", arg, proc.ID)
-							} else {
-								logStore.Add("system", "startup", fmt.Sprintf("✅ Started command '%s' (PID: %s)", arg, proc.ID), false)
-							}
-						}
+			} else {
+				// Try to run it as a command
+				proc, err := processMgr.StartCommand(arg, arg, []string{})
+				if err != nil {
+					if noTUI {
+						log.Printf("Failed to start command '%s': %v", arg, err)
+					} else {
+						logStore.Add("system", "startup", fmt.Sprintf("❌ Failed to start command '%s': %v", arg, err), true)
 					}
 				} else {
-					// Try to run it as a command
-					proc, err := processMgr.StartCommand(arg, arg, []string{})
-					if err != nil {
-						if noTUI {
-							log.Printf("Failed to start command '%s': %v", arg, err)
-						} else {
-							logStore.Add("system", "startup", fmt.Sprintf("❌ Failed to start command '%s': %v", arg, err), true)
-						}
+					if noTUI {
+						fmt.Printf("Started command '%s' (PID: %s)\n", arg, proc.ID)
 					} else {
-						if noTUI {
-							fmt.Printf("Started command '%s' (PID: %s)\n", arg, proc.ID)
-						} else {
-							logStore.Add("system", "startup", fmt.Sprintf("✅ Started command '%s' (PID: %s)", arg, proc.ID), false)
-						}
+						logStore.Add("system", "startup", fmt.Sprintf("✅ Started command '%s' (PID: %s)", arg, proc.ID), false)
 					}
 				}
 			}
 		}
+	}
 
-		// Give processes a moment to start before showing TUI
-		if startedFromCLI {
-			time.Sleep(100 * time.Millisecond)
-		}
+	// Give processes a moment to start before showing TUI
+	if startedFromCLI {
+		time.Sleep(100 * time.Millisecond)
 	}
 
 	// Track instance registration for cleanup
@@ -525,9 +696,24 @@ func runApp(cmd *cobra.Command, args []string) {
 			sigChan := make(chan os.Signal, 1)
 			setupSignalHandling(sigChan)
 
-			// Wait for signal
-			<-sigChan
-			fmt.Println("\nShutting down gracefully...")
+			// Wait for a signal, or for the package and task runs to finish
+			runsFailed := false
+			select {
+			case <-sigChan:
+				fmt.Println("\nShutting down gracefully...")
+			case <-runsDone:
+				for _, run := range matrixRuns {
+					summary := run.Summary()
+					fmt.Println(summary)
+					runsFailed = runsFailed || summary.Failed > 0
+				}
+				for _, run := range taskRuns {
+					summary := run.Summary()
+					fmt.Println(summary)
+					runsFailed = runsFailed || summary.Failed > 0
+				}
+				fmt.Println("\nAll runs finished, shutting down...")
+			}
 
 			// Cleanup all processes
 			fmt.Println("Stopping all running processes...")
@@ -549,6 +735,9 @@ func runApp(cmd *cobra.Command, args []string) {
 			}
 
 			fmt.Println("Cleanup complete.")
+			if runsFailed {
+				os.Exit(1)
+			}
 			return
 		}
 	}
@@ -99,6 +99,7 @@ func NewAICoderManagerWithPTY(config Config, eventBus EventBus, dataProvider Bru
 
 	// Add PTY support
 	manager.ptyManager = NewPTYManager(dataProvider, eventBus)
+	manager.ptyManager.SetResourceLimits(config.GetAICoderConfig().ResourceLimits)
 
 	return manager, nil
 }
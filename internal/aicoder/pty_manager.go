@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/standardbeagle/brummer/internal/cgroup"
 )
 
 // PTYManager manages multiple PTY sessions for AI coders
@@ -19,6 +20,9 @@ type PTYManager struct {
 	// Brummer integration
 	dataProvider BrummerDataProvider
 	eventBus     EventBus
+
+	// Resource limits applied to new sessions
+	limits cgroup.Limits
 }
 
 // NewPTYManager creates a new PTY session manager
@@ -30,6 +34,13 @@ func NewPTYManager(dataProvider BrummerDataProvider, eventBus EventBus) *PTYMana
 	}
 }
 
+// SetResourceLimits sets the limits enforced on sessions created from now on
+func (pm *PTYManager) SetResourceLimits(limits cgroup.Limits) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.limits = limits
+}
+
 // CreateSession creates a new PTY session
 func (pm *PTYManager) CreateSession(name, command string, args []string) (*PTYSession, error) {
 	return pm.CreateSessionWithEnv(name, command, args, nil)
@@ -42,7 +53,7 @@ func (pm *PTYManager) CreateSessionWithEnv(name, command string, args []string,
 
 	sessionID := uuid.New().String()
 
-	session, err := NewPTYSessionWithEnv(sessionID, name, command, args, extraEnv)
+	session, err := newPTYSession(sessionID, name, command, args, extraEnv, pm.limits)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create PTY session: %w", err)
 	}
@@ -223,9 +234,13 @@ func (pm *PTYManager) CloseSession(sessionID string) error {
 
 	// Emit session closed event
 	if pm.eventBus != nil {
-		pm.eventBus.Emit("pty_session_closed", map[string]interface{}{
+		data := map[string]interface{}{
 			"session_id": sessionID,
-		})
+		}
+		if session.OOMKilled() {
+			data["reason"] = "oom_killed"
+		}
+		pm.eventBus.Emit("pty_session_closed", data)
 	}
 
 	return nil
@@ -14,6 +14,7 @@ import (
 
 	"github.com/creack/pty"
 	"github.com/hinshun/vt10x"
+	"github.com/standardbeagle/brummer/internal/cgroup"
 )
 
 // PTYSession represents a pseudo-terminal session for an AI coder
@@ -46,6 +47,45 @@ type PTYSession struct {
 	outputHistory []byte
 	maxHistory    int
 	historyMutex  sync.Mutex
+
+	// Resource limits
+	cgroup        *cgroup.Group
+	limitsWarning error
+	oomKilled     bool
+}
+
+// watchOOM reports each OOM kill in the session's cgroup as it happens. The kernel
+// may kill only a command the coder started, leaving the session itself running.
+func (s *PTYSession) watchOOM(group *cgroup.Group) {
+	group.WatchOOM(s.ctx.Done(), func(kills int) {
+		s.mu.Lock()
+		s.oomKilled = true
+		s.mu.Unlock()
+
+		select {
+		case s.EventChan <- PTYEvent{
+			Type:      PTYEventOOM,
+			SessionID: s.ID,
+			Data:      fmt.Sprintf("%d process(es) killed for exceeding the memory limit", kills),
+			Timestamp: time.Now(),
+		}:
+		case <-s.ctx.Done():
+		}
+	})
+}
+
+// LimitsWarning explains which resource limits could not be enforced, if any
+func (s *PTYSession) LimitsWarning() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.limitsWarning
+}
+
+// OOMKilled reports whether the session was killed for exceeding its memory limit
+func (s *PTYSession) OOMKilled() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.oomKilled
 }
 
 // IsAtStartOfLine returns true if the cursor is at the beginning of a line
@@ -105,6 +145,7 @@ const (
 	PTYEventResize     PTYEventType = "resize"
 	PTYEventClose      PTYEventType = "close"
 	PTYEventDataInject PTYEventType = "data_inject"
+	PTYEventOOM        PTYEventType = "oom_killed" // a process in the session hit its memory limit
 )
 
 // NewPTYSession creates a new PTY session for an AI coder
@@ -114,6 +155,12 @@ func NewPTYSession(id, name, command string, args []string) (*PTYSession, error)
 
 // NewPTYSessionWithEnv creates a new PTY session with additional environment variables
 func NewPTYSessionWithEnv(id, name, command string, args []string, extraEnv map[string]string) (*PTYSession, error) {
+	return newPTYSession(id, name, command, args, extraEnv, cgroup.Limits{})
+}
+
+// newPTYSession creates a PTY session whose process tree is confined to limits from
+// the moment it starts
+func newPTYSession(id, name, command string, args []string, extraEnv map[string]string, limits cgroup.Limits) (*PTYSession, error) {
 	// Detect if this is a stream-json session
 	isStreamJSON := false
 	for i, arg := range args {
@@ -138,9 +185,14 @@ func NewPTYSessionWithEnv(id, name, command string, args []string, extraEnv map[
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
 	}
 
-	ptmx, err := pty.StartWithSize(cmd, &pty.Winsize{
-		Rows: 24,
-		Cols: 80,
+	var ptmx *os.File
+	group, warning, err := cgroup.Start(name, cmd, limits, func() error {
+		var err error
+		ptmx, err = pty.StartWithSize(cmd, &pty.Winsize{
+			Rows: 24,
+			Cols: 80,
+		})
+		return err
 	})
 	if err != nil {
 		cancel()
@@ -169,11 +221,16 @@ func NewPTYSessionWithEnv(id, name, command string, args []string, extraEnv map[
 		isStreamJSON:  isStreamJSON,
 		outputHistory: make([]byte, 0, 1024*1024), // Start with 1MB capacity
 		maxHistory:    10 * 1024 * 1024,           // 10MB max history
+		cgroup:        group,
+		limitsWarning: warning,
 	}
 
 	// Start I/O goroutines
 	go session.readLoop()
 	go session.writeLoop()
+	if group != nil {
+		go session.watchOOM(group)
+	}
 
 	return session, nil
 }
@@ -463,6 +520,13 @@ func (s *PTYSession) Close() error {
 		s.Command.Process.Kill()
 	}
 
+	// Removing the cgroup also kills anything the coder left running
+	if s.cgroup != nil {
+		s.oomKilled = s.oomKilled || s.cgroup.OOMKilled()
+		s.cgroup.Close()
+		s.cgroup = nil
+	}
+
 	// Close PTY
 	if s.PTY != nil {
 		s.PTY.Close()
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/standardbeagle/brummer/internal/cgroup"
 )
 
 // AICoderStatus represents the current state of an AI coder
@@ -106,6 +108,7 @@ type AICoderConfig struct {
 	WorkspaceBaseDir string
 	DefaultProvider  string
 	TimeoutMinutes   int
+	ResourceLimits   cgroup.Limits // enforced on coder sessions; zero fields are unlimited
 }
 
 // ProviderConfig is a simplified provider configuration
@@ -0,0 +1,100 @@
+// Package cgroup confines managed processes to transient cgroup v2 groups so that
+// memory, CPU and process-count limits are enforced by the kernel.
+package cgroup
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Limits are the resources a process tree may use. A zero field means unlimited.
+type Limits struct {
+	MemoryMB     int
+	CPUPercent   int // percent of one CPU
+	MaxProcesses int
+}
+
+// IsZero reports whether no limit is set
+func (l Limits) IsZero() bool {
+	return l.MemoryMB <= 0 && l.CPUPercent <= 0 && l.MaxProcesses <= 0
+}
+
+// String describes the limits for log messages
+func (l Limits) String() string {
+	var parts []string
+	if l.MemoryMB > 0 {
+		parts = append(parts, fmt.Sprintf("memory %d MB", l.MemoryMB))
+	}
+	if l.CPUPercent > 0 {
+		parts = append(parts, fmt.Sprintf("cpu %d%%", l.CPUPercent))
+	}
+	if l.MaxProcesses > 0 {
+		parts = append(parts, fmt.Sprintf("%d processes", l.MaxProcesses))
+	}
+	if len(parts) == 0 {
+		return "unlimited"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Start runs start, which must start cmd, confined to limits. Where the kernel
+// allows it cmd is created inside its group, so children it forks right away are
+// confined too; elsewhere it is moved in as soon as it has started. The group is
+// nil when cgroups could not be used, and warning then describes which limits
+// are not enforced. err is start's error; no group is left behind in that case.
+func Start(name string, cmd *exec.Cmd, limits Limits, start func() error) (group *Group, warning, err error) {
+	if limits.IsZero() {
+		return nil, nil, start()
+	}
+
+	group, cause := New(name, limits)
+	inside := false
+	if group != nil {
+		var release func()
+		if release, inside = group.StartInside(cmd); inside {
+			defer release()
+		}
+	}
+	if err := start(); err != nil {
+		if group != nil {
+			group.Close()
+		}
+		return nil, nil, err
+	}
+
+	if group != nil && !inside {
+		if cause = group.Add(cmd.Process.Pid); cause != nil {
+			group.Close()
+			group = nil
+		}
+	}
+	if group == nil {
+		return nil, Fallback(cmd.Process.Pid, limits, cause), nil
+	}
+	return group, nil, nil
+}
+
+// Fallback applies what it can of limits to a started process without cgroups,
+// which cause explains, and returns a warning describing which limits are not
+// enforced
+func Fallback(pid int, limits Limits, cause error) error {
+	return applyRlimits(pid, limits, cause)
+}
+
+// sanitizeName turns a script name into a valid cgroup directory name
+func sanitizeName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "process"
+	}
+	return b.String()
+}
@@ -0,0 +1,424 @@
+//go:build linux
+
+package cgroup
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	mountPoint      = "/sys/fs/cgroup"
+	cpuPeriod       = 100000 // microseconds
+	oomPollInterval = 2 * time.Second
+)
+
+var (
+	subtreeMu  sync.Mutex
+	subtreeCur *subtree // set up on first use, removed by Teardown
+	subtreeErr error
+	groupSeq   atomic.Int64
+
+	cloneOnce      sync.Once
+	cloneSupported bool
+)
+
+// subtree is what setupSubtree created below brummer's own cgroup
+type subtree struct {
+	base       string // brummer's cgroup when it started
+	root       string // brummer-<pid>
+	dir        string // where groups are created: root, or root/procs
+	supervisor string // leaf brummer moved itself into, if it had to
+	control    string // controllers enabled, e.g. "+memory +cpu"
+}
+
+// Group is a transient cgroup holding one managed process tree
+type Group struct {
+	path   string
+	limits Limits
+}
+
+// New creates a cgroup with the given limits under brummer's delegated subtree
+func New(name string, limits Limits) (*Group, error) {
+	subtreeMu.Lock()
+	if subtreeCur == nil && subtreeErr == nil {
+		subtreeCur, subtreeErr = setupSubtree()
+	}
+	tree, err := subtreeCur, subtreeErr
+	subtreeMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(tree.dir, fmt.Sprintf("%s-%d", sanitizeName(name), groupSeq.Add(1)))
+	if err := os.Mkdir(path, 0o755); err != nil {
+		return nil, fmt.Errorf("create cgroup: %w", err)
+	}
+
+	g := &Group{path: path, limits: limits}
+	if err := g.writeLimits(); err != nil {
+		g.Close()
+		return nil, err
+	}
+	return g, nil
+}
+
+// Path returns the cgroup's directory
+func (g *Group) Path() string {
+	return g.path
+}
+
+// Add moves a process into the group; children it forks afterwards stay in the group
+func (g *Group) Add(pid int) error {
+	return writeFile(g.path, "cgroup.procs", strconv.Itoa(pid))
+}
+
+// StartInside makes cmd start inside the group, so that nothing the process forks
+// before it could be moved escapes the limits. It reports false when processes
+// cannot be created in a cgroup here (before Linux 5.7, or when clone3 is filtered);
+// the process must then be moved in with Add once it has started. The returned
+// function releases the group's descriptor once cmd has started.
+func (g *Group) StartInside(cmd *exec.Cmd) (func(), bool) {
+	cloneOnce.Do(func() {
+		cloneSupported = g.probeCloneInto()
+	})
+	if !cloneSupported {
+		return nil, false
+	}
+	dir, err := os.Open(g.path)
+	if err != nil {
+		return nil, false
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = int(dir.Fd())
+	return func() { dir.Close() }, true
+}
+
+// probeCloneInto starts a trivial process inside the group to learn whether the
+// kernel and any seccomp filter allow creating processes in a cgroup. A failed
+// exec.Cmd cannot be started again, so this is found out before a real one.
+func (g *Group) probeCloneInto() bool {
+	path, err := exec.LookPath("true")
+	if err != nil {
+		return false
+	}
+	dir, err := os.Open(g.path)
+	if err != nil {
+		return false
+	}
+	defer dir.Close()
+	probe := exec.Command(path)
+	probe.SysProcAttr = &syscall.SysProcAttr{UseCgroupFD: true, CgroupFD: int(dir.Fd())}
+	return probe.Run() == nil
+}
+
+// OOMKilled reports whether the kernel killed a process in the group for exceeding
+// its memory limit
+func (g *Group) OOMKilled() bool {
+	return g.oomKills() > 0
+}
+
+// oomKills counts the processes the kernel killed for exceeding the memory limit
+func (g *Group) oomKills() int {
+	f, err := os.Open(filepath.Join(g.path, "memory.events"))
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	kills := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "oom_kill" {
+			kills, _ = strconv.Atoi(fields[1])
+		}
+	}
+	return kills
+}
+
+// WatchOOM calls onKill with the number of new kills each time the kernel kills a
+// process in the group for exceeding its memory limit, until done is closed. The
+// kernel signals changes to memory.events through inotify; where that is not
+// available the file is polled.
+func (g *Group) WatchOOM(done <-chan struct{}, onKill func(kills int)) {
+	events := filepath.Join(g.path, "memory.events")
+	changed := make(chan struct{}, 1)
+
+	if fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK); err == nil {
+		watcher := os.NewFile(uintptr(fd), "inotify")
+		if _, err := unix.InotifyAddWatch(fd, events, unix.IN_MODIFY); err == nil {
+			defer watcher.Close() // unblocks the reader
+			go func() {
+				buf := make([]byte, 4096)
+				for {
+					if _, err := watcher.Read(buf); err != nil {
+						return
+					}
+					select {
+					case changed <- struct{}{}:
+					default:
+					}
+				}
+			}()
+		} else {
+			watcher.Close()
+		}
+	}
+
+	poll := time.NewTicker(oomPollInterval)
+	defer poll.Stop()
+
+	seen := g.oomKills()
+	for {
+		select {
+		case <-done:
+			return
+		case <-changed:
+		case <-poll.C:
+		}
+		if kills := g.oomKills(); kills > seen {
+			onKill(kills - seen)
+			seen = kills
+		}
+	}
+}
+
+// Close kills anything left in the group and removes it
+func (g *Group) Close() error {
+	// cgroup.kill exists from Linux 5.14; older kernels just fail the rmdir if busy
+	_ = writeFile(g.path, "cgroup.kill", "1")
+
+	var err error
+	for i := 0; i < 50; i++ {
+		if err = os.Remove(g.path); err == nil || errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return fmt.Errorf("remove cgroup: %w", err)
+}
+
+func (g *Group) writeLimits() error {
+	if g.limits.MemoryMB > 0 {
+		bytes := int64(g.limits.MemoryMB) * 1024 * 1024
+		if err := writeFile(g.path, "memory.max", strconv.FormatInt(bytes, 10)); err != nil {
+			return err
+		}
+		// Hit the limit instead of swapping, and kill the whole tree when it is hit
+		_ = writeFile(g.path, "memory.swap.max", "0")
+		_ = writeFile(g.path, "memory.oom.group", "1")
+	}
+	if g.limits.CPUPercent > 0 {
+		quota := g.limits.CPUPercent * cpuPeriod / 100
+		if err := writeFile(g.path, "cpu.max", fmt.Sprintf("%d %d", quota, cpuPeriod)); err != nil {
+			return err
+		}
+	}
+	if g.limits.MaxProcesses > 0 {
+		if err := writeFile(g.path, "pids.max", strconv.Itoa(g.limits.MaxProcesses)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setupSubtree prepares a cgroup below brummer's own in which managed processes get
+// their groups. cgroups v2 only lets a group with no processes of its own enable
+// controllers for its children, so when brummer's group is in use brummer moves
+// itself into a leaf first. This only succeeds when the group has been delegated,
+// e.g. with systemd-run --user --scope -p Delegate=yes.
+func setupSubtree() (*subtree, error) {
+	if _, err := os.Stat(filepath.Join(mountPoint, "cgroup.controllers")); err != nil {
+		return nil, fmt.Errorf("cgroup v2 is not mounted at %s", mountPoint)
+	}
+
+	own, err := ownCgroup()
+	if err != nil {
+		return nil, err
+	}
+	base := filepath.Join(mountPoint, own)
+
+	controllers, err := os.ReadFile(filepath.Join(base, "cgroup.controllers"))
+	if err != nil {
+		return nil, err
+	}
+	var enable []string
+	for _, c := range []string{"memory", "cpu", "pids"} {
+		if strings.Contains(" "+string(controllers)+" ", " "+c+" ") {
+			enable = append(enable, "+"+c)
+		}
+	}
+	if len(enable) == 0 {
+		return nil, fmt.Errorf("no memory, cpu or pids controller is delegated to %s", base)
+	}
+	control := strings.Join(enable, " ")
+
+	root := filepath.Join(base, fmt.Sprintf("brummer-%d", os.Getpid()))
+	if err := os.Mkdir(root, 0o755); err != nil && !errors.Is(err, os.ErrExist) {
+		return nil, fmt.Errorf("create cgroup subtree: %w", err)
+	}
+
+	if err := writeFile(base, "cgroup.subtree_control", control); err != nil {
+		if !errors.Is(err, syscall.EBUSY) {
+			os.Remove(root)
+			return nil, fmt.Errorf("enable controllers in %s: %w", base, err)
+		}
+
+		supervisor := filepath.Join(root, "supervisor")
+		if err := os.Mkdir(supervisor, 0o755); err != nil && !errors.Is(err, os.ErrExist) {
+			os.Remove(root)
+			return nil, fmt.Errorf("create supervisor cgroup: %w", err)
+		}
+		if err := writeFile(supervisor, "cgroup.procs", strconv.Itoa(os.Getpid())); err != nil {
+			os.Remove(supervisor)
+			os.Remove(root)
+			return nil, fmt.Errorf("move brummer into %s: %w", supervisor, err)
+		}
+		if err := writeFile(base, "cgroup.subtree_control", control); err != nil {
+			return nil, fmt.Errorf("enable controllers in %s: %w", base, err)
+		}
+		// The subtree itself must be free of processes too
+		dir, err := nestedSubtree(root, control)
+		if err != nil {
+			return nil, err
+		}
+		return &subtree{base: base, root: root, dir: dir, supervisor: supervisor, control: control}, nil
+	}
+
+	if err := writeFile(root, "cgroup.subtree_control", control); err != nil {
+		return nil, fmt.Errorf("enable controllers in %s: %w", root, err)
+	}
+	return &subtree{base: base, root: root, dir: root, control: control}, nil
+}
+
+// nestedSubtree creates the group for managed processes next to the supervisor leaf
+func nestedSubtree(root, control string) (string, error) {
+	procs := filepath.Join(root, "procs")
+	if err := os.Mkdir(procs, 0o755); err != nil && !errors.Is(err, os.ErrExist) {
+		return "", fmt.Errorf("create cgroup subtree: %w", err)
+	}
+	if err := writeFile(root, "cgroup.subtree_control", control); err != nil {
+		return "", fmt.Errorf("enable controllers in %s: %w", root, err)
+	}
+	if err := writeFile(procs, "cgroup.subtree_control", control); err != nil {
+		return "", fmt.Errorf("enable controllers in %s: %w", procs, err)
+	}
+	return procs, nil
+}
+
+// Teardown removes the cgroups New set up below brummer's own, moving brummer back
+// to the group it started in. It waits briefly for the groups of exiting processes
+// to be closed and leaves everything in place while any is still in use.
+func Teardown() error {
+	subtreeMu.Lock()
+	defer subtreeMu.Unlock()
+	tree := subtreeCur
+	subtreeErr = nil // a later New may set up again
+	if tree == nil {
+		return nil
+	}
+
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(20 * time.Millisecond) {
+		groups := childGroups(tree.dir)
+		if groups == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%d cgroups under %s are still in use", groups, tree.dir)
+		}
+	}
+
+	if tree.dir != tree.root {
+		os.Remove(tree.dir)
+	}
+	if tree.supervisor != "" {
+		// The controllers were enabled in brummer's group only after brummer left it;
+		// disable them again so brummer may return
+		disable := strings.ReplaceAll(tree.control, "+", "-")
+		_ = writeFile(tree.base, "cgroup.subtree_control", disable)
+		if err := writeFile(tree.base, "cgroup.procs", strconv.Itoa(os.Getpid())); err != nil {
+			return fmt.Errorf("move brummer back into %s: %w", tree.base, err)
+		}
+		if err := os.Remove(tree.supervisor); err != nil {
+			return fmt.Errorf("remove supervisor cgroup: %w", err)
+		}
+	}
+	if err := os.Remove(tree.root); err != nil {
+		return fmt.Errorf("remove cgroup subtree: %w", err)
+	}
+	subtreeCur = nil
+	return nil
+}
+
+// childGroups counts the cgroups directly below dir
+func childGroups(dir string) int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+	n := 0
+	for _, entry := range entries {
+		if entry.IsDir() {
+			n++
+		}
+	}
+	return n
+}
+
+// ownCgroup returns brummer's cgroup v2 path relative to the mount point
+func ownCgroup() (string, error) {
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(line, "0::"); ok {
+			return rest, nil
+		}
+	}
+	return "", fmt.Errorf("process is not in a cgroup v2 hierarchy")
+}
+
+func writeFile(dir, name, value string) error {
+	return os.WriteFile(filepath.Join(dir, name), []byte(value), 0o644)
+}
+
+// applyRlimits enforces what it can without cgroups. RLIMIT_DATA caps the heap of
+// each process; CPU share and process count have no per-tree rlimit equivalent.
+func applyRlimits(pid int, limits Limits, cause error) error {
+	var unenforced []string
+	if limits.MemoryMB > 0 {
+		bytes := uint64(limits.MemoryMB) * 1024 * 1024
+		if err := unix.Prlimit(pid, unix.RLIMIT_DATA, &unix.Rlimit{Cur: bytes, Max: bytes}, nil); err != nil {
+			unenforced = append(unenforced, "memory")
+		}
+	}
+	if limits.CPUPercent > 0 {
+		unenforced = append(unenforced, "cpu")
+	}
+	if limits.MaxProcesses > 0 {
+		unenforced = append(unenforced, "process count")
+	}
+
+	msg := fmt.Sprintf("cgroups unavailable (%v); using rlimits", cause)
+	if len(unenforced) > 0 {
+		msg += fmt.Sprintf(", %s limit not enforced", strings.Join(unenforced, " and "))
+	}
+	return errors.New(msg)
+}
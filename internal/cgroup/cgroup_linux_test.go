@@ -0,0 +1,70 @@
+//go:build linux
+
+package cgroup
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStartInside tests that a process starts inside its group where the kernel
+// allows it, and that Teardown removes brummer's subtree once the group is closed
+func TestStartInside(t *testing.T) {
+	group, err := New("inside", Limits{MaxProcesses: 50})
+	if err != nil {
+		t.Skipf("cgroups unavailable: %v", err)
+	}
+
+	cmd := exec.Command("sleep", "5")
+	release, inside := group.StartInside(cmd)
+	require.NoError(t, cmd.Start())
+	if inside {
+		release()
+	} else {
+		require.NoError(t, group.Add(cmd.Process.Pid))
+	}
+	membership, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(cmd.Process.Pid), "cgroup"))
+	require.NoError(t, err)
+	assert.Contains(t, string(membership), filepath.Base(group.Path()))
+
+	cmd.Process.Kill()
+	cmd.Wait()
+	require.NoError(t, group.Close())
+
+	subtreeMu.Lock()
+	root := subtreeCur.root
+	subtreeMu.Unlock()
+	require.NoError(t, Teardown())
+	assert.NoDirExists(t, root)
+}
+
+// TestWatchOOM tests that new kills in memory.events are reported as they are counted
+func TestWatchOOM(t *testing.T) {
+	dir := t.TempDir()
+	events := filepath.Join(dir, "memory.events")
+	require.NoError(t, os.WriteFile(events, []byte("low 0\nhigh 0\nmax 3\noom 1\noom_kill 1\n"), 0o644))
+	group := &Group{path: dir}
+
+	kills := make(chan int, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go group.WatchOOM(done, func(n int) { kills <- n })
+
+	time.Sleep(50 * time.Millisecond) // let the watcher read the starting count
+	require.NoError(t, os.WriteFile(events, []byte("low 0\nhigh 0\nmax 5\noom 2\noom_kill 3\n"), 0o644))
+
+	select {
+	case n := <-kills:
+		assert.Equal(t, 2, n)
+	case <-time.After(time.Second):
+		t.Fatal("OOM kill was not reported")
+	}
+	assert.True(t, group.OOMKilled())
+}
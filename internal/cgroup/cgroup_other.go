@@ -0,0 +1,42 @@
+//go:build !linux
+
+package cgroup
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// Group is a transient cgroup holding one managed process tree
+type Group struct{}
+
+// New always fails; cgroups are Linux-only
+func New(name string, limits Limits) (*Group, error) {
+	return nil, fmt.Errorf("cgroups are not supported on %s", runtime.GOOS)
+}
+
+// Path returns the cgroup's directory
+func (g *Group) Path() string { return "" }
+
+// Add moves a process into the group
+func (g *Group) Add(pid int) error { return nil }
+
+// StartInside makes cmd start inside the group; never possible without cgroups
+func (g *Group) StartInside(cmd *exec.Cmd) (func(), bool) { return nil, false }
+
+// OOMKilled reports whether the kernel killed a process in the group for exceeding its memory limit
+func (g *Group) OOMKilled() bool { return false }
+
+// WatchOOM returns once done is closed; without cgroups there are no OOM kills to report
+func (g *Group) WatchOOM(done <-chan struct{}, onKill func(kills int)) { <-done }
+
+// Close kills anything left in the group and removes it
+func (g *Group) Close() error { return nil }
+
+func applyRlimits(pid int, limits Limits, cause error) error {
+	return fmt.Errorf("%v; resource limits (%s) are not enforced", cause, limits)
+}
+
+// Teardown has nothing to remove without cgroups
+func Teardown() error { return nil }
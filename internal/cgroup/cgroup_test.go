@@ -0,0 +1,41 @@
+package cgroup
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimits(t *testing.T) {
+	assert.True(t, Limits{}.IsZero())
+	assert.Equal(t, "unlimited", Limits{}.String())
+
+	limits := Limits{MemoryMB: 256, CPUPercent: 50, MaxProcesses: 20}
+	assert.False(t, limits.IsZero())
+	assert.Equal(t, "memory 256 MB, cpu 50%, 20 processes", limits.String())
+}
+
+func TestSanitizeName(t *testing.T) {
+	assert.Equal(t, "db_migrate", sanitizeName("db:migrate"))
+	assert.Equal(t, "web_dev_", sanitizeName("web dev/"))
+	assert.Equal(t, "process", sanitizeName(""))
+}
+
+// TestStart tests that limits are applied through whichever mechanism the host allows
+func TestStart(t *testing.T) {
+	cmd := exec.Command("sleep", "5")
+	group, warning, err := Start("sleeper", cmd, Limits{MemoryMB: 256}, cmd.Start)
+	require.NoError(t, err)
+	defer cmd.Process.Kill()
+	if group == nil {
+		require.Error(t, warning, "a fallback must explain itself")
+		t.Logf("fell back: %v", warning)
+		return
+	}
+	defer group.Close()
+
+	assert.NoError(t, warning)
+	assert.False(t, group.OOMKilled())
+}
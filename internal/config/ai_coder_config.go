@@ -1,5 +1,7 @@
 package config
 
+import "github.com/standardbeagle/brummer/internal/cgroup"
+
 // AICoderConfig holds all configuration for AI coder functionality
 type AICoderConfig struct {
 	Enabled           *bool                      `toml:"enabled,omitempty"`
@@ -248,6 +250,25 @@ func (r *ResourceLimits) GetMaxProcesses() int {
 	return *r.MaxProcesses
 }
 
+// CgroupLimits returns the limits to enforce on a process tree. Unlike the getters it
+// has no defaults: only limits that were configured are enforced.
+func (r *ResourceLimits) CgroupLimits() cgroup.Limits {
+	var limits cgroup.Limits
+	if r == nil {
+		return limits
+	}
+	if r.MaxMemoryMB != nil {
+		limits.MemoryMB = *r.MaxMemoryMB
+	}
+	if r.MaxCPUPercent != nil {
+		limits.CPUPercent = *r.MaxCPUPercent
+	}
+	if r.MaxProcesses != nil {
+		limits.MaxProcesses = *r.MaxProcesses
+	}
+	return limits
+}
+
 func (r *ResourceLimits) GetMaxFilesPerCoder() int {
 	if r == nil || r.MaxFilesPerCoder == nil {
 		return 100 // default
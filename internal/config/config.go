@@ -28,8 +28,30 @@ type Config struct {
 	StandardProxy *bool   `toml:"standard_proxy,omitempty"`
 	NoProxy       *bool   `toml:"no_proxy,omitempty"`
 
+	// Process Settings
+	PersistProcesses *bool `toml:"persist_processes,omitempty"`
+	KillDevProcesses *bool `toml:"kill_dev_processes,omitempty"`
+
 	// AI Coder Settings
 	AICoders *AICoderConfig `toml:"ai_coders,omitempty"`
+
+	// Environment Settings
+	Environment *EnvironmentConfig `toml:"environment,omitempty"`
+
+	// Port assignment for managed processes
+	Ports *PortsConfig `toml:"ports,omitempty"`
+
+	// On-disk log history
+	Logs *LogsConfig `toml:"logs,omitempty"`
+
+	// Per-script settings keyed by script name
+	Scripts map[string]*ScriptConfig `toml:"scripts,omitempty"`
+
+	// Named commands keyed by service name
+	Services map[string]*ServiceConfig `toml:"services,omitempty"`
+
+	// Recurring commands keyed by schedule name
+	Schedules map[string]*ScheduleConfig `toml:"schedules,omitempty"`
 }
 
 // ConfigWithSources tracks where each config value comes from
@@ -123,14 +145,45 @@ func Load() (*Config, error) {
 		if fileCfg.NoProxy != nil {
 			cfg.NoProxy = fileCfg.NoProxy
 		}
+		if fileCfg.PersistProcesses != nil {
+			cfg.PersistProcesses = fileCfg.PersistProcesses
+		}
+		if fileCfg.KillDevProcesses != nil {
+			cfg.KillDevProcesses = fileCfg.KillDevProcesses
+		}
 		if fileCfg.AICoders != nil {
 			cfg.AICoders = fileCfg.AICoders
 		}
+		if fileCfg.Environment != nil {
+			cfg.Environment = fileCfg.Environment
+		}
+		if fileCfg.Ports != nil {
+			cfg.Ports = fileCfg.Ports
+		}
+		if fileCfg.Logs != nil {
+			cfg.Logs = fileCfg.Logs
+		}
+		cfg.mergeScripts(fileCfg.Scripts)
+		cfg.mergeServices(fileCfg.Services)
+		cfg.mergeSchedules(fileCfg.Schedules)
 	}
 
 	return cfg, nil
 }
 
+// mergeScripts overlays per-script settings; a more specific file replaces a script's whole entry
+func (c *Config) mergeScripts(scripts map[string]*ScriptConfig) {
+	if len(scripts) == 0 {
+		return
+	}
+	if c.Scripts == nil {
+		c.Scripts = make(map[string]*ScriptConfig)
+	}
+	for name, sc := range scripts {
+		c.Scripts[name] = sc
+	}
+}
+
 // LoadWithSources loads the configuration with source tracking
 func LoadWithSources() (*ConfigWithSources, error) {
 	paths, err := getConfigPaths()
@@ -193,10 +246,42 @@ func LoadWithSources() (*ConfigWithSources, error) {
 			cfg.NoProxy = fileCfg.NoProxy
 			cfg.Sources["no_proxy"] = path
 		}
+		if fileCfg.PersistProcesses != nil {
+			cfg.PersistProcesses = fileCfg.PersistProcesses
+			cfg.Sources["persist_processes"] = path
+		}
+		if fileCfg.KillDevProcesses != nil {
+			cfg.KillDevProcesses = fileCfg.KillDevProcesses
+			cfg.Sources["kill_dev_processes"] = path
+		}
 		if fileCfg.AICoders != nil {
 			cfg.AICoders = fileCfg.AICoders
 			cfg.Sources["ai_coders"] = path
 		}
+		if fileCfg.Environment != nil {
+			cfg.Environment = fileCfg.Environment
+			cfg.Sources["environment"] = path
+		}
+		if fileCfg.Ports != nil {
+			cfg.Ports = fileCfg.Ports
+			cfg.Sources["ports"] = path
+		}
+		if fileCfg.Logs != nil {
+			cfg.Logs = fileCfg.Logs
+			cfg.Sources["logs"] = path
+		}
+		for name := range fileCfg.Scripts {
+			cfg.Sources["scripts."+name] = path
+		}
+		cfg.mergeScripts(fileCfg.Scripts)
+		for name := range fileCfg.Services {
+			cfg.Sources["services."+name] = path
+		}
+		cfg.mergeServices(fileCfg.Services)
+		for name := range fileCfg.Schedules {
+			cfg.Sources["schedules."+name] = path
+		}
+		cfg.mergeSchedules(fileCfg.Schedules)
 	}
 
 	return cfg, nil
@@ -296,6 +381,25 @@ func (c *Config) GetNoProxy() bool {
 	return false // default
 }
 
+// GetPersistProcesses reports whether process metadata and output are kept on disk
+// so a restarted brummer can reattach to running processes
+func (c *Config) GetPersistProcesses() bool {
+	if c.PersistProcesses != nil {
+		return *c.PersistProcesses
+	}
+	return false // default
+}
+
+// GetKillDevProcesses reports whether processes on common dev ports (3000-3009) and
+// with dev-server command lines are killed when scripts stop, even if brummer did
+// not start them
+func (c *Config) GetKillDevProcesses() bool {
+	if c.KillDevProcesses != nil {
+		return *c.KillDevProcesses
+	}
+	return true // default
+}
+
 func (c *Config) GetAICoderConfig() aicoder.AICoderConfig {
 	aiConfig := c.AICoders
 	if aiConfig == nil {
@@ -308,6 +412,7 @@ func (c *Config) GetAICoderConfig() aicoder.AICoderConfig {
 		WorkspaceBaseDir: aiConfig.GetWorkspaceBaseDir(),
 		DefaultProvider:  aiConfig.GetDefaultProvider(),
 		TimeoutMinutes:   aiConfig.GetTimeoutMinutes(),
+		ResourceLimits:   aiConfig.ResourceLimits.CgroupLimits(),
 	}
 }
 
@@ -410,6 +515,83 @@ func (c *ConfigWithSources) DisplaySettingsWithSources() string {
 	} else {
 		lines = append(lines, "# no_proxy = false  # default")
 	}
+	lines = append(lines, "")
+
+	// Process Settings
+	lines = append(lines, "# Process Settings")
+	if c.PersistProcesses != nil {
+		if source, ok := c.Sources["persist_processes"]; ok {
+			lines = append(lines, fmt.Sprintf("# Source: %s", shortenPath(source)))
+		}
+		lines = append(lines, fmt.Sprintf("persist_processes = %t", *c.PersistProcesses))
+	} else {
+		lines = append(lines, "# persist_processes = false  # default")
+	}
+
+	if c.KillDevProcesses != nil {
+		if source, ok := c.Sources["kill_dev_processes"]; ok {
+			lines = append(lines, fmt.Sprintf("# Source: %s", shortenPath(source)))
+		}
+		lines = append(lines, fmt.Sprintf("kill_dev_processes = %t", *c.KillDevProcesses))
+	} else {
+		lines = append(lines, "# kill_dev_processes = true  # default")
+	}
+	lines = append(lines, "")
+
+	// Port Assignment
+	lines = append(lines, "# Port Assignment")
+	if source, ok := c.Sources["ports"]; ok {
+		lines = append(lines, fmt.Sprintf("# Source: %s", shortenPath(source)))
+	}
+	lines = append(lines, "[ports]")
+	if c.Ports != nil && c.Ports.Assign != nil {
+		lines = append(lines, fmt.Sprintf("assign = %t", *c.Ports.Assign))
+	} else {
+		lines = append(lines, "# assign = false  # default")
+	}
+	if c.Ports != nil && c.Ports.RangeStart != nil {
+		lines = append(lines, fmt.Sprintf("range_start = %d", *c.Ports.RangeStart))
+	} else {
+		lines = append(lines, "# range_start = 4000  # default")
+	}
+	if c.Ports != nil && c.Ports.RangeEnd != nil {
+		lines = append(lines, fmt.Sprintf("range_end = %d", *c.Ports.RangeEnd))
+	} else {
+		lines = append(lines, "# range_end = 4999  # default")
+	}
+	lines = append(lines, "")
+
+	// Log History
+	lines = append(lines, "# Log History")
+	if source, ok := c.Sources["logs"]; ok {
+		lines = append(lines, fmt.Sprintf("# Source: %s", shortenPath(source)))
+	}
+	lines = append(lines, "[logs]")
+	if c.Logs != nil && c.Logs.Persist != nil {
+		lines = append(lines, fmt.Sprintf("persist = %t", *c.Logs.Persist))
+	} else {
+		lines = append(lines, "# persist = true  # default")
+	}
+	if c.Logs != nil && c.Logs.Dir != nil {
+		lines = append(lines, fmt.Sprintf("dir = %q", *c.Logs.Dir))
+	} else {
+		lines = append(lines, "# dir = \"\"  # default: the user cache directory")
+	}
+	if c.Logs != nil && c.Logs.MaxSizeMB != nil {
+		lines = append(lines, fmt.Sprintf("max_size_mb = %d", *c.Logs.MaxSizeMB))
+	} else {
+		lines = append(lines, "# max_size_mb = 200  # default")
+	}
+	if c.Logs != nil && c.Logs.MaxAgeHours != nil {
+		lines = append(lines, fmt.Sprintf("max_age_hours = %d", *c.Logs.MaxAgeHours))
+	} else {
+		lines = append(lines, "# max_age_hours = 72  # default")
+	}
+	if c.Logs != nil && c.Logs.SegmentSizeMB != nil {
+		lines = append(lines, fmt.Sprintf("segment_size_mb = %d", *c.Logs.SegmentSizeMB))
+	} else {
+		lines = append(lines, "# segment_size_mb = 8  # default")
+	}
 
 	return strings.Join(lines, "\n")
 }
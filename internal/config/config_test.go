@@ -133,3 +133,103 @@ func TestConfigDefaults(t *testing.T) {
 		t.Errorf("Expected default no_proxy false, got %t", cfg.GetNoProxy())
 	}
 }
+
+func TestScriptConfigRestartPolicy(t *testing.T) {
+	tmpDir := t.TempDir()
+	subDir := filepath.Join(tmpDir, "project")
+	os.MkdirAll(subDir, 0755)
+
+	parentContent := `[scripts.api]
+restart = "always"
+
+[scripts.worker]
+restart = "on-failure"
+max_retries = 3`
+	os.WriteFile(filepath.Join(tmpDir, ".brum.toml"), []byte(parentContent), 0644)
+
+	subContent := `[scripts.api]
+restart = "on-failure"
+restart_delay_ms = 250`
+	os.WriteFile(filepath.Join(subDir, ".brum.toml"), []byte(subContent), 0644)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(subDir)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	api := cfg.GetScriptConfig("api")
+	if api.GetRestartPolicy() != RestartOnFailure {
+		t.Errorf("Expected api policy on-failure, got %s", api.GetRestartPolicy())
+	}
+	if api.GetRestartDelay().Milliseconds() != 250 {
+		t.Errorf("Expected api restart delay 250ms, got %v", api.GetRestartDelay())
+	}
+
+	worker := cfg.GetScriptConfig("worker")
+	if worker.GetMaxRetries() != 3 {
+		t.Errorf("Expected worker max_retries 3, got %d", worker.GetMaxRetries())
+	}
+
+	// Unconfigured scripts fall back to never restarting
+	if cfg.GetScriptConfig("web").GetRestartPolicy() != RestartNever {
+		t.Errorf("Expected default policy never")
+	}
+}
+
+func TestServiceConfigOverride(t *testing.T) {
+	tmpDir := t.TempDir()
+	subDir := filepath.Join(tmpDir, "project")
+	os.MkdirAll(subDir, 0755)
+
+	parentContent := `[services.api]
+command = "go"
+args = ["run", "./cmd/api"]
+group = "backend"
+
+[services.db]
+command = "postgres -D data"
+group = "backend"`
+	os.WriteFile(filepath.Join(tmpDir, ".brum.toml"), []byte(parentContent), 0644)
+
+	subContent := `[services.api]
+command = "air"
+cwd = "api"
+
+[services.api.env]
+PORT = "8080"`
+	os.WriteFile(filepath.Join(subDir, ".brum.toml"), []byte(subContent), 0644)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(subDir)
+
+	cfg, err := LoadWithSources()
+	if err != nil {
+		t.Fatalf("LoadWithSources() failed: %v", err)
+	}
+
+	// The nearer file replaces the whole entry
+	api := cfg.GetServiceConfig("api")
+	if api.Command != "air" || len(api.Args) != 0 {
+		t.Errorf("Expected api command air without args, got %q %v", api.Command, api.Args)
+	}
+	if api.GetCwd() != "api" || api.GetEnv()["PORT"] != "8080" {
+		t.Errorf("Expected api cwd and env from the project file, got %q %v", api.GetCwd(), api.GetEnv())
+	}
+	if api.GetGroup() != "" {
+		t.Errorf("Expected api group to be replaced, got %q", api.GetGroup())
+	}
+	if cfg.GetServiceConfig("db").GetGroup() != "backend" {
+		t.Errorf("Expected db group backend, got %q", cfg.GetServiceConfig("db").GetGroup())
+	}
+	if cfg.Sources["services.api"] != filepath.Join(subDir, ".brum.toml") {
+		t.Errorf("Expected services.api source in the project file, got %q", cfg.Sources["services.api"])
+	}
+	if cfg.GetServiceConfig("missing") != nil {
+		t.Errorf("Expected no config for an undeclared service")
+	}
+}
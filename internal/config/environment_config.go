@@ -0,0 +1,31 @@
+package config
+
+// EnvironmentConfig controls how process environments are built from .env files
+type EnvironmentConfig struct {
+	Profile    *string  `toml:"profile,omitempty"`     // selects .env.<profile>
+	SecretKeys []string `toml:"secret_keys,omitempty"` // extra variable names to mask
+	Override   *bool    `toml:"override,omitempty"`    // .env files win over the system environment
+}
+
+// Environment helpers
+
+func (c *Config) GetEnvProfile() string {
+	if c == nil || c.Environment == nil || c.Environment.Profile == nil {
+		return "" // default: only .env and .env.local
+	}
+	return *c.Environment.Profile
+}
+
+func (c *Config) GetEnvOverride() bool {
+	if c == nil || c.Environment == nil || c.Environment.Override == nil {
+		return false // default: variables already set in the shell win
+	}
+	return *c.Environment.Override
+}
+
+func (c *Config) GetSecretKeys() []string {
+	if c == nil || c.Environment == nil {
+		return nil
+	}
+	return c.Environment.SecretKeys
+}
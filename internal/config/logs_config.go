@@ -0,0 +1,59 @@
+package config
+
+import "time"
+
+// LogsConfig controls the log history brummer keeps on disk. The in-memory store
+// holds the most recent lines; older ones are read back from append-only segment
+// files, which are deleted once the history for a project grows past MaxSizeMB or a
+// segment is older than MaxAgeHours.
+type LogsConfig struct {
+	Persist       *bool   `toml:"persist,omitempty"`         // keep history on disk
+	Dir           *string `toml:"dir,omitempty"`             // where history is kept; defaults to the user cache directory
+	MaxSizeMB     *int    `toml:"max_size_mb,omitempty"`     // history kept per project
+	MaxAgeHours   *int    `toml:"max_age_hours,omitempty"`   // how long a segment is kept
+	SegmentSizeMB *int    `toml:"segment_size_mb,omitempty"` // size at which a new segment is started
+}
+
+// Logs helpers
+
+func (c *Config) GetLogs() *LogsConfig {
+	if c == nil {
+		return nil
+	}
+	return c.Logs
+}
+
+func (l *LogsConfig) GetPersist() bool {
+	if l == nil || l.Persist == nil {
+		return true // default
+	}
+	return *l.Persist
+}
+
+func (l *LogsConfig) GetDir() string {
+	if l == nil || l.Dir == nil {
+		return "" // default
+	}
+	return *l.Dir
+}
+
+func (l *LogsConfig) GetMaxSize() int64 {
+	if l == nil || l.MaxSizeMB == nil {
+		return 200 << 20 // default
+	}
+	return int64(*l.MaxSizeMB) << 20
+}
+
+func (l *LogsConfig) GetMaxAge() time.Duration {
+	if l == nil || l.MaxAgeHours == nil {
+		return 72 * time.Hour // default
+	}
+	return time.Duration(*l.MaxAgeHours) * time.Hour
+}
+
+func (l *LogsConfig) GetSegmentSize() int64 {
+	if l == nil || l.SegmentSizeMB == nil {
+		return 8 << 20 // default
+	}
+	return int64(*l.SegmentSizeMB) << 20
+}
@@ -0,0 +1,58 @@
+package config
+
+// PortsConfig controls the ports brummer hands to the processes it starts. Each
+// process that gets one keeps the same port across restarts, and instances on the
+// same machine never hand out the same port.
+type PortsConfig struct {
+	Assign     *bool `toml:"assign,omitempty"`      // give every service a port; scripts opt in with assign_port
+	RangeStart *int  `toml:"range_start,omitempty"` // first port handed out
+	RangeEnd   *int  `toml:"range_end,omitempty"`   // last port handed out
+}
+
+// Ports helpers
+
+func (c *Config) GetPorts() *PortsConfig {
+	if c == nil {
+		return nil
+	}
+	return c.Ports
+}
+
+func (p *PortsConfig) GetAssign() bool {
+	if p == nil || p.Assign == nil {
+		return false // default
+	}
+	return *p.Assign
+}
+
+func (p *PortsConfig) GetRangeStart() int {
+	if p == nil || p.RangeStart == nil {
+		return 4000 // default
+	}
+	return *p.RangeStart
+}
+
+func (p *PortsConfig) GetRangeEnd() int {
+	if p == nil || p.RangeEnd == nil {
+		return 4999 // default
+	}
+	return *p.RangeEnd
+}
+
+// Per-script port helpers
+
+// GetAssignPort reports whether the script's own setting asks for a port, and
+// whether it has one at all
+func (s *ScriptConfig) GetAssignPort() (assign bool, set bool) {
+	if s == nil || s.AssignPort == nil {
+		return false, false
+	}
+	return *s.AssignPort, true
+}
+
+func (s *ScriptConfig) GetPortEnv() string {
+	if s == nil || s.PortEnv == nil || *s.PortEnv == "" {
+		return "PORT" // default
+	}
+	return *s.PortEnv
+}
@@ -0,0 +1,47 @@
+package config
+
+// ScheduleConfig declares a recurring command under [schedules.<name>] in .brum.toml.
+// Exactly one of Cron and Every should be set.
+type ScheduleConfig struct {
+	Command string   `toml:"command"`         // executable, or a shell command line when args is empty
+	Args    []string `toml:"args,omitempty"`  // arguments passed to command without a shell
+	Cron    *string  `toml:"cron,omitempty"`  // five-field cron expression or a macro such as @daily
+	Every   *string  `toml:"every,omitempty"` // fixed interval such as "10m"
+	Enabled *bool    `toml:"enabled,omitempty"`
+}
+
+// mergeSchedules overlays schedule declarations; a more specific file replaces a schedule's whole entry
+func (c *Config) mergeSchedules(schedules map[string]*ScheduleConfig) {
+	if len(schedules) == 0 {
+		return
+	}
+	if c.Schedules == nil {
+		c.Schedules = make(map[string]*ScheduleConfig)
+	}
+	for name, sc := range schedules {
+		c.Schedules[name] = sc
+	}
+}
+
+// Schedule helpers
+
+func (s *ScheduleConfig) GetCron() string {
+	if s == nil || s.Cron == nil {
+		return ""
+	}
+	return *s.Cron
+}
+
+func (s *ScheduleConfig) GetEvery() string {
+	if s == nil || s.Every == nil {
+		return ""
+	}
+	return *s.Every
+}
+
+func (s *ScheduleConfig) GetEnabled() bool {
+	if s == nil || s.Enabled == nil {
+		return true // default
+	}
+	return *s.Enabled
+}
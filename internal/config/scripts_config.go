@@ -0,0 +1,400 @@
+package config
+
+import (
+	"time"
+
+	"github.com/standardbeagle/brummer/internal/cgroup"
+)
+
+// RestartPolicy controls whether a managed process is restarted after it exits
+type RestartPolicy string
+
+const (
+	RestartNever     RestartPolicy = "never"
+	RestartOnFailure RestartPolicy = "on-failure"
+	RestartAlways    RestartPolicy = "always"
+)
+
+// ScriptConfig holds per-script settings declared under [scripts.<name>] in .brum.toml
+type ScriptConfig struct {
+	// Restart settings
+	Restart           *string `toml:"restart,omitempty"`              // never, on-failure, always
+	MaxRetries        *int    `toml:"max_retries,omitempty"`          // restarts allowed before entering crash loop
+	RestartDelayMs    *int    `toml:"restart_delay_ms,omitempty"`     // initial backoff delay
+	RestartMaxDelayMs *int    `toml:"restart_max_delay_ms,omitempty"` // backoff ceiling
+	StableAfterSecs   *int    `toml:"stable_after_seconds,omitempty"` // uptime that resets the retry budget
+
+	// Dependency settings
+	DependsOn []string         `toml:"depends_on,omitempty"` // scripts that must be ready first
+	Ready     *ReadinessConfig `toml:"ready,omitempty"`      // how this script signals readiness
+
+	// Health check settings
+	Health *HealthConfig `toml:"health,omitempty"`
+
+	// Environment settings
+	Env map[string]string `toml:"env,omitempty"` // overrides applied on top of .env files
+
+	// Terminal settings
+	PTY   *bool `toml:"pty,omitempty"`   // run under a pseudo-terminal instead of pipes
+	Stdin *bool `toml:"stdin,omitempty"` // give the script a writable stdin instead of /dev/null
+
+	// Resource limits enforced with cgroups v2; unset fields are unlimited
+	Limits *ProcessLimits `toml:"limits,omitempty"`
+
+	// Restart when watched files change
+	Watch *WatchConfig `toml:"watch,omitempty"`
+
+	// Shutdown settings
+	StopSignal         *string `toml:"stop_signal,omitempty"`          // sent to the process group first; SIGTERM by default
+	StopTimeoutSeconds *int    `toml:"stop_timeout_seconds,omitempty"` // wait before escalating to SIGKILL
+	PreStop            *string `toml:"pre_stop,omitempty"`             // shell command run before the stop signal
+
+	// Port assignment
+	AssignPort *bool   `toml:"assign_port,omitempty"` // overrides ports.assign for this script
+	PortEnv    *string `toml:"port_env,omitempty"`    // variable receiving the port; PORT by default
+}
+
+// ProcessLimits caps the resources a script's process tree may use
+type ProcessLimits struct {
+	MaxMemoryMB   *int `toml:"max_memory_mb,omitempty"`
+	MaxCPUPercent *int `toml:"max_cpu_percent,omitempty"` // percent of one CPU
+	MaxProcesses  *int `toml:"max_processes,omitempty"`
+}
+
+// WatchConfig restarts a script when files under its watched paths change. Globs use
+// forward slashes and may contain **; a glob without a slash matches the file name
+// in any directory.
+type WatchConfig struct {
+	Paths      []string `toml:"paths,omitempty"`       // directories to watch, relative to the project
+	Include    []string `toml:"include,omitempty"`     // files that trigger a restart; all files when empty
+	Exclude    []string `toml:"exclude,omitempty"`     // ignored in addition to .git and node_modules
+	DebounceMs *int     `toml:"debounce_ms,omitempty"` // quiet period before restarting
+}
+
+// ReadinessConfig describes when a started script counts as ready for its dependents.
+// When several checks are set, the first one to pass wins. With none set, a script
+// is ready as soon as it is running.
+type ReadinessConfig struct {
+	LogPattern     *string `toml:"log,omitempty"`             // regex matched against output lines
+	Port           *int    `toml:"port,omitempty"`            // TCP port that accepts connections
+	HTTP           *string `toml:"http,omitempty"`            // URL that returns 200
+	Exit           *bool   `toml:"exit,omitempty"`            // ready once the script exits cleanly
+	TimeoutSeconds *int    `toml:"timeout_seconds,omitempty"` // how long dependents wait
+}
+
+// GetScriptConfig returns the settings for a script, or nil if none are configured
+func (c *Config) GetScriptConfig(name string) *ScriptConfig {
+	if c == nil || c.Scripts == nil {
+		return nil
+	}
+	return c.Scripts[name]
+}
+
+// Restart helpers
+
+func (s *ScriptConfig) GetRestartPolicy() RestartPolicy {
+	if s == nil || s.Restart == nil {
+		return RestartNever // default
+	}
+	switch policy := RestartPolicy(*s.Restart); policy {
+	case RestartOnFailure, RestartAlways:
+		return policy
+	default:
+		return RestartNever
+	}
+}
+
+func (s *ScriptConfig) GetMaxRetries() int {
+	if s == nil || s.MaxRetries == nil {
+		return 5 // default
+	}
+	return *s.MaxRetries
+}
+
+func (s *ScriptConfig) GetRestartDelay() time.Duration {
+	if s == nil || s.RestartDelayMs == nil {
+		return time.Second // default
+	}
+	return time.Duration(*s.RestartDelayMs) * time.Millisecond
+}
+
+func (s *ScriptConfig) GetRestartMaxDelay() time.Duration {
+	if s == nil || s.RestartMaxDelayMs == nil {
+		return 30 * time.Second // default
+	}
+	return time.Duration(*s.RestartMaxDelayMs) * time.Millisecond
+}
+
+func (s *ScriptConfig) GetStableAfter() time.Duration {
+	if s == nil || s.StableAfterSecs == nil {
+		return 10 * time.Second // default
+	}
+	return time.Duration(*s.StableAfterSecs) * time.Second
+}
+
+// HealthConfig holds the periodic probes run against a running script
+type HealthConfig struct {
+	Liveness           *ProbeConfig `toml:"liveness,omitempty"`             // failing marks the process unhealthy
+	Readiness          *ProbeConfig `toml:"readiness,omitempty"`            // failing marks the process not ready
+	RestartOnUnhealthy *bool        `toml:"restart_on_unhealthy,omitempty"` // restart when liveness fails
+}
+
+// ProbeConfig describes a single health probe. Exactly one of HTTP, TCP or Exec should be set.
+type ProbeConfig struct {
+	HTTP                *string `toml:"http,omitempty"` // GET that must return 2xx or 3xx
+	TCP                 *int    `toml:"tcp,omitempty"`  // localhost port that must accept connections
+	Exec                *string `toml:"exec,omitempty"` // shell command that must exit 0
+	IntervalSeconds     *int    `toml:"interval_seconds,omitempty"`
+	TimeoutSeconds      *int    `toml:"timeout_seconds,omitempty"`
+	InitialDelaySeconds *int    `toml:"initial_delay_seconds,omitempty"`
+	FailureThreshold    *int    `toml:"failure_threshold,omitempty"` // consecutive failures before flipping state
+}
+
+// Dependency helpers
+
+func (s *ScriptConfig) GetDependsOn() []string {
+	if s == nil {
+		return nil
+	}
+	return s.DependsOn
+}
+
+func (s *ScriptConfig) GetReadiness() *ReadinessConfig {
+	if s == nil {
+		return nil
+	}
+	return s.Ready
+}
+
+// Readiness helpers
+
+func (r *ReadinessConfig) GetLogPattern() string {
+	if r == nil || r.LogPattern == nil {
+		return ""
+	}
+	return *r.LogPattern
+}
+
+func (r *ReadinessConfig) GetPort() int {
+	if r == nil || r.Port == nil {
+		return 0
+	}
+	return *r.Port
+}
+
+func (r *ReadinessConfig) GetHTTP() string {
+	if r == nil || r.HTTP == nil {
+		return ""
+	}
+	return *r.HTTP
+}
+
+func (r *ReadinessConfig) GetExit() bool {
+	if r == nil || r.Exit == nil {
+		return false
+	}
+	return *r.Exit
+}
+
+func (r *ReadinessConfig) GetTimeout() time.Duration {
+	if r == nil || r.TimeoutSeconds == nil {
+		return 60 * time.Second // default
+	}
+	return time.Duration(*r.TimeoutSeconds) * time.Second
+}
+
+// Health helpers
+
+func (s *ScriptConfig) GetHealth() *HealthConfig {
+	if s == nil {
+		return nil
+	}
+	return s.Health
+}
+
+func (h *HealthConfig) GetLiveness() *ProbeConfig {
+	if h == nil {
+		return nil
+	}
+	return h.Liveness
+}
+
+func (h *HealthConfig) GetReadiness() *ProbeConfig {
+	if h == nil {
+		return nil
+	}
+	return h.Readiness
+}
+
+func (h *HealthConfig) GetRestartOnUnhealthy() bool {
+	if h == nil || h.RestartOnUnhealthy == nil {
+		return false // default
+	}
+	return *h.RestartOnUnhealthy
+}
+
+// Probe helpers
+
+func (p *ProbeConfig) GetHTTP() string {
+	if p == nil || p.HTTP == nil {
+		return ""
+	}
+	return *p.HTTP
+}
+
+func (p *ProbeConfig) GetTCP() int {
+	if p == nil || p.TCP == nil {
+		return 0
+	}
+	return *p.TCP
+}
+
+func (p *ProbeConfig) GetExec() string {
+	if p == nil || p.Exec == nil {
+		return ""
+	}
+	return *p.Exec
+}
+
+func (p *ProbeConfig) GetInterval() time.Duration {
+	if p == nil || p.IntervalSeconds == nil {
+		return 10 * time.Second // default
+	}
+	return time.Duration(*p.IntervalSeconds) * time.Second
+}
+
+func (p *ProbeConfig) GetTimeout() time.Duration {
+	if p == nil || p.TimeoutSeconds == nil {
+		return 2 * time.Second // default
+	}
+	return time.Duration(*p.TimeoutSeconds) * time.Second
+}
+
+func (p *ProbeConfig) GetInitialDelay() time.Duration {
+	if p == nil || p.InitialDelaySeconds == nil {
+		return 0 // default
+	}
+	return time.Duration(*p.InitialDelaySeconds) * time.Second
+}
+
+func (p *ProbeConfig) GetFailureThreshold() int {
+	if p == nil || p.FailureThreshold == nil {
+		return 3 // default
+	}
+	return *p.FailureThreshold
+}
+
+// Environment helpers
+
+func (s *ScriptConfig) GetEnv() map[string]string {
+	if s == nil {
+		return nil
+	}
+	return s.Env
+}
+
+// Terminal helpers
+
+func (s *ScriptConfig) GetPTY() bool {
+	if s == nil || s.PTY == nil {
+		return false // default
+	}
+	return *s.PTY
+}
+
+func (s *ScriptConfig) GetStdin() bool {
+	if s == nil || s.Stdin == nil {
+		return false // default
+	}
+	return *s.Stdin
+}
+
+// Resource limit helpers
+
+func (s *ScriptConfig) GetLimits() cgroup.Limits {
+	if s == nil {
+		return cgroup.Limits{}
+	}
+	return s.Limits.CgroupLimits()
+}
+
+// CgroupLimits returns the limits to enforce; only limits that were configured are enforced
+func (l *ProcessLimits) CgroupLimits() cgroup.Limits {
+	var limits cgroup.Limits
+	if l == nil {
+		return limits
+	}
+	if l.MaxMemoryMB != nil {
+		limits.MemoryMB = *l.MaxMemoryMB
+	}
+	if l.MaxCPUPercent != nil {
+		limits.CPUPercent = *l.MaxCPUPercent
+	}
+	if l.MaxProcesses != nil {
+		limits.MaxProcesses = *l.MaxProcesses
+	}
+	return limits
+}
+
+// Shutdown helpers
+
+func (s *ScriptConfig) GetStopSignal() string {
+	if s == nil || s.StopSignal == nil || *s.StopSignal == "" {
+		return "SIGTERM" // default
+	}
+	return *s.StopSignal
+}
+
+func (s *ScriptConfig) GetStopTimeout() time.Duration {
+	if s == nil || s.StopTimeoutSeconds == nil {
+		return 5 * time.Second // default
+	}
+	return time.Duration(*s.StopTimeoutSeconds) * time.Second
+}
+
+func (s *ScriptConfig) GetPreStop() string {
+	if s == nil || s.PreStop == nil {
+		return ""
+	}
+	return *s.PreStop
+}
+
+// Watch helpers
+
+// defaultWatchExcludes are never watched
+var defaultWatchExcludes = []string{".git/**", "node_modules/**", "**/.git/**", "**/node_modules/**"}
+
+func (s *ScriptConfig) GetWatch() *WatchConfig {
+	if s == nil {
+		return nil
+	}
+	return s.Watch
+}
+
+func (w *WatchConfig) GetPaths() []string {
+	if w == nil || len(w.Paths) == 0 {
+		return []string{"."} // default: the whole project
+	}
+	return w.Paths
+}
+
+func (w *WatchConfig) GetInclude() []string {
+	if w == nil {
+		return nil
+	}
+	return w.Include
+}
+
+func (w *WatchConfig) GetExclude() []string {
+	if w == nil {
+		return defaultWatchExcludes
+	}
+	return append(append([]string(nil), defaultWatchExcludes...), w.Exclude...)
+}
+
+func (w *WatchConfig) GetDebounce() time.Duration {
+	if w == nil || w.DebounceMs == nil {
+		return 300 * time.Millisecond // default
+	}
+	return time.Duration(*w.DebounceMs) * time.Millisecond
+}
@@ -0,0 +1,56 @@
+package config
+
+// ServiceConfig declares a named command under [services.<name>] in .brum.toml, for
+// projects whose processes are not package.json scripts. Per-script settings such as
+// restart, depends_on and limits apply to a service through [scripts.<name>].
+type ServiceConfig struct {
+	Command string            `toml:"command"`         // executable, or a shell command line when args is empty
+	Args    []string          `toml:"args,omitempty"`  // arguments passed to command without a shell
+	Cwd     *string           `toml:"cwd,omitempty"`   // working directory, relative to the project
+	Env     map[string]string `toml:"env,omitempty"`   // variables set on top of the .env files
+	Group   *string           `toml:"group,omitempty"` // group started and stopped together, e.g. "backend"
+}
+
+// GetServiceConfig returns a declared service, or nil if there is none with that name
+func (c *Config) GetServiceConfig(name string) *ServiceConfig {
+	if c == nil || c.Services == nil {
+		return nil
+	}
+	return c.Services[name]
+}
+
+// mergeServices overlays service declarations; a more specific file replaces a service's whole entry
+func (c *Config) mergeServices(services map[string]*ServiceConfig) {
+	if len(services) == 0 {
+		return
+	}
+	if c.Services == nil {
+		c.Services = make(map[string]*ServiceConfig)
+	}
+	for name, svc := range services {
+		c.Services[name] = svc
+	}
+}
+
+// Service helpers
+
+func (s *ServiceConfig) GetCwd() string {
+	if s == nil || s.Cwd == nil {
+		return "" // default: the project directory
+	}
+	return *s.Cwd
+}
+
+func (s *ServiceConfig) GetEnv() map[string]string {
+	if s == nil {
+		return nil
+	}
+	return s.Env
+}
+
+func (s *ServiceConfig) GetGroup() string {
+	if s == nil || s.Group == nil {
+		return ""
+	}
+	return *s.Group
+}
@@ -0,0 +1,146 @@
+package discovery
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// PortReservation records that a brummer instance has handed a port to one of its
+// processes, so that other instances on the machine leave it alone
+type PortReservation struct {
+	Port       int       `json:"port"`
+	OwnerPID   int       `json:"ownerPid"` // the brummer holding the port
+	WorkDir    string    `json:"workDir"`
+	Name       string    `json:"name"` // script or service the port was assigned to
+	ReservedAt time.Time `json:"reservedAt"`
+}
+
+// GetPortsDir returns where port reservations are kept. Like the session directory
+// it is a sibling of the instances directory.
+func GetPortsDir() string {
+	return filepath.Join(filepath.Dir(GetDefaultInstancesDir()), "ports")
+}
+
+// ReservePort claims a port for res.OwnerPID. It reports false when another live
+// instance holds the port. A reservation left by an instance that has exited is
+// taken over, and one already held by the same owner, directory and name is kept.
+func ReservePort(dir string, res PortReservation) (bool, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return false, err
+	}
+	path := reservationPath(dir, res.Port)
+	data, err := json.Marshal(res)
+	if err != nil {
+		return false, err
+	}
+
+	// Two attempts: the second follows removing a stale reservation
+	for attempt := 0; attempt < 2; attempt++ {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, writeErr := file.Write(data)
+			closeErr := file.Close()
+			if writeErr != nil || closeErr != nil {
+				os.Remove(path)
+				return false, fmt.Errorf("failed to write port reservation: %w", errors.Join(writeErr, closeErr))
+			}
+			return true, nil
+		}
+		if !os.IsExist(err) {
+			return false, err
+		}
+
+		existing, readErr := readReservation(path)
+		if readErr == nil {
+			if existing.OwnerPID == res.OwnerPID && existing.WorkDir == res.WorkDir && existing.Name == res.Name {
+				return true, nil
+			}
+			if ownerAlive(existing.OwnerPID) {
+				return false, nil
+			}
+		} else if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) < time.Second {
+			return false, nil // another instance is still writing it
+		}
+		os.Remove(path)
+	}
+	return false, nil
+}
+
+// ReleasePort drops a reservation if ownerPID holds it
+func ReleasePort(dir string, port, ownerPID int) error {
+	path := reservationPath(dir, port)
+	existing, err := readReservation(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if existing.OwnerPID != ownerPID {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// ListPortReservations returns the reservations held by live instances
+func ListPortReservations(dir string) ([]PortReservation, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var reservations []PortReservation
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		res, err := readReservation(filepath.Join(dir, entry.Name()))
+		if err != nil || !ownerAlive(res.OwnerPID) {
+			continue
+		}
+		reservations = append(reservations, res)
+	}
+	return reservations, nil
+}
+
+func reservationPath(dir string, port int) string {
+	return filepath.Join(dir, fmt.Sprintf("%d.json", port))
+}
+
+func readReservation(path string) (PortReservation, error) {
+	var res PortReservation
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return res, err
+	}
+	err = json.Unmarshal(data, &res)
+	return res, err
+}
+
+// ownerAlive reports whether the instance that made a reservation is still running
+func ownerAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true // FindProcess only succeeds for running processes there
+	}
+	err = proc.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
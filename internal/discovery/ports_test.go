@@ -0,0 +1,68 @@
+package discovery
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestPortReservation(t *testing.T) {
+	dir := t.TempDir()
+	mine := PortReservation{Port: 4100, OwnerPID: os.Getpid(), WorkDir: "/project", Name: "api", ReservedAt: time.Now()}
+
+	ok, err := ReservePort(dir, mine)
+	if err != nil || !ok {
+		t.Fatalf("Expected to reserve port 4100, got %v, %v", ok, err)
+	}
+
+	// Reserving again for the same service keeps the reservation
+	ok, err = ReservePort(dir, mine)
+	if err != nil || !ok {
+		t.Fatalf("Expected the reservation to be kept, got %v, %v", ok, err)
+	}
+
+	// Another live instance cannot take it
+	other := mine
+	other.OwnerPID = os.Getppid()
+	ok, err = ReservePort(dir, other)
+	if err != nil || ok {
+		t.Fatalf("Expected port 4100 to be held, got %v, %v", ok, err)
+	}
+
+	reservations, err := ListPortReservations(dir)
+	if err != nil || len(reservations) != 1 || reservations[0].Name != "api" {
+		t.Fatalf("Expected one reservation for api, got %+v, %v", reservations, err)
+	}
+
+	// Only the owner can release it
+	if err := ReleasePort(dir, 4100, other.OwnerPID); err != nil {
+		t.Fatalf("ReleasePort failed: %v", err)
+	}
+	if reservations, _ := ListPortReservations(dir); len(reservations) != 1 {
+		t.Fatalf("Expected the reservation to survive a release by another instance")
+	}
+	if err := ReleasePort(dir, 4100, mine.OwnerPID); err != nil {
+		t.Fatalf("ReleasePort failed: %v", err)
+	}
+	if reservations, _ := ListPortReservations(dir); len(reservations) != 0 {
+		t.Fatalf("Expected no reservations, got %+v", reservations)
+	}
+}
+
+func TestStalePortReservationIsTakenOver(t *testing.T) {
+	dir := t.TempDir()
+
+	// A PID above the kernel maximum never belongs to a running process
+	stale := PortReservation{Port: 4200, OwnerPID: 1 << 30, WorkDir: "/old", Name: "web", ReservedAt: time.Now()}
+	if ok, err := ReservePort(dir, stale); err != nil || !ok {
+		t.Fatalf("Expected to reserve port 4200, got %v, %v", ok, err)
+	}
+	if reservations, _ := ListPortReservations(dir); len(reservations) != 0 {
+		t.Fatalf("Expected the dead instance's reservation to be ignored, got %+v", reservations)
+	}
+
+	fresh := PortReservation{Port: 4200, OwnerPID: os.Getpid(), WorkDir: "/project", Name: "web", ReservedAt: time.Now()}
+	if ok, err := ReservePort(dir, fresh); err != nil || !ok {
+		t.Fatalf("Expected to take over port 4200, got %v, %v", ok, err)
+	}
+}
@@ -1,6 +1,8 @@
 package discovery
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"os"
 	"path/filepath"
 )
@@ -34,3 +36,11 @@ func GetDefaultInstancesDir() string {
 	// Fall back to temp directory
 	return filepath.Join(os.TempDir(), "brummer", "instances")
 }
+
+// GetSessionDir returns where process state for a project directory is kept, so a
+// later brummer can reattach to the processes it started. It is a sibling of the
+// instances directory because every JSON file there is read as an instance.
+func GetSessionDir(workDir string) string {
+	sum := sha256.Sum256([]byte(workDir))
+	return filepath.Join(filepath.Dir(GetDefaultInstancesDir()), "sessions", hex.EncodeToString(sum[:8]))
+}
@@ -0,0 +1,122 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParse tests dotenv syntax handling
+func TestParse(t *testing.T) {
+	input := `# comment
+PLAIN=value
+export EXPORTED=yes
+SPACED = padded  # trailing comment
+SINGLE='literal \n # kept'
+DOUBLE="line1\nline2 \"quoted\""
+EMPTY=
+URL=http://localhost:3000/#anchor
+`
+	pairs, err := Parse(strings.NewReader(input))
+	require.NoError(t, err)
+
+	got := make(map[string]string)
+	for _, p := range pairs {
+		got[p.Key] = p.Value
+	}
+	assert.Equal(t, map[string]string{
+		"PLAIN":    "value",
+		"EXPORTED": "yes",
+		"SPACED":   "padded",
+		"SINGLE":   `literal \n # kept`,
+		"DOUBLE":   "line1\nline2 \"quoted\"",
+		"EMPTY":    "",
+		"URL":      "http://localhost:3000/#anchor",
+	}, got)
+
+	_, err = Parse(strings.NewReader("NOEQUALS\n"))
+	assert.Error(t, err)
+	_, err = Parse(strings.NewReader(`OPEN="unterminated` + "\n"))
+	assert.Error(t, err)
+}
+
+// TestLoadPrecedence tests that later layers override earlier ones, and that the
+// system environment wins over dotenv files unless they are set to override it
+func TestLoadPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	write(".env", "A=base\nB=base\nC=base\nD=base\n")
+	write(".env.staging", "B=staging\nC=staging\nD=staging\n")
+	write(".env.local", "C=local\nD=local\n")
+	write(".env.production", "B=production\n")
+
+	t.Setenv("BRUM_ENV_TEST_SYSTEM", "system")
+	t.Setenv("A", "from-system")
+
+	e, err := Load(Options{
+		Dir:       dir,
+		Profile:   "staging",
+		Overrides: map[string]string{"D": "override"},
+	})
+	require.NoError(t, err)
+
+	expect := map[string][2]string{
+		"A":                    {"from-system", SourceSystem},
+		"B":                    {"staging", ".env.staging"},
+		"C":                    {"local", ".env.local"},
+		"D":                    {"override", SourceOverride},
+		"BRUM_ENV_TEST_SYSTEM": {"system", SourceSystem},
+	}
+	for name, want := range expect {
+		v, ok := e.Get(name)
+		require.True(t, ok, name)
+		assert.Equal(t, want[0], v.Value, name)
+		assert.Equal(t, want[1], v.Source, name)
+	}
+	assert.Equal(t, []string{".env", ".env.staging", ".env.local"}, e.Files())
+	assert.Contains(t, e.Environ(), "D=override")
+
+	for _, v := range e.Variables(false) {
+		assert.NotEqual(t, SourceSystem, v.Source)
+	}
+
+	e, err = Load(Options{Dir: dir, OverrideSystem: true})
+	require.NoError(t, err)
+	v, _ := e.Get("A")
+	assert.Equal(t, "base", v.Value)
+	assert.Equal(t, ".env", v.Source)
+}
+
+// TestSecretMasking tests secret detection and masking
+func TestSecretMasking(t *testing.T) {
+	assert.True(t, IsSecret("STRIPE_SECRET_KEY", nil))
+	assert.True(t, IsSecret("github_token", nil))
+	assert.True(t, IsSecret("DB_PASSWORD", nil))
+	assert.False(t, IsSecret("PORT", nil))
+	assert.True(t, IsSecret("SIGNING_SALT", []string{"signing_salt"}))
+
+	assert.Equal(t, "***", Mask("short"))
+	assert.Equal(t, "***", Mask("sk_test_1234567890"))
+	assert.Equal(t, "", Mask(""))
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("API_TOKEN=abcdefghijklmnop\nPORT=3000\n"), 0644))
+	e, err := Load(Options{Dir: dir})
+	require.NoError(t, err)
+
+	masked := make(map[string]string)
+	for _, v := range e.Masked(false) {
+		masked[v.Name] = v.Value
+	}
+	assert.Equal(t, "***", masked["API_TOKEN"])
+	assert.Equal(t, "3000", masked["PORT"])
+
+	// The unmasked value is still what the process receives
+	assert.Contains(t, e.Environ(), "API_TOKEN=abcdefghijklmnop")
+}
@@ -0,0 +1,146 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Source names for variables that do not come from a dotenv file
+const (
+	SourceSystem   = "system"
+	SourceOverride = ".brum.toml"
+)
+
+// Variable is a resolved environment variable and the layer it came from
+type Variable struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Source   string `json:"source"`
+	IsSecret bool   `json:"is_secret"`
+}
+
+// Options controls how an environment is resolved
+type Options struct {
+	Dir        string            // directory containing the dotenv files
+	Profile    string            // selects .env.<profile>; empty skips that layer
+	Overrides  map[string]string // per-script values from .brum.toml
+	SecretKeys []string          // extra variable names to mask
+
+	// OverrideSystem lets dotenv files replace variables already set in the system
+	// environment. By default the system environment wins, as with other dotenv tools.
+	OverrideSystem bool
+}
+
+// Environment is the layered result of resolving the system environment, dotenv
+// files and script overrides
+type Environment struct {
+	vars       map[string]Variable
+	files      []string
+	secretKeys []string
+}
+
+// Layers returns the dotenv file names for a profile, lowest precedence first
+func Layers(profile string) []string {
+	layers := []string{".env"}
+	if profile != "" {
+		layers = append(layers, ".env."+profile)
+	}
+	return append(layers, ".env.local")
+}
+
+// Load resolves the environment. Precedence from lowest to highest is .env,
+// .env.<profile>, .env.local, the system environment, then script overrides. With
+// OverrideSystem the dotenv files come after the system environment instead.
+func Load(opts Options) (*Environment, error) {
+	e := &Environment{
+		vars:       make(map[string]Variable),
+		secretKeys: opts.SecretKeys,
+	}
+
+	for _, kv := range os.Environ() {
+		if idx := strings.Index(kv, "="); idx > 0 {
+			e.set(kv[:idx], kv[idx+1:], SourceSystem)
+		}
+	}
+
+	for _, layer := range Layers(opts.Profile) {
+		path := filepath.Join(opts.Dir, layer)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		pairs, err := ParseFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range pairs {
+			if v, ok := e.vars[p.Key]; ok && v.Source == SourceSystem && !opts.OverrideSystem {
+				continue
+			}
+			e.set(p.Key, p.Value, layer)
+		}
+		e.files = append(e.files, layer)
+	}
+
+	for key, value := range opts.Overrides {
+		e.set(key, value, SourceOverride)
+	}
+
+	return e, nil
+}
+
+func (e *Environment) set(name, value, source string) {
+	e.vars[name] = Variable{
+		Name:     name,
+		Value:    value,
+		Source:   source,
+		IsSecret: IsSecret(name, e.secretKeys),
+	}
+}
+
+// Get returns a resolved variable
+func (e *Environment) Get(name string) (Variable, bool) {
+	v, ok := e.vars[name]
+	return v, ok
+}
+
+// Files returns the dotenv files that were loaded, lowest precedence first
+func (e *Environment) Files() []string {
+	return e.files
+}
+
+// Environ returns the environment in KEY=VALUE form for exec.Cmd.Env
+func (e *Environment) Environ() []string {
+	environ := make([]string, 0, len(e.vars))
+	for _, v := range e.vars {
+		environ = append(environ, v.Name+"="+v.Value)
+	}
+	sort.Strings(environ)
+	return environ
+}
+
+// Variables returns the resolved variables sorted by name. System variables are
+// only included when includeSystem is set.
+func (e *Environment) Variables(includeSystem bool) []Variable {
+	vars := make([]Variable, 0, len(e.vars))
+	for _, v := range e.vars {
+		if v.Source == SourceSystem && !includeSystem {
+			continue
+		}
+		vars = append(vars, v)
+	}
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars
+}
+
+// Masked returns Variables with secret values masked, for display
+func (e *Environment) Masked(includeSystem bool) []Variable {
+	vars := e.Variables(includeSystem)
+	for i := range vars {
+		if vars[i].IsSecret {
+			vars[i].Value = Mask(vars[i].Value)
+		}
+	}
+	return vars
+}
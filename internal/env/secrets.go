@@ -0,0 +1,34 @@
+package env
+
+import "strings"
+
+// secretMarkers are name fragments that mark a variable as sensitive
+var secretMarkers = []string{
+	"SECRET", "TOKEN", "PASSWORD", "PASSWD", "APIKEY", "_KEY",
+	"PRIVATE", "CREDENTIAL", "AUTH_", "DSN", "DATABASE_URL",
+}
+
+// IsSecret reports whether a variable name looks sensitive. Names listed in extra
+// are always treated as secret.
+func IsSecret(name string, extra []string) bool {
+	upper := strings.ToUpper(name)
+	for _, e := range extra {
+		if strings.EqualFold(e, name) {
+			return true
+		}
+	}
+	for _, marker := range secretMarkers {
+		if strings.Contains(upper, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// Mask hides a secret value entirely; only whether it is set shows
+func Mask(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "***"
+}
@@ -20,6 +20,12 @@ type ErrorContext struct {
 	Severity    string   // critical, error, warning
 	Language    string   // js, go, python, java, etc.
 	Raw         []string // All raw log lines that make up this error
+
+	// Stack as logged, one line per Stack line, when source maps rewrote Stack
+	GeneratedStack []string
+
+	// Identifies the error across occurrences and restarts; see Fingerprint
+	Fingerprint string
 }
 
 // ErrorParser handles sophisticated multi-line error parsing
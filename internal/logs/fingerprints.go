@@ -0,0 +1,362 @@
+package logs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrorStatus is what has been decided about an error fingerprint
+type ErrorStatus string
+
+const (
+	ErrorOpen     ErrorStatus = "open"
+	ErrorResolved ErrorStatus = "resolved" // reopened when the error comes back
+	ErrorIgnored  ErrorStatus = "ignored"  // hidden from the errors view for good
+)
+
+// ParseErrorStatus reads open, resolved or ignored
+func ParseErrorStatus(name string) (ErrorStatus, bool) {
+	switch status := ErrorStatus(strings.ToLower(name)); status {
+	case ErrorOpen, ErrorResolved, ErrorIgnored:
+		return status, true
+	}
+	return "", false
+}
+
+// ErrorFingerprint tracks every occurrence of one error, across restarts
+type ErrorFingerprint struct {
+	Fingerprint string      `json:"fingerprint"`
+	Type        string      `json:"type"`
+	Message     string      `json:"message"`          // as first seen
+	Frames      []string    `json:"frames,omitempty"` // the in-app frames the fingerprint covers
+	FirstSeen   time.Time   `json:"first_seen"`
+	LastSeen    time.Time   `json:"last_seen"`
+	Count       int         `json:"count"`
+	Processes   []string    `json:"processes"`
+	Status      ErrorStatus `json:"status"`
+	StatusSince time.Time   `json:"status_since,omitempty"`
+	Regressed   bool        `json:"regressed,omitempty"` // seen again after it was resolved
+}
+
+// fingerprintFrames is how many in-app frames go into a fingerprint
+const fingerprintFrames = 3
+
+var (
+	messageTimes   = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:?\d{2})?|\b\d{1,2}:\d{2}:\d{2}(?:\.\d+)?\b`)
+	messageUUIDs   = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+	messageHex     = regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b|\b[0-9a-f]{6,}\b`)
+	messageNumbers = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	messageSpaces  = regexp.MustCompile(`\s+`)
+
+	framePosition  = regexp.MustCompile(`(?::\d+)+|, line \d+| \+0x[0-9a-f]+`)
+	frameBuildHash = regexp.MustCompile(`(?i)[.-][0-9a-f]{6,}(\.[a-z]+)`)
+)
+
+// externalFrameMarkers identify frames outside the application: dependencies,
+// runtimes and bundler glue
+var externalFrameMarkers = []string{
+	"node_modules", "node:", "(internal/", "(native)", "<anonymous>",
+	"site-packages", "dist-packages", "/lib/python", "/go/src/runtime/", "/usr/local/go/",
+	"webpack/bootstrap", "webpack/runtime", "/.pnpm/", "/.yarn/",
+}
+
+// Fingerprint identifies an error by its type, its message with the variable parts
+// taken out, and its top in-app frames without line numbers, so that the same error
+// keeps its fingerprint across reloads and restarts. Frames are taken as logged, not
+// as resolved through source maps, so the fingerprint does not depend on whether a
+// map could be found.
+func Fingerprint(ctx ErrorContext) string {
+	sum := sha256.Sum256([]byte(ctx.Type + "\x00" + normalizeErrorMessage(ctx.Message) + "\x00" + strings.Join(inAppFrames(loggedStack(ctx)), "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// loggedStack returns the stack of an error as it was logged
+func loggedStack(ctx ErrorContext) []string {
+	if len(ctx.GeneratedStack) > 0 {
+		return ctx.GeneratedStack
+	}
+	return ctx.Stack
+}
+
+// normalizeErrorMessage replaces times, IDs, addresses and numbers in a message
+func normalizeErrorMessage(message string) string {
+	message = ansiRegex.ReplaceAllString(message, "")
+	message = messageTimes.ReplaceAllString(message, "<time>")
+	message = messageUUIDs.ReplaceAllString(message, "<id>")
+	message = messageHex.ReplaceAllStringFunc(message, func(word string) string {
+		if !hasDigit(word) || strings.Trim(word, "0123456789") == "" {
+			return word // a word like "facade", or a number
+		}
+		return "<hex>"
+	})
+	message = messageNumbers.ReplaceAllString(message, "<n>")
+	return strings.TrimSpace(messageSpaces.ReplaceAllString(message, " "))
+}
+
+// inAppFrames returns the top stack frames that belong to the application, without
+// their line and column numbers or build hashes
+func inAppFrames(stack []string) []string {
+	var frames []string
+	for _, line := range stack {
+		frame := strings.TrimSpace(ansiRegex.ReplaceAllString(line, ""))
+		if frame == "" || isExternalFrame(frame) {
+			continue
+		}
+		frame = framePosition.ReplaceAllString(frame, "")
+		frame = frameBuildHash.ReplaceAllStringFunc(frame, func(name string) string {
+			ext := frameBuildHash.FindStringSubmatch(name)[1]
+			if !hasDigit(strings.TrimSuffix(name, ext)) {
+				return name
+			}
+			return ext // main.3f9a2c1b.js is main.js in every build
+		})
+		frames = append(frames, frame)
+		if len(frames) == fingerprintFrames {
+			break
+		}
+	}
+	return frames
+}
+
+func hasDigit(s string) bool {
+	return strings.ContainsAny(s, "0123456789")
+}
+
+func isExternalFrame(frame string) bool {
+	for _, marker := range externalFrameMarkers {
+		if strings.Contains(frame, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// FingerprintStore counts error occurrences by fingerprint and keeps them, with
+// their status, in a JSON file
+type FingerprintStore struct {
+	path string // empty keeps them in memory only
+
+	mu           sync.Mutex
+	fingerprints map[string]*ErrorFingerprint
+	observed     map[string]time.Time // occurrences already counted, by when they were
+	dirty        bool
+}
+
+// FingerprintPath is where the fingerprints of a project are kept, next to its log history
+func FingerprintPath(root, workDir string) string {
+	return filepath.Join(HistoryDir(root, workDir), "fingerprints.json")
+}
+
+// OpenFingerprintStore loads the fingerprints saved at path. A missing file starts empty.
+func OpenFingerprintStore(path string) (*FingerprintStore, error) {
+	f := &FingerprintStore{
+		path:         path,
+		fingerprints: make(map[string]*ErrorFingerprint),
+		observed:     make(map[string]time.Time),
+	}
+	if path == "" {
+		return f, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read error fingerprints: %w", err)
+	}
+	var saved []*ErrorFingerprint
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to read error fingerprints from %s: %w", path, err)
+	}
+	for _, fp := range saved {
+		f.fingerprints[fp.Fingerprint] = fp
+	}
+	return f, nil
+}
+
+// Seen reports whether an occurrence has been recorded
+func (f *FingerprintStore) Seen(occurrence string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, seen := f.observed[occurrence]
+	return seen
+}
+
+// Record counts an occurrence of an error once, however often it is recorded
+func (f *FingerprintStore) Record(occurrence string, ctx ErrorContext) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, seen := f.observed[occurrence]; seen {
+		return
+	}
+	f.observed[occurrence] = time.Now()
+	f.dirty = true
+
+	key := ctx.Fingerprint
+	if key == "" {
+		key = Fingerprint(ctx)
+	}
+	fp, ok := f.fingerprints[key]
+	if !ok {
+		fp = &ErrorFingerprint{
+			Fingerprint: key,
+			Type:        ctx.Type,
+			Message:     ctx.Message,
+			Frames:      inAppFrames(loggedStack(ctx)),
+			FirstSeen:   ctx.Timestamp,
+			Status:      ErrorOpen,
+		}
+		f.fingerprints[key] = fp
+	}
+	fp.Count++
+	if ctx.Timestamp.After(fp.LastSeen) {
+		fp.LastSeen = ctx.Timestamp
+	}
+	if !containsString(fp.Processes, ctx.ProcessName) {
+		fp.Processes = append(fp.Processes, ctx.ProcessName)
+	}
+	if fp.Status == ErrorResolved && ctx.Timestamp.After(fp.StatusSince) {
+		fp.Status, fp.StatusSince, fp.Regressed = ErrorOpen, ctx.Timestamp, true
+	}
+}
+
+// Forget drops the occurrences recorded before the given time, once the entries
+// they came from are gone
+func (f *FingerprintStore) Forget(before time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for occurrence, recorded := range f.observed {
+		if recorded.Before(before) {
+			delete(f.observed, occurrence)
+		}
+	}
+}
+
+// Get returns a fingerprint
+func (f *FingerprintStore) Get(fingerprint string) (ErrorFingerprint, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fp, ok := f.fingerprints[fingerprint]
+	if !ok {
+		return ErrorFingerprint{}, false
+	}
+	return fp.copy(), true
+}
+
+// List returns the fingerprints, most recently seen first
+func (f *FingerprintStore) List() []ErrorFingerprint {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	list := make([]ErrorFingerprint, 0, len(f.fingerprints))
+	for _, fp := range f.fingerprints {
+		list = append(list, fp.copy())
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].LastSeen.Equal(list[j].LastSeen) {
+			return list[i].LastSeen.After(list[j].LastSeen)
+		}
+		return list[i].Fingerprint < list[j].Fingerprint
+	})
+	return list
+}
+
+// SetStatus marks a fingerprint open, resolved or ignored. A unique prefix of the
+// fingerprint is enough. The change is saved right away.
+func (f *FingerprintStore) SetStatus(fingerprint string, status ErrorStatus) (ErrorFingerprint, error) {
+	f.mu.Lock()
+	var match *ErrorFingerprint
+	for key, fp := range f.fingerprints {
+		if key == fingerprint {
+			match = fp
+			break
+		}
+		if strings.HasPrefix(key, fingerprint) {
+			if match != nil {
+				f.mu.Unlock()
+				return ErrorFingerprint{}, fmt.Errorf("fingerprint %q is ambiguous", fingerprint)
+			}
+			match = fp
+		}
+	}
+	if fingerprint == "" || match == nil {
+		f.mu.Unlock()
+		return ErrorFingerprint{}, fmt.Errorf("no error with fingerprint %q", fingerprint)
+	}
+	match.Status, match.StatusSince = status, time.Now()
+	if status != ErrorOpen {
+		match.Regressed = false
+	}
+	f.dirty = true
+	result := match.copy()
+	f.mu.Unlock()
+
+	return result, f.Save()
+}
+
+// Save writes the fingerprints to disk when they changed
+func (f *FingerprintStore) Save() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.path == "" || !f.dirty {
+		return nil
+	}
+	list := make([]*ErrorFingerprint, 0, len(f.fingerprints))
+	for _, fp := range f.fingerprints {
+		list = append(list, fp)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].FirstSeen.Before(list[j].FirstSeen) })
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write then rename, so a crash never leaves a truncated file
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return fmt.Errorf("failed to save error fingerprints: %w", err)
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to save error fingerprints: %w", err)
+	}
+	if err := os.Rename(tmp, f.path); err != nil {
+		return fmt.Errorf("failed to save error fingerprints: %w", err)
+	}
+	f.dirty = false
+	return nil
+}
+
+// hides reports whether an occurrence at the given time is hidden by the status
+func (fp ErrorFingerprint) hides(at time.Time) bool {
+	switch fp.Status {
+	case ErrorIgnored:
+		return true
+	case ErrorResolved:
+		return !at.After(fp.StatusSince)
+	}
+	return false
+}
+
+func (fp *ErrorFingerprint) copy() ErrorFingerprint {
+	c := *fp
+	c.Frames = append([]string(nil), fp.Frames...)
+	c.Processes = append([]string(nil), fp.Processes...)
+	return c
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
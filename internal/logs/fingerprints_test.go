@@ -0,0 +1,131 @@
+package logs
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+	before := ErrorContext{
+		Type:    "TypeError",
+		Message: "Cannot read properties of undefined (reading 'id') at 12:04:33 for order 8812",
+		Stack: []string{
+			"    at node_modules/react-dom/cjs/react-dom.development.js:1234:5",
+			"    at OrderList (http://localhost:3000/static/js/main.3f9a2c1b.js:88:19)",
+			"    at render (src/orders/render.ts:14:3)",
+		},
+	}
+	// After a hot reload: other line numbers, bundle hash, time and ID
+	after := ErrorContext{
+		Type:    "TypeError",
+		Message: "Cannot read properties of undefined (reading 'id') at 12:09:01 for order 9377",
+		Stack: []string{
+			"    at node_modules/react-dom/cjs/react-dom.development.js:1250:9",
+			"    at OrderList (http://localhost:3000/static/js/main.77c0e41d.js:91:19)",
+			"    at render (src/orders/render.ts:16:3)",
+		},
+	}
+	assert.Equal(t, Fingerprint(before), Fingerprint(after))
+	assert.Len(t, Fingerprint(before), 16)
+
+	// Resolving the stack through source maps keeps the fingerprint
+	resolved := after
+	resolved.GeneratedStack = after.Stack
+	resolved.Stack = []string{"    at OrderList (src/orders/OrderList.tsx:12:7)", "    at render (src/orders/render.ts:16:3)"}
+	assert.Equal(t, Fingerprint(after), Fingerprint(resolved))
+
+	other := after
+	other.Message = "Cannot read properties of null (reading 'id')"
+	assert.NotEqual(t, Fingerprint(before), Fingerprint(other))
+	other = after
+	other.Stack = []string{"    at checkout (src/checkout.ts:3:1)"}
+	assert.NotEqual(t, Fingerprint(before), Fingerprint(other))
+
+	assert.Equal(t, []string{
+		"at OrderList (http://localhost/static/js/main.js)",
+		"at render (src/orders/render.ts)",
+	}, inAppFrames(before.Stack))
+}
+
+func TestFingerprintStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fingerprints.json")
+	f, err := OpenFingerprintStore(path)
+	require.NoError(t, err)
+
+	start := time.Now().Add(-time.Minute)
+	boom := ErrorContext{ProcessName: "web", Type: "TypeError", Message: "boom", Timestamp: start}
+	f.Record("web-1", boom)
+	f.Record("web-1", boom) // the same group again
+	boom.ProcessName, boom.Timestamp = "api", start.Add(time.Second)
+	f.Record("api-2", boom)
+
+	key := Fingerprint(boom)
+	fp, ok := f.Get(key)
+	require.True(t, ok)
+	assert.Equal(t, 2, fp.Count)
+	assert.Equal(t, []string{"web", "api"}, fp.Processes)
+	assert.True(t, fp.FirstSeen.Equal(start))
+	assert.True(t, fp.LastSeen.Equal(start.Add(time.Second)))
+	assert.Equal(t, ErrorOpen, fp.Status)
+
+	// Resolving hides earlier occurrences; a later one reopens it
+	fp, err = f.SetStatus(key[:6], ErrorResolved)
+	require.NoError(t, err)
+	assert.True(t, fp.hides(start))
+	boom.Timestamp = time.Now().Add(time.Second)
+	f.Record("web-3", boom)
+	fp, _ = f.Get(key)
+	assert.Equal(t, ErrorOpen, fp.Status)
+	assert.True(t, fp.Regressed)
+	assert.Equal(t, 3, fp.Count)
+
+	_, err = f.SetStatus("ffff", ErrorIgnored)
+	assert.Error(t, err)
+	_, err = f.SetStatus(key, ErrorIgnored)
+	require.NoError(t, err)
+
+	// Fingerprints survive a restart
+	require.NoError(t, f.Save())
+	reopened, err := OpenFingerprintStore(path)
+	require.NoError(t, err)
+	fp, ok = reopened.Get(key)
+	require.True(t, ok)
+	assert.Equal(t, 3, fp.Count)
+	assert.Equal(t, ErrorIgnored, fp.Status)
+	assert.True(t, fp.hides(time.Now()))
+}
+
+func TestStoreTracksErrorOccurrences(t *testing.T) {
+	f, err := OpenFingerprintStore("")
+	require.NoError(t, err)
+	store := NewStore(100, nil)
+	store.SetFingerprints(f)
+
+	// Two hot reloads apart, the same error
+	store.addSync("web", "web", "TypeError: x is undefined (id 41)", true)
+	time.Sleep(300 * time.Millisecond)
+	store.addSync("web", "web", "TypeError: x is undefined (id 97)", true)
+
+	contexts := store.GetErrorContexts()
+	require.Len(t, contexts, 2)
+	assert.Equal(t, contexts[0].Fingerprint, contexts[1].Fingerprint)
+
+	require.Eventually(t, func() bool {
+		fp, ok := store.GetErrorFingerprint(contexts[0].Fingerprint)
+		return ok && fp.Count == 2
+	}, 3*time.Second, 50*time.Millisecond)
+
+	_, err = store.SetErrorStatus(contexts[0].Fingerprint, ErrorIgnored)
+	require.NoError(t, err)
+	assert.Empty(t, store.GetErrorContexts())
+
+	store.Close()
+	fingerprints, err := store.GetErrorFingerprints()
+	require.NoError(t, err)
+	require.Len(t, fingerprints, 1)
+	assert.Equal(t, 2, fingerprints[0].Count, "closing does not count them again")
+}
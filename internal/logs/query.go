@@ -0,0 +1,703 @@
+package logs
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Log query language. A query is a list of terms that must all match:
+//
+//	process:api level>=warn tag:build "timeout" since:10m -"healthcheck"
+//
+// A bare word or a "quoted phrase" matches the content or process name, ignoring
+// case, and /regex/ (with an optional i flag) matches the content. Fields are
+// process, level, tag, priority, message, since and until; a field's value can be
+// quoted or a regex literal too. Any other name:value matches that field of a
+// JSON or logfmt line, or the text as written, so status:504, user:"jane doe"
+// and duration_ms>=250 work. Terms combine with AND (implied), OR and NOT, a
+// leading - negates a term, and parentheses group.
+
+// QueryError reports where a query could not be parsed
+type QueryError struct {
+	Column  int // 1-based, in characters
+	Message string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Column, e.Message)
+}
+
+// Query is a parsed log query
+type Query struct {
+	match matcher
+	since time.Time // bounds every match satisfies, from top-level since and until terms
+	until time.Time
+}
+
+type matcher func(LogEntry) bool
+
+// ParseQuery parses a query. Relative times in since and until count back from now.
+func ParseQuery(input string) (*Query, error) {
+	return parseQueryAt(input, time.Now())
+}
+
+// RegexQuery matches the content against a regular expression
+func RegexQuery(pattern string) (*Query, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, &QueryError{Column: 1, Message: "invalid regex: " + err.Error()}
+	}
+	return &Query{match: func(entry LogEntry) bool { return re.MatchString(entry.Content) }}, nil
+}
+
+func parseQueryAt(input string, now time.Time) (*Query, error) {
+	tokens, err := lexQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{input: input, tokens: tokens, now: now}
+	if len(tokens) == 0 {
+		return &Query{}, nil
+	}
+	match, bounds, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok != nil {
+		if tok.kind == tokRParen {
+			return nil, p.errorAt(tok.pos, "unmatched )")
+		}
+		return nil, p.errorAt(tok.pos, "unexpected "+tok.text)
+	}
+	return &Query{match: match, since: bounds.since, until: bounds.until}, nil
+}
+
+// Match reports whether an entry matches the query
+func (q *Query) Match(entry LogEntry) bool {
+	return q.match == nil || q.match(entry)
+}
+
+// Page returns a PageQuery for up to limit matches logged before the given time
+func (q *Query) Page(before time.Time, limit int) PageQuery {
+	if !q.until.IsZero() && (before.IsZero() || q.until.Before(before)) {
+		before = q.until
+	}
+	return PageQuery{Match: q.Match, Since: q.since, Before: before, Limit: limit}
+}
+
+// Tokens
+
+type tokenKind int
+
+const (
+	tokWord   tokenKind = iota // bare word, or field:value
+	tokString                  // "quoted phrase"
+	tokRegex                   // /pattern/flags
+	tokLParen
+	tokRParen
+	tokMinus // - directly before a term
+)
+
+type queryToken struct {
+	kind tokenKind
+	text string // the token as written
+	pos  int    // byte offset in the input
+
+	// Words of the form field<op>value
+	field    string
+	name     string // the field name as written, for structured fields
+	op       string
+	value    string
+	valueTok *queryToken // a quoted or regex value
+}
+
+// queryFields maps field names and their aliases to fields
+var queryFields = map[string]string{
+	"process":  "process",
+	"proc":     "process",
+	"level":    "level",
+	"tag":      "tag",
+	"priority": "priority",
+	"message":  "message",
+	"msg":      "message",
+	"since":    "since",
+	"until":    "until",
+	"before":   "until",
+}
+
+func lexQuery(input string) ([]*queryToken, error) {
+	var tokens []*queryToken
+	i := 0
+	for i < len(input) {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '(':
+			tokens = append(tokens, &queryToken{kind: tokLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, &queryToken{kind: tokRParen, text: ")", pos: i})
+			i++
+		case r == '-' && i+1 < len(input) && !unicode.IsSpace(rune(input[i+1])):
+			tokens = append(tokens, &queryToken{kind: tokMinus, text: "-", pos: i})
+			i++
+		case r == '"':
+			tok, end, err := lexLiteral(input, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i = end
+		case r == '/':
+			// Text that does not read as /pattern/flags, such as the path /api/users,
+			// is searched for as a word
+			tok, end, err := lexLiteral(input, i)
+			if err != nil || !wordEndsAt(input, end) || strings.Trim(tok.op, "i") != "" {
+				tok, end, err = lexWord(input, i)
+				if err != nil {
+					return nil, err
+				}
+			}
+			tokens = append(tokens, tok)
+			i = end
+		default:
+			tok, end, err := lexWord(input, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// lexLiteral reads a "quoted phrase" or a /regex/flags starting at i
+func lexLiteral(input string, start int) (*queryToken, int, error) {
+	quote := input[start]
+	var value strings.Builder
+	for i := start + 1; i < len(input); i++ {
+		c := input[i]
+		if c == '\\' && i+1 < len(input) {
+			if quote == '"' || input[i+1] != '/' {
+				if quote == '/' {
+					value.WriteByte('\\') // keep the escape for the regex
+				}
+				value.WriteByte(input[i+1])
+			} else {
+				value.WriteByte('/')
+			}
+			i++
+			continue
+		}
+		if c != quote {
+			value.WriteByte(c)
+			continue
+		}
+
+		end := i + 1
+		if quote == '"' {
+			return &queryToken{kind: tokString, text: input[start:end], pos: start, value: value.String()}, end, nil
+		}
+		for end < len(input) && unicode.IsLetter(rune(input[end])) {
+			end++
+		}
+		return &queryToken{kind: tokRegex, text: input[start:end], pos: start, value: value.String(), op: input[i+1 : end]}, end, nil
+	}
+	if quote == '"' {
+		return nil, 0, queryErrorAt(input, start, "unterminated quote")
+	}
+	return nil, 0, queryErrorAt(input, start, "unterminated regex")
+}
+
+// wordEndsAt reports whether a word ends at i: at the end of the input, or before
+// whitespace, a parenthesis or a quote
+func wordEndsAt(input string, i int) bool {
+	if i >= len(input) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(input[i:])
+	return unicode.IsSpace(r) || r == '(' || r == ')' || r == '"'
+}
+
+// lexWord reads a bare word. A word naming a field followed by an operator may take
+// a quoted or regex value, as in process:"my app" or process:/api|web/.
+func lexWord(input string, start int) (*queryToken, int, error) {
+	i := start
+	for !wordEndsAt(input, i) {
+		_, size := utf8.DecodeRuneInString(input[i:])
+		i += size
+	}
+	tok := &queryToken{kind: tokWord, text: input[start:i], pos: start}
+
+	name := strings.IndexAny(tok.text, ":<>=")
+	if name <= 0 {
+		return tok, i, nil
+	}
+	rest := tok.text[name:]
+	op := rest[:1]
+	if len(rest) > 1 && rest[1] == '=' && (op == ">" || op == "<") {
+		op = rest[:2]
+	}
+	field, ok := queryFields[strings.ToLower(tok.text[:name])]
+	if !ok {
+		return lexStructuredField(input, tok, name, op, i)
+	}
+	tok.field, tok.op, tok.value = field, op, rest[len(op):]
+
+	// A value starting with / was read as part of the word; a quote ended it
+	if strings.HasPrefix(tok.value, "/") {
+		i = start + name + len(op)
+	}
+	if tok.value == "" || strings.HasPrefix(tok.value, "/") {
+		if i < len(input) && (input[i] == '"' || input[i] == '/') {
+			valueTok, end, err := lexLiteral(input, i)
+			if err != nil {
+				return nil, 0, err
+			}
+			tok.valueTok, tok.value = valueTok, valueTok.value
+			tok.text = input[start:end]
+			return tok, end, nil
+		}
+		if tok.value == "" {
+			return nil, 0, queryErrorAt(input, i, fmt.Sprintf("missing value for %s", field))
+		}
+	}
+	return tok, i, nil
+}
+
+// lexStructuredField reads name<op>value for a field of a structured line. The
+// value may be quoted but not a regex, so words such as http://host stay words.
+func lexStructuredField(input string, tok *queryToken, name int, op string, i int) (*queryToken, int, error) {
+	if !isStructuredFieldName(tok.text[:name]) {
+		return tok, i, nil
+	}
+	tok.value = tok.text[name+len(op):]
+	if tok.value == "" {
+		if i >= len(input) || input[i] != '"' {
+			return tok, i, nil // a word ending in a colon, such as error:
+		}
+		valueTok, end, err := lexLiteral(input, i)
+		if err != nil {
+			return nil, 0, err
+		}
+		tok.valueTok, tok.value = valueTok, valueTok.value
+		tok.text = input[tok.pos:end]
+		i = end
+	}
+	tok.field, tok.name, tok.op = "structured", tok.text[:name], op
+	return tok, i, nil
+}
+
+func isStructuredFieldName(name string) bool {
+	for i, r := range name {
+		if !(r == '_' || r == '@' || unicode.IsLetter(r) || (i > 0 && (unicode.IsDigit(r) || r == '.' || r == '-'))) {
+			return false
+		}
+	}
+	return true
+}
+
+func queryErrorAt(input string, pos int, message string) *QueryError {
+	return &QueryError{Column: utf8.RuneCountInString(input[:pos]) + 1, Message: message}
+}
+
+// Parser
+
+type queryBounds struct {
+	since time.Time
+	until time.Time
+}
+
+type queryParser struct {
+	input  string
+	tokens []*queryToken
+	next   int
+	now    time.Time
+}
+
+func (p *queryParser) peek() *queryToken {
+	if p.next < len(p.tokens) {
+		return p.tokens[p.next]
+	}
+	return nil
+}
+
+func (p *queryParser) isKeyword(tok *queryToken, keyword string) bool {
+	return tok != nil && tok.kind == tokWord && tok.field == "" && tok.text == keyword
+}
+
+func (p *queryParser) errorAt(pos int, message string) *QueryError {
+	return queryErrorAt(p.input, pos, message)
+}
+
+// parseOr parses and-groups separated by OR
+func (p *queryParser) parseOr() (matcher, queryBounds, error) {
+	first, bounds, err := p.parseAnd()
+	if err != nil {
+		return nil, queryBounds{}, err
+	}
+	alternatives := []matcher{first}
+	for p.isKeyword(p.peek(), "OR") {
+		p.next++
+		alt, _, err := p.parseAnd()
+		if err != nil {
+			return nil, queryBounds{}, err
+		}
+		alternatives = append(alternatives, alt)
+	}
+	if len(alternatives) == 1 {
+		return first, bounds, nil
+	}
+	return func(entry LogEntry) bool {
+		for _, alt := range alternatives {
+			if alt(entry) {
+				return true
+			}
+		}
+		return false
+	}, queryBounds{}, nil
+}
+
+// parseAnd parses terms joined by AND or juxtaposition. The since and until terms
+// among them bound the whole group.
+func (p *queryParser) parseAnd() (matcher, queryBounds, error) {
+	var terms []matcher
+	var bounds queryBounds
+	for {
+		tok := p.peek()
+		if tok == nil || tok.kind == tokRParen || p.isKeyword(tok, "OR") {
+			break
+		}
+		if p.isKeyword(tok, "AND") {
+			p.next++
+			if next := p.peek(); next == nil || next.kind == tokRParen || p.isKeyword(next, "OR") {
+				return nil, queryBounds{}, p.errorAt(p.endOf(tok), "expected a term after AND")
+			}
+			continue
+		}
+		term, termBounds, err := p.parseUnary()
+		if err != nil {
+			return nil, queryBounds{}, err
+		}
+		if termBounds.since.After(bounds.since) {
+			bounds.since = termBounds.since
+		}
+		if !termBounds.until.IsZero() && (bounds.until.IsZero() || termBounds.until.Before(bounds.until)) {
+			bounds.until = termBounds.until
+		}
+		terms = append(terms, term)
+	}
+	if len(terms) == 0 {
+		pos := len(p.input)
+		if tok := p.peek(); tok != nil {
+			pos = tok.pos
+		}
+		return nil, queryBounds{}, p.errorAt(pos, "expected a term")
+	}
+	if len(terms) == 1 {
+		return terms[0], bounds, nil
+	}
+	return func(entry LogEntry) bool {
+		for _, term := range terms {
+			if !term(entry) {
+				return false
+			}
+		}
+		return true
+	}, bounds, nil
+}
+
+// parseUnary parses a term, a negated term or a parenthesised query
+func (p *queryParser) parseUnary() (matcher, queryBounds, error) {
+	tok := p.peek()
+	if tok.kind == tokMinus || p.isKeyword(tok, "NOT") {
+		p.next++
+		if next := p.peek(); next == nil || next.kind == tokRParen || p.isKeyword(next, "OR") || p.isKeyword(next, "AND") {
+			return nil, queryBounds{}, p.errorAt(p.endOf(tok), "expected a term after "+tok.text)
+		}
+		term, _, err := p.parseUnary()
+		if err != nil {
+			return nil, queryBounds{}, err
+		}
+		return func(entry LogEntry) bool { return !term(entry) }, queryBounds{}, nil
+	}
+
+	p.next++
+	switch tok.kind {
+	case tokLParen:
+		inner, _, err := p.parseOr()
+		if err != nil {
+			return nil, queryBounds{}, err
+		}
+		if closing := p.peek(); closing == nil || closing.kind != tokRParen {
+			return nil, queryBounds{}, p.errorAt(tok.pos, "unmatched (")
+		}
+		p.next++
+		return inner, queryBounds{}, nil
+	case tokRParen:
+		return nil, queryBounds{}, p.errorAt(tok.pos, "unmatched )")
+	case tokString:
+		return textMatcher(tok.value), queryBounds{}, nil
+	case tokRegex:
+		re, err := p.compileRegex(tok)
+		if err != nil {
+			return nil, queryBounds{}, err
+		}
+		return func(entry LogEntry) bool { return re.MatchString(entry.Content) }, queryBounds{}, nil
+	}
+
+	if tok.field == "" {
+		return textMatcher(tok.text), queryBounds{}, nil
+	}
+	return p.parseField(tok)
+}
+
+func (p *queryParser) endOf(tok *queryToken) int {
+	return tok.pos + len(tok.text)
+}
+
+// valuePos is where a field's value starts
+func (p *queryParser) valuePos(tok *queryToken) int {
+	if tok.valueTok != nil {
+		return tok.valueTok.pos
+	}
+	return tok.pos + len(tok.text) - len(tok.value)
+}
+
+func (p *queryParser) compileRegex(tok *queryToken) (*regexp.Regexp, error) {
+	pattern := tok.value
+	for _, flag := range tok.op {
+		if flag != 'i' {
+			return nil, p.errorAt(tok.pos+len(tok.text)-len(tok.op), fmt.Sprintf("unknown regex flag %q", flag))
+		}
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, p.errorAt(tok.pos, "invalid regex: "+strings.TrimPrefix(err.Error(), "error parsing regexp: "))
+	}
+	return re, nil
+}
+
+// textMatcher matches the content or process name, ignoring case
+func textMatcher(text string) matcher {
+	text = strings.ToLower(text)
+	return func(entry LogEntry) bool {
+		return strings.Contains(strings.ToLower(entry.Content), text) ||
+			strings.Contains(strings.ToLower(entry.ProcessName), text)
+	}
+}
+
+// valueMatcher matches a field value: a regex literal, a glob, or text ignoring case
+func (p *queryParser) valueMatcher(tok *queryToken) (func(string) bool, error) {
+	if tok.valueTok != nil && tok.valueTok.kind == tokRegex {
+		re, err := p.compileRegex(tok.valueTok)
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString, nil
+	}
+	want := strings.ToLower(tok.value)
+	if tok.valueTok == nil && strings.ContainsAny(want, "*?[") {
+		if _, err := path.Match(want, ""); err != nil {
+			return nil, p.errorAt(p.valuePos(tok), "invalid pattern "+tok.value)
+		}
+		return func(value string) bool {
+			matched, _ := path.Match(want, strings.ToLower(value))
+			return matched
+		}, nil
+	}
+	return func(value string) bool { return strings.ToLower(value) == want }, nil
+}
+
+func (p *queryParser) parseField(tok *queryToken) (matcher, queryBounds, error) {
+	comparison := tok.op != ":" && tok.op != "="
+	if tok.field == "structured" {
+		return p.parseStructuredField(tok, comparison)
+	}
+	if comparison && tok.field != "level" && tok.field != "priority" {
+		return nil, queryBounds{}, p.errorAt(tok.pos+strings.Index(tok.text, tok.op), fmt.Sprintf("%s does not support %s", tok.field, tok.op))
+	}
+
+	switch tok.field {
+	case "process":
+		match, err := p.valueMatcher(tok)
+		if err != nil {
+			return nil, queryBounds{}, err
+		}
+		return func(entry LogEntry) bool {
+			return match(entry.ProcessName) || match(entry.ProcessID)
+		}, queryBounds{}, nil
+
+	case "tag":
+		match, err := p.valueMatcher(tok)
+		if err != nil {
+			return nil, queryBounds{}, err
+		}
+		return func(entry LogEntry) bool {
+			for _, tag := range entry.Tags {
+				if match(tag) {
+					return true
+				}
+			}
+			return false
+		}, queryBounds{}, nil
+
+	case "message":
+		if tok.valueTok != nil && tok.valueTok.kind == tokRegex {
+			re, err := p.compileRegex(tok.valueTok)
+			if err != nil {
+				return nil, queryBounds{}, err
+			}
+			return func(entry LogEntry) bool { return re.MatchString(entry.message()) }, queryBounds{}, nil
+		}
+		text := strings.ToLower(tok.value)
+		return func(entry LogEntry) bool {
+			return strings.Contains(strings.ToLower(entry.message()), text)
+		}, queryBounds{}, nil
+
+	case "level":
+		level, ok := ParseLogLevel(tok.value)
+		if !ok {
+			return nil, queryBounds{}, p.errorAt(p.valuePos(tok), fmt.Sprintf("unknown level %q; use debug, info, warn, error or critical", tok.value))
+		}
+		return compareInts(tok.op, level, func(entry LogEntry) LogLevel { return entry.Level }), queryBounds{}, nil
+
+	case "priority":
+		priority, err := strconv.Atoi(tok.value)
+		if err != nil {
+			return nil, queryBounds{}, p.errorAt(p.valuePos(tok), fmt.Sprintf("priority must be a number, not %q", tok.value))
+		}
+		return compareInts(tok.op, priority, func(entry LogEntry) int { return entry.Priority }), queryBounds{}, nil
+
+	case "since", "until":
+		t, err := parseQueryTime(tok.value, p.now)
+		if err != nil {
+			return nil, queryBounds{}, p.errorAt(p.valuePos(tok), err.Error())
+		}
+		if tok.field == "since" {
+			return func(entry LogEntry) bool { return !entry.Timestamp.Before(t) }, queryBounds{since: t}, nil
+		}
+		return func(entry LogEntry) bool { return entry.Timestamp.Before(t) }, queryBounds{until: t}, nil
+	}
+	return nil, queryBounds{}, p.errorAt(tok.pos, "unknown field "+tok.field)
+}
+
+// parseStructuredField matches a field of a JSON or logfmt line. Numbers compare as
+// numbers and anything else as text. Lines without the field match the term as
+// written, as a bare word would.
+func (p *queryParser) parseStructuredField(tok *queryToken, comparison bool) (matcher, queryBounds, error) {
+	text := textMatcher(tok.text)
+	if tok.valueTok != nil {
+		text = textMatcher(tok.name + tok.op + tok.value)
+	}
+	match := func(value string) bool { return compareValues(tok.op, value, tok.value) }
+	if !comparison {
+		match = func(value string) bool { return strings.EqualFold(value, tok.value) }
+		if valueMatch, err := p.valueMatcher(tok); err == nil {
+			match = valueMatch // a bad glob is matched as written, like the word it was before
+		}
+	}
+	return func(entry LogEntry) bool {
+		if entry.Structured != nil {
+			if value, ok := entry.Structured.Field(tok.name); ok && match(value) {
+				return true
+			}
+		}
+		return text(entry)
+	}, queryBounds{}, nil
+}
+
+// compareValues compares a field value with the one in the query, as numbers when
+// both are numbers
+func compareValues(op, got, want string) bool {
+	cmp := strings.Compare(strings.ToLower(got), strings.ToLower(want))
+	gotNum, gotErr := strconv.ParseFloat(got, 64)
+	wantNum, wantErr := strconv.ParseFloat(want, 64)
+	if gotErr == nil && wantErr == nil {
+		cmp = 0
+		if gotNum < wantNum {
+			cmp = -1
+		} else if gotNum > wantNum {
+			cmp = 1
+		}
+	}
+	switch op {
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	}
+	return cmp == 0
+}
+
+func compareInts[T LogLevel | int](op string, want T, get func(LogEntry) T) matcher {
+	return func(entry LogEntry) bool {
+		got := get(entry)
+		switch op {
+		case ">=":
+			return got >= want
+		case "<=":
+			return got <= want
+		case ">":
+			return got > want
+		case "<":
+			return got < want
+		}
+		return got == want
+	}
+}
+
+// ParseLogLevel reads a level name such as warn or error
+func ParseLogLevel(name string) (LogLevel, bool) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return LevelDebug, true
+	case "info":
+		return LevelInfo, true
+	case "warn", "warning":
+		return LevelWarn, true
+	case "error":
+		return LevelError, true
+	case "critical", "fatal":
+		return LevelCritical, true
+	}
+	return 0, false
+}
+
+// parseQueryTime reads a duration back from now (10m, 2h, 3d), an RFC 3339 time, a
+// date, or a time of day today
+func parseQueryTime(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), t.Second(), 0, now.Location()), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q; use a duration such as 10m, a date or a time of day", value)
+}
@@ -0,0 +1,98 @@
+package logs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryMatches(t *testing.T) {
+	now := time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC)
+	entries := []LogEntry{
+		{ProcessName: "api", Content: "request timeout after 30s", Level: LevelWarn, Tags: []string{"build"}, Priority: 20, Timestamp: now.Add(-5 * time.Minute)},
+		{ProcessName: "api", Content: "healthcheck timeout", Level: LevelWarn, Tags: []string{"build"}, Priority: 20, Timestamp: now.Add(-5 * time.Minute)},
+		{ProcessName: "api", Content: "Request TIMEOUT", Level: LevelError, Tags: []string{"build"}, Priority: 80, Timestamp: now.Add(-time.Hour)},
+		{ProcessName: "web", Content: "compiled in 2s", Level: LevelInfo, Timestamp: now.Add(-time.Minute)},
+		{ProcessName: "web:dev", Content: "GET /api/users 500", Level: LevelError, Priority: 50, Timestamp: now.Add(-2 * time.Minute)},
+	}
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{`process:api level>=warn tag:build "timeout" since:10m -"healthcheck"`, []int{0}},
+		{`timeout`, []int{0, 1, 2}},
+		{`process:web*`, []int{3, 4}},
+		{`process:"web:dev"`, []int{4}},
+		{`process:/^w/ AND NOT level:info`, []int{4}},
+		{`level:error OR compiled`, []int{2, 3, 4}},
+		{`(process:web OR tag:build) -timeout`, []int{3}},
+		{`/req\w+ timeout/i`, []int{0, 2}},
+		{`/req\w+ timeout/`, []int{0}},
+		{`"/api/users"`, []int{4}},
+		{`/api/users`, []int{4}},
+		{`/API/users 500`, []int{4}},
+		{`/api/v1/users`, nil},
+		{`/api`, []int{4}},
+		{`web /api/users?id=1`, nil},
+		{`priority>=50 until:30m`, []int{2}},
+		{`msg:/\d+s$/`, []int{0, 3}},
+		{``, []int{0, 1, 2, 3, 4}},
+	}
+	for _, tt := range tests {
+		q, err := parseQueryAt(tt.query, now)
+		require.NoError(t, err, tt.query)
+		var got []int
+		for i, entry := range entries {
+			if q.Match(entry) {
+				got = append(got, i)
+			}
+		}
+		assert.Equal(t, tt.want, got, tt.query)
+	}
+
+	q, err := parseQueryAt(`since:10m timeout until:1m`, now)
+	require.NoError(t, err)
+	page := q.Page(time.Time{}, 5)
+	assert.Equal(t, now.Add(-10*time.Minute), page.Since)
+	assert.Equal(t, now.Add(-time.Minute), page.Before)
+}
+
+func TestQuerySyntaxErrors(t *testing.T) {
+	tests := []struct {
+		query  string
+		column int
+	}{
+		{`process:api "timeout`, 13},
+		{`level>=loud`, 8},
+		{`tag>=build`, 4},
+		{`(process:api OR web`, 1},
+		{`process:api)`, 12},
+		{`since:yesterday`, 7},
+		{`timeout /[a-/`, 9},
+		{`api OR`, 7},
+		{`api NOT`, 8},
+		{`process:`, 9},
+		{`priority>x`, 10},
+	}
+	for _, tt := range tests {
+		_, err := ParseQuery(tt.query)
+		var queryErr *QueryError
+		require.ErrorAs(t, err, &queryErr, tt.query)
+		assert.Equal(t, tt.column, queryErr.Column, "%s: %v", tt.query, err)
+	}
+}
+
+func TestSearchPagePathQuery(t *testing.T) {
+	store := NewStore(10, nil)
+	defer store.Close()
+	store.addSync("web", "web", "GET /api/users 200", false)
+	store.addSync("web", "web", "GET /api/orders 200", false)
+
+	// A path is not a /regex/flags, so it is searched for as text
+	page, err := store.SearchPage("/api/users", time.Time{}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET /api/users 200"}, contents(page))
+}
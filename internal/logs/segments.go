@@ -0,0 +1,286 @@
+package logs
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Log history on disk. Each brummer instance appends entries as JSON lines to its
+// own directory under the project's history directory. A segment is named after the
+// timestamp of its first entry, so segments sort by time across instances and a
+// query can skip the ones outside its range.
+
+const segmentExt = ".log"
+
+// Retention limits the history kept for a project
+type Retention struct {
+	MaxBytes     int64         // total size of all segments; the oldest go first
+	MaxAge       time.Duration // segments not written to for this long are deleted
+	SegmentBytes int64         // size at which a new segment is started
+}
+
+// PageQuery selects a page of log entries, newest first. Entries are matched in
+// reverse order until Limit is reached; the page itself is returned oldest first.
+type PageQuery struct {
+	Match  func(LogEntry) bool // nil matches every entry
+	Since  time.Time           // only entries logged at or after this; zero means no bound
+	Before time.Time           // only entries logged before this; zero means now
+	Limit  int                 // maximum entries returned; zero or less means no limit
+}
+
+func (q PageQuery) matches(entry LogEntry) bool {
+	if !q.Since.IsZero() && entry.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Before.IsZero() && !entry.Timestamp.Before(q.Before) {
+		return false
+	}
+	return q.Match == nil || q.Match(entry)
+}
+
+type segment struct {
+	path  string
+	first time.Time
+}
+
+// SegmentStore is an append-only log history for one brummer instance
+type SegmentStore struct {
+	projectDir string
+	dir        string
+	retention  Retention
+
+	mu          sync.Mutex
+	segments    []segment // this instance's segments, oldest first
+	current     *os.File
+	currentSize int64
+}
+
+// HistoryDir returns the directory that holds the log history of a project. Root
+// defaults to brummer's directory in the user cache directory.
+func HistoryDir(root, workDir string) string {
+	if root == "" {
+		cache, err := os.UserCacheDir()
+		if err != nil {
+			cache = os.TempDir()
+		}
+		root = filepath.Join(cache, "brummer", "logs")
+	}
+	sum := sha256.Sum256([]byte(workDir))
+	return filepath.Join(root, hex.EncodeToString(sum[:8]))
+}
+
+// OpenSegmentStore starts the history of a new instance in projectDir, and applies
+// retention to what earlier instances left there
+func OpenSegmentStore(projectDir string, retention Retention) (*SegmentStore, error) {
+	dir := filepath.Join(projectDir, fmt.Sprintf("%s-%d", time.Now().Format("20060102-150405"), os.Getpid()))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log history directory: %w", err)
+	}
+	s := &SegmentStore{
+		projectDir: projectDir,
+		dir:        dir,
+		retention:  retention,
+	}
+	s.enforceRetention()
+	return s, nil
+}
+
+// Dir returns the directory this instance writes its segments to
+func (s *SegmentStore) Dir() string {
+	return s.dir
+}
+
+// Append writes an entry to the current segment, starting a new one when it is full
+func (s *SegmentStore) Append(entry LogEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current != nil && s.retention.SegmentBytes > 0 && s.currentSize+int64(len(line)) > s.retention.SegmentBytes {
+		s.current.Close()
+		s.current = nil
+		s.enforceRetention()
+	}
+	if s.current == nil {
+		path := filepath.Join(s.dir, fmt.Sprintf("%020d%s", entry.Timestamp.UnixNano(), segmentExt))
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open log segment: %w", err)
+		}
+		s.current, s.currentSize = file, 0
+		s.segments = append(s.segments, segment{path: path, first: entry.Timestamp})
+	}
+
+	n, err := s.current.Write(line)
+	s.currentSize += int64(n)
+	return err
+}
+
+// Query returns the page of this instance's history that q selects
+func (s *SegmentStore) Query(q PageQuery) ([]LogEntry, error) {
+	s.mu.Lock()
+	segments := make([]segment, len(s.segments))
+	copy(segments, s.segments)
+	s.mu.Unlock()
+
+	var pages [][]LogEntry
+	found := 0
+	for i := len(segments) - 1; i >= 0; i-- {
+		seg := segments[i]
+		if !q.Before.IsZero() && !seg.first.Before(q.Before) {
+			continue // starts after the page
+		}
+		if !q.Since.IsZero() && i+1 < len(segments) && !segments[i+1].first.After(q.Since) {
+			break // ends before the page, and so do all older segments
+		}
+
+		entries, err := readSegment(seg.path, q)
+		if err != nil {
+			if os.IsNotExist(err) {
+				break // removed by retention, as were all older segments
+			}
+			return nil, err
+		}
+		if q.Limit > 0 && found+len(entries) > q.Limit {
+			entries = entries[found+len(entries)-q.Limit:]
+		}
+		pages = append(pages, entries)
+		found += len(entries)
+		if q.Limit > 0 && found >= q.Limit {
+			break
+		}
+	}
+
+	result := make([]LogEntry, 0, found)
+	for i := len(pages) - 1; i >= 0; i-- {
+		result = append(result, pages[i]...)
+	}
+	return result, nil
+}
+
+// readSegment returns the entries of a segment that q selects, oldest first
+func readSegment(path string, q PageQuery) ([]LogEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []LogEntry
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var entry LogEntry
+			// A line cut short by a crash is skipped
+			if json.Unmarshal(line, &entry) == nil && q.matches(entry) {
+				entries = append(entries, entry)
+			}
+		}
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return entries, err
+		}
+	}
+}
+
+// enforceRetention deletes segments of every instance in the project directory that
+// are older than MaxAge, then the oldest ones until the history fits in MaxBytes. The
+// segment being written to is always kept. Callers hold s.mu or own s exclusively.
+func (s *SegmentStore) enforceRetention() {
+	type segmentFile struct {
+		path    string
+		name    string
+		size    int64
+		modTime time.Time
+	}
+	var files []segmentFile
+	var total int64
+	instances, _ := os.ReadDir(s.projectDir)
+	for _, instance := range instances {
+		if !instance.IsDir() {
+			continue
+		}
+		dir := filepath.Join(s.projectDir, instance.Name())
+		entries, _ := os.ReadDir(dir)
+		if len(entries) == 0 && dir != s.dir {
+			os.Remove(dir)
+			continue
+		}
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil || !strings.HasSuffix(entry.Name(), segmentExt) {
+				continue
+			}
+			files = append(files, segmentFile{
+				path:    filepath.Join(dir, entry.Name()),
+				name:    entry.Name(),
+				size:    info.Size(),
+				modTime: info.ModTime(),
+			})
+			total += info.Size()
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].name < files[j].name })
+
+	var active string
+	if s.current != nil {
+		active = s.current.Name()
+	}
+	cutoff := time.Now().Add(-s.retention.MaxAge)
+	removed := make(map[string]bool)
+	for _, file := range files {
+		if file.path == active {
+			continue
+		}
+		expired := s.retention.MaxAge > 0 && file.modTime.Before(cutoff)
+		oversize := s.retention.MaxBytes > 0 && total > s.retention.MaxBytes
+		if !expired && !oversize {
+			continue
+		}
+		if os.Remove(file.path) == nil {
+			removed[file.path] = true
+			total -= file.size
+			if dir := filepath.Dir(file.path); dir != s.dir {
+				os.Remove(dir) // only succeeds once an earlier instance's last segment is gone
+			}
+		}
+	}
+
+	kept := s.segments[:0]
+	for _, seg := range s.segments {
+		if !removed[seg.path] {
+			kept = append(kept, seg)
+		}
+	}
+	s.segments = kept
+}
+
+// Close closes the current segment
+func (s *SegmentStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.current == nil {
+		return nil
+	}
+	err := s.current.Close()
+	s.current = nil
+	return err
+}
@@ -0,0 +1,119 @@
+package logs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func contents(entries []LogEntry) []string {
+	result := make([]string, len(entries))
+	for i, entry := range entries {
+		result[i] = entry.Content
+	}
+	return result
+}
+
+func TestStorePagesIntoHistory(t *testing.T) {
+	history, err := OpenSegmentStore(t.TempDir(), Retention{SegmentBytes: 512})
+	require.NoError(t, err)
+	store := NewStore(5, nil)
+	store.SetHistory(history)
+	defer store.Close()
+
+	for i := 0; i < 20; i++ {
+		process := "web"
+		if i%2 == 1 {
+			process = "api"
+		}
+		store.addSync(process, process, fmt.Sprintf("line %d", i), false)
+	}
+	require.Len(t, store.GetAll(), 5)
+
+	// The newest page comes from memory, older ones from the segments on disk
+	page := store.GetByProcessPage("web", time.Time{}, 4)
+	assert.Equal(t, []string{"line 12", "line 14", "line 16", "line 18"}, contents(page))
+	page = store.GetByProcessPage("web", page[0].Timestamp, 4)
+	assert.Equal(t, []string{"line 4", "line 6", "line 8", "line 10"}, contents(page))
+	page = store.GetByProcessPage("web", page[0].Timestamp, 4)
+	assert.Equal(t, []string{"line 0", "line 2"}, contents(page))
+
+	page, err = store.SearchPage("LINE 1", time.Time{}, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"line 17", "line 18", "line 19"}, contents(page))
+	page, err = store.SearchPage("line 1", time.Time{}, 0)
+	require.NoError(t, err)
+	assert.Len(t, page, 11)
+
+	// Reads without a page take as many entries as the window holds
+	assert.Equal(t, []string{"line 10", "line 12", "line 14", "line 16", "line 18"}, contents(store.GetByProcess("web")))
+	assert.Equal(t, []string{"line 15", "line 16", "line 17", "line 18", "line 19"}, contents(store.Search("line 1")))
+	assert.Equal(t, []string{"line 16", "line 18"}, contents(store.GetByProcessInMemory("web")))
+
+	segments, _ := filepath.Glob(filepath.Join(history.Dir(), "*.log"))
+	assert.Greater(t, len(segments), 1)
+}
+
+func TestStoreReportsDroppedHistory(t *testing.T) {
+	history, err := OpenSegmentStore(t.TempDir(), Retention{})
+	require.NoError(t, err)
+	store := NewStore(100, nil)
+	store.SetHistory(history)
+	defer store.Close()
+
+	// Entries the writer could not keep up with are counted and logged once it catches up
+	store.historyDropped.Store(3)
+	store.Add("web", "web", "caught up", false)
+	require.Eventually(t, func() bool {
+		return len(store.GetByProcessInMemory("system")) == 1
+	}, time.Second, 10*time.Millisecond)
+	notice := store.GetByProcessInMemory("system")[0]
+	assert.Contains(t, notice.Content, "3 entries were kept in memory only")
+	assert.True(t, notice.IsError)
+	assert.Zero(t, store.historyDropped.Load())
+}
+
+func TestSegmentRetention(t *testing.T) {
+	projectDir := t.TempDir()
+
+	// A segment left by an earlier instance that has aged out
+	old := filepath.Join(projectDir, "earlier", fmt.Sprintf("%020d.log", time.Now().Add(-48*time.Hour).UnixNano()))
+	require.NoError(t, os.MkdirAll(filepath.Dir(old), 0755))
+	require.NoError(t, os.WriteFile(old, []byte("{}\n"), 0644))
+	require.NoError(t, os.Chtimes(old, time.Now().Add(-48*time.Hour), time.Now().Add(-48*time.Hour)))
+
+	history, err := OpenSegmentStore(projectDir, Retention{MaxBytes: 2048, MaxAge: 24 * time.Hour, SegmentBytes: 512})
+	require.NoError(t, err)
+	defer history.Close()
+	assert.NoDirExists(t, filepath.Dir(old))
+
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		require.NoError(t, history.Append(LogEntry{
+			ProcessID: "web",
+			Timestamp: start.Add(time.Duration(i) * time.Millisecond),
+			Content:   fmt.Sprintf("line %d", i),
+		}))
+	}
+
+	var total int64
+	segments, _ := filepath.Glob(filepath.Join(history.Dir(), "*.log"))
+	for _, segment := range segments {
+		info, err := os.Stat(segment)
+		require.NoError(t, err)
+		total += info.Size()
+	}
+	assert.LessOrEqual(t, total, int64(2048+512))
+
+	// The oldest lines are gone; the newest are all still there
+	entries, err := history.Query(PageQuery{})
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	assert.NotEqual(t, "line 0", entries[0].Content)
+	assert.Equal(t, "line 99", entries[len(entries)-1].Content)
+}
@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/standardbeagle/brummer/pkg/events"
@@ -37,6 +38,7 @@ type LogEntry struct {
 	IsError     bool
 	Tags        []string
 	Priority    int
+	Structured  *StructuredLog // set for JSON and logfmt lines
 }
 
 // CollapsedLogEntry represents a log entry that may contain multiple identical consecutive logs
@@ -64,6 +66,30 @@ type Store struct {
 	// Event bus for publishing LogLine events
 	eventBus EventBus
 
+	// History on disk; entries logged before windowStart are read from it. Entries
+	// reach it through historyQueue so that writes happen outside mu. historyMu is
+	// held to send on historyQueue, and by Close to close it.
+	history        *SegmentStore
+	historyMu      sync.RWMutex
+	historyQueue   chan historyWrite
+	historyDone    chan struct{} // closed once writeHistory has returned
+	historyDropped atomic.Int64  // entries not queued while the writer was behind
+	windowStart    time.Time
+
+	// Maps frames of bundled or minified JavaScript back to their sources. Stacks are
+	// resolved by watchErrors and kept, by the stack as logged, in resolvedStacks.
+	// Stacks from outside the log, such as browser errors, are resolved while they
+	// are still asked for; requestedStacks holds when each was last asked for.
+	stackResolver   StackResolver
+	resolvedStacks  map[string][]string // nil when nothing in the stack changed
+	requestedStacks map[string]time.Time
+	stacksPending   atomic.Bool
+
+	// Occurrences of each error; errors are counted once their group has settled
+	fingerprints  *FingerprintStore
+	errorsPending atomic.Bool
+	errorWatch    sync.Once
+
 	// Channel-based async operations
 	addChan   chan *addLogRequest
 	closeChan chan struct{}
@@ -79,6 +105,13 @@ type URLEntry struct {
 	Context     string
 }
 
+// historyWrite is an entry for history, or with flushed set, a request to be told
+// once everything queued before it has been written
+type historyWrite struct {
+	entry   LogEntry
+	flushed chan struct{}
+}
+
 type addLogRequest struct {
 	processID   string
 	processName string
@@ -173,6 +206,21 @@ func (s *Store) processAddRequests() {
 }
 
 func (s *Store) addSync(processID, processName, content string, isError bool) *LogEntry {
+	// A structured line states its level, and only its message and error are read
+	// for keywords. Lines are parsed before taking the lock, which readers share.
+	structured := ParseStructured(content)
+	text := content
+	if structured != nil {
+		text = structured.Text()
+	}
+	level := s.detectLogLevel(text, isError)
+	if structured != nil {
+		if stated, ok := structured.LogLevel(); ok {
+			level = stated
+		}
+	}
+	tags := s.extractTags(text)
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -183,12 +231,26 @@ func (s *Store) addSync(processID, processName, content string, isError bool) *L
 		Timestamp:   time.Now(),
 		Content:     content,
 		IsError:     isError,
-		Level:       s.detectLogLevel(content, isError),
-		Tags:        s.extractTags(content),
-		Priority:    s.calculatePriority(content, isError),
+		Level:       level,
+		Tags:        tags,
+		Priority:    s.calculatePriority(text, isError || (structured != nil && level >= LevelError)),
+		Structured:  structured,
+	}
+
+	s.historyMu.RLock()
+	if s.historyQueue != nil {
+		// History is best effort: while the writer is behind, entries only stay in
+		// memory. writeHistory reports how many once it catches up.
+		select {
+		case s.historyQueue <- historyWrite{entry: entry}:
+		default:
+			s.historyDropped.Add(1)
+		}
 	}
+	s.historyMu.RUnlock()
 
 	if len(s.entries) >= s.maxEntries {
+		s.windowStart = s.entries[0].Timestamp.Add(time.Nanosecond)
 		s.entries = s.entries[1:]
 		for pid, indices := range s.byProcess {
 			for i := range indices {
@@ -204,6 +266,8 @@ func (s *Store) addSync(processID, processName, content string, isError bool) *L
 
 	// Track errors with enhanced parsing
 	if isError || entry.Level >= LevelError {
+		s.errorsPending.Store(true)
+		s.stacksPending.Store(true)
 		s.errors = append(s.errors, entry)
 		if len(s.errors) > 100 {
 			s.errors = s.errors[1:]
@@ -333,7 +397,18 @@ func (s *Store) calculatePriority(content string, isError bool) int {
 	return priority
 }
 
+// GetByProcess returns the newest entries of a process, as many as the in-memory
+// window holds. With history, those the window has dropped are read from it.
 func (s *Store) GetByProcess(processID string) []LogEntry {
+	if s.hasHistory() {
+		return s.GetByProcessPage(processID, time.Time{}, s.maxEntries)
+	}
+	return s.GetByProcessInMemory(processID)
+}
+
+// GetByProcessInMemory returns the entries of a process in the in-memory window,
+// without reading history. It suits views redrawn on every frame.
+func (s *Store) GetByProcessInMemory(processID string) []LogEntry {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -361,16 +436,24 @@ func (s *Store) GetAll() []LogEntry {
 	return result
 }
 
+// Search returns the newest entries that match a query (see ParseQuery), as many as
+// the in-memory window holds. With history, older matches are read from it. A query
+// that does not parse is matched as plain text.
 func (s *Store) Search(query string) []LogEntry {
+	q, err := ParseQuery(query)
+	if err != nil {
+		q = &Query{match: textMatcher(query)}
+	}
+	if s.hasHistory() {
+		return s.Page(q.Page(time.Time{}, s.maxEntries))
+	}
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	query = strings.ToLower(query)
 	result := []LogEntry{}
-
 	for _, entry := range s.entries {
-		if strings.Contains(strings.ToLower(entry.Content), query) ||
-			strings.Contains(strings.ToLower(entry.ProcessName), query) {
+		if q.Match(entry) {
 			result = append(result, entry)
 		}
 	}
@@ -378,6 +461,196 @@ func (s *Store) Search(query string) []LogEntry {
 	return result
 }
 
+// SetHistory keeps every entry added from now on in history, and pages queries into it
+// once entries leave the in-memory window. It is called once, before logging starts.
+func (s *Store) SetHistory(history *SegmentStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.historyMu.Lock()
+	defer s.historyMu.Unlock()
+	s.history = history
+	s.historyQueue = make(chan historyWrite, 1000)
+	s.historyDone = make(chan struct{})
+	go s.writeHistory(history, s.historyQueue, s.historyDone)
+}
+
+func (s *Store) hasHistory() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.history != nil
+}
+
+// writeHistory appends queued entries to history until the queue is closed. Once it
+// has caught up, it logs how many entries were left out while it was behind.
+func (s *Store) writeHistory(history *SegmentStore, queue <-chan historyWrite, done chan<- struct{}) {
+	defer close(done)
+	for write := range queue {
+		if write.flushed != nil {
+			close(write.flushed)
+		} else {
+			history.Append(write.entry)
+		}
+		if len(queue) == 0 {
+			s.reportHistoryDropped()
+		}
+	}
+}
+
+// reportHistoryDropped logs how many entries were left out of history. The writer
+// must never wait on the store, so when the log is busy the count is kept for later.
+func (s *Store) reportHistoryDropped() {
+	dropped := s.historyDropped.Swap(0)
+	if dropped == 0 {
+		return
+	}
+	select {
+	case s.addChan <- &addLogRequest{
+		processID:   "system",
+		processName: "System",
+		content:     fmt.Sprintf("⚠️ Log history fell behind: %d entries were kept in memory only", dropped),
+		isError:     true,
+	}:
+	default:
+		s.historyDropped.Add(dropped)
+	}
+}
+
+// flushHistory waits until every entry queued for history so far has been written
+func (s *Store) flushHistory() {
+	s.historyMu.RLock()
+	if s.historyQueue == nil {
+		s.historyMu.RUnlock()
+		return
+	}
+	flushed := make(chan struct{})
+	s.historyQueue <- historyWrite{flushed: flushed}
+	s.historyMu.RUnlock()
+	<-flushed
+}
+
+// StackResolver rewrites stack frames of generated code to their original sources.
+// The result has one line per input line.
+type StackResolver interface {
+	ResolveStack(lines []string) []string
+}
+
+// SetStackResolver sets the resolver applied to the stacks of error contexts. Stacks
+// are resolved in the background, shortly after their error is logged.
+func (s *Store) SetStackResolver(resolver StackResolver) {
+	s.mu.Lock()
+	s.stackResolver = resolver
+	s.resolvedStacks = make(map[string][]string)
+	s.requestedStacks = make(map[string]time.Time)
+	s.mu.Unlock()
+
+	s.stacksPending.Store(true)
+	s.watchErrors()
+}
+
+// ResolvedStack returns stack lines as resolved in the background, and whether any
+// line changed. A stack not resolved yet is returned as is and resolved shortly after.
+func (s *Store) ResolvedStack(lines []string) ([]string, bool) {
+	key := strings.Join(lines, "\n")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.resolvedStacks == nil {
+		return lines, false
+	}
+	s.requestedStacks[key] = time.Now()
+	resolved, done := s.resolvedStacks[key]
+	if !done {
+		s.stacksPending.Store(true)
+	}
+	if resolved == nil {
+		return lines, false
+	}
+	return resolved, true
+}
+
+// resolveStack rewrites stack lines through the stack resolver. It reports whether
+// any line changed.
+func (s *Store) resolveStack(lines []string) ([]string, bool) {
+	s.mu.RLock()
+	resolver := s.stackResolver
+	s.mu.RUnlock()
+	if resolver == nil {
+		return lines, false
+	}
+	resolved := resolver.ResolveStack(lines)
+	for i := range lines {
+		if resolved[i] != lines[i] {
+			return resolved, true
+		}
+	}
+	return lines, false
+}
+
+// Page returns the entries q selects. The in-memory window is searched first and
+// history, when there is one, for entries older than the window.
+func (s *Store) Page(q PageQuery) []LogEntry {
+	s.mu.RLock()
+	history, windowStart := s.history, s.windowStart
+	if history == nil {
+		windowStart = time.Time{}
+	}
+	var newest []LogEntry
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		entry := s.entries[i]
+		if entry.Timestamp.Before(windowStart) || (!q.Since.IsZero() && entry.Timestamp.Before(q.Since)) {
+			break
+		}
+		if q.matches(entry) {
+			newest = append(newest, entry)
+			if q.Limit > 0 && len(newest) >= q.Limit {
+				break
+			}
+		}
+	}
+	paging := history != nil && !windowStart.IsZero() && (q.Limit <= 0 || len(newest) < q.Limit)
+	s.mu.RUnlock()
+
+	result := make([]LogEntry, 0, len(newest))
+	if paging {
+		// Entries that just left the window may still be queued for history
+		s.flushHistory()
+		older := q
+		if older.Before.IsZero() || windowStart.Before(older.Before) {
+			older.Before = windowStart
+		}
+		if q.Limit > 0 {
+			older.Limit = q.Limit - len(newest)
+		}
+		if entries, err := history.Query(older); err == nil {
+			result = append(result, entries...)
+		}
+	}
+	for i := len(newest) - 1; i >= 0; i-- {
+		result = append(result, newest[i])
+	}
+	return result
+}
+
+// GetByProcessPage returns up to limit entries of a process logged before the given
+// time, reaching into history beyond the in-memory window. A zero before means now.
+func (s *Store) GetByProcessPage(processID string, before time.Time, limit int) []LogEntry {
+	return s.Page(PageQuery{
+		Match:  func(entry LogEntry) bool { return entry.ProcessID == processID },
+		Before: before,
+		Limit:  limit,
+	})
+}
+
+// SearchPage returns up to limit entries matching a query that were logged before the
+// given time, reaching into history beyond the in-memory window. A zero before means
+// now. The error is a *QueryError when the query does not parse.
+func (s *Store) SearchPage(query string, before time.Time, limit int) ([]LogEntry, error) {
+	q, err := ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return s.Page(q.Page(before, limit)), nil
+}
+
 func (s *Store) GetHighPriority(threshold int) []LogEntry {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -527,6 +800,22 @@ func (s *Store) Close() {
 	// Clean shutdown - no need to finalize clusters since we use functional grouping
 	close(s.closeChan)
 	s.wg.Wait()
+
+	if s.fingerprints != nil {
+		s.trackErrors(true)
+	}
+
+	s.historyMu.Lock()
+	queue, done := s.historyQueue, s.historyDone
+	s.historyQueue = nil
+	s.historyMu.Unlock()
+	if queue != nil {
+		close(queue)
+		<-done
+	}
+	if s.history != nil {
+		s.history.Close()
+	}
 }
 
 func (s *Store) ClearLogs() {
@@ -535,6 +824,7 @@ func (s *Store) ClearLogs() {
 
 	s.entries = make([]LogEntry, 0, s.maxEntries)
 	s.byProcess = make(map[string][]int)
+	s.windowStart = time.Now() // cleared entries stay in history
 }
 
 func (s *Store) ClearErrors() {
@@ -559,6 +849,7 @@ func (s *Store) ClearLogsForProcess(processName string) {
 		}
 	}
 	s.entries = newEntries
+	s.windowStart = time.Now() // cleared entries stay in history
 
 	// Rebuild the byProcess index
 	s.byProcess = make(map[string][]int)
@@ -579,10 +870,216 @@ func (s *Store) ClearLogsForProcess(processName string) {
 	// No need to clear them separately since they're derived from the filtered entries
 }
 
-// GetErrorContexts returns parsed error contexts with full details
+// GetErrorContexts returns parsed error contexts with full details. Errors marked
+// ignored, or resolved before they occurred, are left out.
 func (s *Store) GetErrorContexts() []ErrorContext {
 	// Use functional grouping to generate error contexts on-demand
-	return s.GetErrorContextsFromFunctionalGrouping()
+	contexts := s.GetErrorContextsFromFunctionalGrouping()
+
+	s.mu.RLock()
+	fingerprints := s.fingerprints
+	s.mu.RUnlock()
+
+	visible := contexts[:0]
+	for _, ctx := range contexts {
+		s.identifyError(&ctx)
+		if fingerprints != nil {
+			if fp, ok := fingerprints.Get(ctx.Fingerprint); ok && fp.hides(ctx.Timestamp) {
+				continue
+			}
+		}
+		visible = append(visible, ctx)
+	}
+	return visible
+}
+
+// identifyError fingerprints an error context and rewrites its stack to the one
+// resolved through source maps, once there is one, keeping the frames as logged
+func (s *Store) identifyError(ctx *ErrorContext) {
+	ctx.Fingerprint = Fingerprint(*ctx)
+	if len(ctx.Stack) > 0 {
+		s.mu.RLock()
+		resolved := s.resolvedStacks[strings.Join(ctx.Stack, "\n")]
+		s.mu.RUnlock()
+		if resolved != nil {
+			ctx.GeneratedStack = ctx.Stack
+			ctx.Stack = resolved
+		}
+	}
+}
+
+// SetFingerprints starts counting error occurrences in f. It is called once.
+func (s *Store) SetFingerprints(f *FingerprintStore) {
+	s.mu.Lock()
+	s.fingerprints = f
+	s.mu.Unlock()
+
+	s.watchErrors()
+}
+
+// watchErrors starts the goroutine that resolves the stacks of new errors and
+// records their occurrences, away from the callers of GetErrorContexts
+func (s *Store) watchErrors() {
+	s.errorWatch.Do(func() {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			ticker := time.NewTicker(500 * time.Millisecond)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					s.resolveStacks()
+					s.mu.RLock()
+					tracking := s.fingerprints != nil
+					s.mu.RUnlock()
+					if tracking {
+						s.trackErrors(false)
+					}
+				case <-s.closeChan:
+					return
+				}
+			}
+		}()
+	})
+}
+
+// requestedStackTTL is how long a stack asked for through ResolvedStack is kept
+const requestedStackTTL = 10 * time.Minute
+
+// resolveStacks resolves the stacks of the error contexts, and those asked for through
+// ResolvedStack, that have not been resolved yet. It forgets the stacks of errors that
+// have left the window and of those no longer asked for.
+func (s *Store) resolveStacks() {
+	if !s.stacksPending.Swap(false) {
+		return
+	}
+	s.mu.RLock()
+	resolving := s.resolvedStacks != nil
+	var stacks [][]string
+	for key, asked := range s.requestedStacks {
+		if time.Since(asked) < requestedStackTTL {
+			stacks = append(stacks, strings.Split(key, "\n"))
+		}
+	}
+	s.mu.RUnlock()
+	if !resolving {
+		return
+	}
+	for _, ctx := range s.GetErrorContextsFromFunctionalGrouping() {
+		if len(ctx.Stack) > 0 {
+			stacks = append(stacks, ctx.Stack)
+		}
+	}
+
+	current := make(map[string]bool)
+	resolved := make(map[string][]string)
+	for _, stack := range stacks {
+		key := strings.Join(stack, "\n")
+		current[key] = true
+		s.mu.RLock()
+		_, done := s.resolvedStacks[key]
+		s.mu.RUnlock()
+		if _, seen := resolved[key]; done || seen {
+			continue
+		}
+		if lines, ok := s.resolveStack(stack); ok {
+			resolved[key] = lines
+		} else {
+			resolved[key] = nil
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.resolvedStacks {
+		if !current[key] {
+			delete(s.resolvedStacks, key)
+		}
+	}
+	for key, asked := range s.requestedStacks {
+		if time.Since(asked) >= requestedStackTTL {
+			delete(s.requestedStacks, key)
+		}
+	}
+	for key, lines := range resolved {
+		s.resolvedStacks[key] = lines
+	}
+}
+
+// trackErrors records the error groups that have settled, that is, that no new
+// entry can join. When final, every group is recorded.
+func (s *Store) trackErrors(final bool) {
+	if !s.errorsPending.Swap(false) && !final {
+		return
+	}
+
+	s.mu.RLock()
+	fingerprints, config := s.fingerprints, s.groupingConfig
+	var errorEntries []LogEntry
+	for _, entry := range s.entries {
+		if isErrorEntry(entry) {
+			errorEntries = append(errorEntries, entry)
+		}
+	}
+	var oldest time.Time
+	if len(s.entries) > 0 {
+		oldest = s.entries[0].Timestamp
+	}
+	s.mu.RUnlock()
+
+	now := time.Now()
+	for _, group := range GroupErrorsByTimeLocality(errorEntries, config) {
+		if !final && now.Sub(group.EndTime) <= config.TimeGapThreshold && len(group.Entries) < config.MaxGroupSize {
+			s.errorsPending.Store(true) // look again once it settles
+			continue
+		}
+		// A group keeps its last entry while older entries leave the window
+		occurrence := fmt.Sprintf("%s-%d", group.ProcessID, group.EndTime.UnixNano())
+		if fingerprints.Seen(occurrence) {
+			continue
+		}
+		ctx := convertErrorGroupToContext(group)
+		s.identifyError(&ctx)
+		fingerprints.Record(occurrence, ctx)
+	}
+
+	fingerprints.Forget(oldest)
+	fingerprints.Save() // best effort, like history
+}
+
+// GetErrorFingerprints returns every error seen in this project, most recent first
+func (s *Store) GetErrorFingerprints() ([]ErrorFingerprint, error) {
+	s.mu.RLock()
+	fingerprints := s.fingerprints
+	s.mu.RUnlock()
+	if fingerprints == nil {
+		return nil, fmt.Errorf("error fingerprints are not being tracked")
+	}
+	return fingerprints.List(), nil
+}
+
+// GetErrorFingerprint returns the occurrences and status of one error
+func (s *Store) GetErrorFingerprint(fingerprint string) (ErrorFingerprint, bool) {
+	s.mu.RLock()
+	fingerprints := s.fingerprints
+	s.mu.RUnlock()
+	if fingerprints == nil {
+		return ErrorFingerprint{}, false
+	}
+	return fingerprints.Get(fingerprint)
+}
+
+// SetErrorStatus marks an error open, resolved or ignored by its fingerprint or a
+// unique prefix of it
+func (s *Store) SetErrorStatus(fingerprint string, status ErrorStatus) (ErrorFingerprint, error) {
+	s.mu.RLock()
+	fingerprints := s.fingerprints
+	s.mu.RUnlock()
+	if fingerprints == nil {
+		return ErrorFingerprint{}, fmt.Errorf("error fingerprints are not being tracked")
+	}
+	return fingerprints.SetStatus(fingerprint, status)
 }
 
 // GetErrorContextsFromFunctionalGrouping generates error contexts using the functional grouping algorithm
@@ -1,6 +1,8 @@
 package logs
 
 import (
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -118,3 +120,87 @@ func TestUpdateProxyURL(t *testing.T) {
 		t.Errorf("UpdateProxyURL() changed original URL: got %q, want %q", urls[0].URL, originalURL)
 	}
 }
+
+// bundleResolver maps frames of bundle.js to src/app.ts and counts its calls
+type bundleResolver struct {
+	calls atomic.Int32
+}
+
+func (r *bundleResolver) ResolveStack(lines []string) []string {
+	r.calls.Add(1)
+	resolved := make([]string, len(lines))
+	for i, line := range lines {
+		resolved[i] = strings.ReplaceAll(line, "bundle.js", "src/app.ts")
+	}
+	return resolved
+}
+
+func TestStoreResolvesStacksInBackground(t *testing.T) {
+	store := NewStore(100, nil)
+	defer store.Close()
+	resolver := &bundleResolver{}
+	store.SetStackResolver(resolver)
+
+	store.addSync("web", "web", "TypeError: boom", true)
+	store.addSync("web", "web", "    at render (http://localhost:3000/bundle.js:1:200)", true)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		contexts := store.GetErrorContexts()
+		if len(contexts) != 1 {
+			t.Fatalf("expected 1 error context, got %d", len(contexts))
+		}
+		if len(contexts[0].GeneratedStack) > 0 {
+			if !strings.Contains(contexts[0].Stack[0], "src/app.ts") || !strings.Contains(contexts[0].GeneratedStack[0], "bundle.js") {
+				t.Fatalf("unexpected stacks %q and %q", contexts[0].Stack, contexts[0].GeneratedStack)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("stack was not resolved")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	// Reading error contexts does not resolve their stacks again
+	for i := 0; i < 5; i++ {
+		store.GetErrorContexts()
+	}
+	if calls := resolver.calls.Load(); calls != 1 {
+		t.Errorf("expected the stack to be resolved once, got %d calls", calls)
+	}
+}
+
+func TestStoreResolvesRequestedStacksInBackground(t *testing.T) {
+	store := NewStore(100, nil)
+	defer store.Close()
+	resolver := &bundleResolver{}
+	store.SetStackResolver(resolver)
+
+	// A stack from outside the log is returned as is until it has been resolved
+	stack := []string{"at render (http://localhost:3000/bundle.js:1:200)"}
+	if _, ok := store.ResolvedStack(stack); ok {
+		t.Fatal("stack was resolved while being read")
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		if resolved, ok := store.ResolvedStack(stack); ok {
+			if !strings.Contains(resolved[0], "src/app.ts") {
+				t.Fatalf("unexpected stack %q", resolved)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("stack was not resolved")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	for i := 0; i < 5; i++ {
+		store.ResolvedStack(stack)
+	}
+	if calls := resolver.calls.Load(); calls != 1 {
+		t.Errorf("expected the stack to be resolved once, got %d calls", calls)
+	}
+}
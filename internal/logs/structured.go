@@ -0,0 +1,298 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Structured log lines: JSON as written by pino, bunyan, zap, winston or slog, and
+// logfmt as written by logrus, go-kit or slog's text handler. Their well-known fields
+// are mapped onto the entry and the rest are kept as attributes.
+
+// StructuredLog holds the fields of a JSON or logfmt log line
+type StructuredLog struct {
+	Format     string            `json:"format"` // "json" or "logfmt"
+	Level      string            `json:"level,omitempty"`
+	Message    string            `json:"message,omitempty"`
+	Time       time.Time         `json:"time,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	Stack      string            `json:"stack,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"` // the remaining fields; nested keys are joined with dots
+}
+
+// Field returns a field of the line by name: msg, error, stack or an attribute
+func (s *StructuredLog) Field(name string) (string, bool) {
+	switch name {
+	case "msg", "message":
+		return s.Message, s.Message != ""
+	case "err", "error":
+		return s.Error, s.Error != ""
+	case "stack":
+		return s.Stack, s.Stack != ""
+	}
+	value, ok := s.Attributes[name]
+	return value, ok
+}
+
+// SortedAttributes returns the attribute names in order
+func (s *StructuredLog) SortedAttributes() []string {
+	return sortedAttributeKeys(s.Attributes)
+}
+
+// Field names each logger uses for the well-known fields
+var (
+	structuredLevelKeys   = []string{"level", "lvl", "severity", "levelname", "log.level"}
+	structuredMessageKeys = []string{"msg", "message"}
+	structuredTimeKeys    = []string{"time", "ts", "timestamp", "@timestamp", "t"}
+	structuredErrorKeys   = []string{"err", "error"}
+	structuredStackKeys   = []string{"stack", "stacktrace", "err.stack", "error.stack"}
+)
+
+// ParseStructured reads a JSON or logfmt log line. It returns nil for anything else.
+func ParseStructured(content string) *StructuredLog {
+	line := strings.TrimSpace(ansiRegex.ReplaceAllString(content, ""))
+	var fields map[string]string
+	format := "json"
+	if strings.HasPrefix(line, "{") && strings.HasSuffix(line, "}") {
+		fields = parseJSONFields(line)
+	} else {
+		fields = parseLogfmtFields(line)
+		format = "logfmt"
+	}
+	if fields == nil {
+		return nil
+	}
+
+	s := &StructuredLog{Format: format}
+	s.Level = takeField(fields, structuredLevelKeys)
+	s.Message = takeField(fields, structuredMessageKeys)
+	s.Time = parseStructuredTime(takeField(fields, structuredTimeKeys))
+	s.Stack = takeField(fields, structuredStackKeys)
+	s.Error = takeField(fields, structuredErrorKeys)
+
+	// pino and bunyan serialize errors as objects with type, message and stack
+	for _, key := range structuredErrorKeys {
+		if message, ok := fields[key+".message"]; ok && s.Error == "" {
+			s.Error = message
+			if errType := fields[key+".type"]; errType != "" {
+				s.Error = errType + ": " + message
+			}
+			delete(fields, key+".message")
+			delete(fields, key+".type")
+		}
+	}
+
+	// A JSON object needs one well-known field to count as a log line, and logfmt a
+	// level or a message, so that plain key=value output is left alone
+	if s.Level == "" && s.Message == "" && (format == "logfmt" || (s.Time.IsZero() && s.Error == "")) {
+		return nil
+	}
+	if len(fields) > 0 {
+		s.Attributes = fields
+	}
+	return s
+}
+
+// LogLevel maps the line's level onto a LogLevel. Numbers follow pino and bunyan:
+// 10 trace, 20 debug, 30 info, 40 warn, 50 error, 60 fatal.
+func (s *StructuredLog) LogLevel() (LogLevel, bool) {
+	var n float64
+	if _, err := fmt.Sscanf(s.Level, "%g", &n); err == nil {
+		switch {
+		case n >= 60:
+			return LevelCritical, true
+		case n >= 50:
+			return LevelError, true
+		case n >= 40:
+			return LevelWarn, true
+		case n >= 30:
+			return LevelInfo, true
+		}
+		return LevelDebug, true
+	}
+	switch strings.ToLower(s.Level) {
+	case "trace", "verbose", "silly":
+		return LevelDebug, true
+	case "notice", "information":
+		return LevelInfo, true
+	case "err", "eror":
+		return LevelError, true
+	case "panic", "dpanic", "crit", "alert", "emerg", "emergency":
+		return LevelCritical, true
+	}
+	return ParseLogLevel(s.Level)
+}
+
+// Text is the message and error of the line, for the keyword checks that give a
+// plain line its tags and priority
+func (s *StructuredLog) Text() string {
+	return strings.TrimSpace(s.Message + " " + s.Error)
+}
+
+// message is the message of a structured line, or else the whole line
+func (e LogEntry) message() string {
+	if e.Structured != nil && e.Structured.Message != "" {
+		return e.Structured.Message
+	}
+	return e.Content
+}
+
+func takeField(fields map[string]string, keys []string) string {
+	for _, key := range keys {
+		if value, ok := fields[key]; ok {
+			delete(fields, key)
+			return value
+		}
+	}
+	return ""
+}
+
+// parseJSONFields flattens a JSON object into dotted keys. Arrays are kept as JSON.
+func parseJSONFields(line string) map[string]string {
+	decoder := json.NewDecoder(strings.NewReader(line))
+	decoder.UseNumber()
+	var object map[string]interface{}
+	if err := decoder.Decode(&object); err != nil {
+		return nil
+	}
+	fields := make(map[string]string)
+	flattenJSON("", object, fields)
+	return fields
+}
+
+func flattenJSON(prefix string, object map[string]interface{}, fields map[string]string) {
+	for key, value := range object {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flattenJSON(key, v, fields)
+		case string:
+			fields[key] = v
+		case nil:
+			fields[key] = "null"
+		case []interface{}:
+			var buf bytes.Buffer
+			encoder := json.NewEncoder(&buf)
+			encoder.SetEscapeHTML(false)
+			encoder.Encode(v)
+			fields[key] = strings.TrimSpace(buf.String())
+		default:
+			fields[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+// parseLogfmtFields reads key=value pairs, with optional double quotes around
+// values. Every word must be a pair or a bare key, and there must be two pairs.
+func parseLogfmtFields(line string) map[string]string {
+	fields := make(map[string]string)
+	pairs := 0
+	for i := 0; i < len(line); {
+		if line[i] == ' ' || line[i] == '\t' {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(line) && isLogfmtKeyByte(line[i]) {
+			i++
+		}
+		if i == start {
+			return nil
+		}
+		key := line[start:i]
+		if i == len(line) || line[i] == ' ' || line[i] == '\t' {
+			fields[key] = "true" // a bare key is a flag
+			continue
+		}
+		if line[i] != '=' {
+			return nil
+		}
+		i++
+
+		var value strings.Builder
+		if i < len(line) && line[i] == '"' {
+			i++
+			closed := false
+			for i < len(line) {
+				if line[i] == '\\' && i+1 < len(line) {
+					switch line[i+1] {
+					case 'n':
+						value.WriteByte('\n')
+					case 't':
+						value.WriteByte('\t')
+					default:
+						value.WriteByte(line[i+1])
+					}
+					i += 2
+					continue
+				}
+				if line[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				value.WriteByte(line[i])
+				i++
+			}
+			if !closed {
+				return nil
+			}
+		} else {
+			for i < len(line) && line[i] != ' ' && line[i] != '\t' {
+				value.WriteByte(line[i])
+				i++
+			}
+		}
+		fields[key] = value.String()
+		pairs++
+	}
+	if pairs < 2 {
+		return nil
+	}
+	return fields
+}
+
+func isLogfmtKeyByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '_' || c == '.' || c == '-' || c == '/' || c == '@'
+}
+
+// parseStructuredTime reads an RFC 3339 time, or a Unix time in seconds (zap) or
+// milliseconds (pino)
+func parseStructuredTime(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t
+	}
+	var n float64
+	if _, err := fmt.Sscanf(value, "%g", &n); err != nil {
+		return time.Time{}
+	}
+	switch {
+	case n > 1e12:
+		return time.UnixMilli(int64(n))
+	case n > 1e9:
+		sec, frac := math.Modf(n)
+		return time.Unix(int64(sec), int64(frac*1e9))
+	}
+	return time.Time{}
+}
+
+func sortedAttributeKeys(attributes map[string]string) []string {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,89 @@
+package logs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStructured(t *testing.T) {
+	// pino, with an error serialized as an object
+	s := ParseStructured(`{"level":50,"time":1710417600000,"pid":42,"hostname":"box","msg":"db down","err":{"type":"Error","message":"connect ECONNREFUSED","stack":"Error: connect ECONNREFUSED\n    at TCPConnectWrap"},"req":{"method":"GET","url":"/users"}}`)
+	require.NotNil(t, s)
+	assert.Equal(t, "json", s.Format)
+	assert.Equal(t, "db down", s.Message)
+	assert.Equal(t, "Error: connect ECONNREFUSED", s.Error)
+	assert.Equal(t, "Error: connect ECONNREFUSED\n    at TCPConnectWrap", s.Stack)
+	assert.True(t, s.Time.Equal(time.Date(2024, 3, 14, 12, 0, 0, 0, time.UTC)))
+	assert.Equal(t, map[string]string{"pid": "42", "hostname": "box", "req.method": "GET", "req.url": "/users"}, s.Attributes)
+	level, ok := s.LogLevel()
+	require.True(t, ok)
+	assert.Equal(t, LevelError, level)
+
+	// zap production encoder
+	s = ParseStructured(`{"level":"warn","ts":1710417600.5,"caller":"api/main.go:42","msg":"slow query","duration":0.75,"stacktrace":"main.run\n\tapi/main.go:42"}`)
+	require.NotNil(t, s)
+	assert.Equal(t, "slow query", s.Message)
+	assert.Equal(t, int64(1710417600500), s.Time.UnixMilli())
+	assert.Equal(t, "main.run\n\tapi/main.go:42", s.Stack)
+	assert.Equal(t, "0.75", s.Attributes["duration"])
+	level, _ = s.LogLevel()
+	assert.Equal(t, LevelWarn, level)
+
+	// logrus text formatter
+	s = ParseStructured(`time="2024-03-14T12:00:00Z" level=fatal msg="cannot bind \"0.0.0.0:80\"" port=80 retry`)
+	require.NotNil(t, s)
+	assert.Equal(t, "logfmt", s.Format)
+	assert.Equal(t, `cannot bind "0.0.0.0:80"`, s.Message)
+	assert.Equal(t, map[string]string{"port": "80", "retry": "true"}, s.Attributes)
+	level, _ = s.LogLevel()
+	assert.Equal(t, LevelCritical, level)
+
+	// Plain output, and key=value or JSON without any log fields, stay plain
+	for _, line := range []string{
+		"Server listening on http://localhost:3000",
+		"GET /api/users 200 12ms",
+		"a=1 b=2",
+		`{"name":"my-app","version":"1.0.0"}`,
+		`{"level":`,
+	} {
+		assert.Nil(t, ParseStructured(line), line)
+	}
+}
+
+func TestStoreStructuredLines(t *testing.T) {
+	store := NewStore(100, nil)
+	defer store.Close()
+
+	// The level field wins over keywords in the line
+	entry := store.addSync("api", "api", `{"level":30,"msg":"retrying after error budget check","status":504,"user":"jane doe"}`, false)
+	require.NotNil(t, entry.Structured)
+	assert.Equal(t, LevelInfo, entry.Level)
+	entry = store.addSync("api", "api", `level=error msg="request failed" status=500 duration_ms=250`, false)
+	assert.Equal(t, LevelError, entry.Level)
+	assert.Len(t, store.GetErrors(), 1)
+	store.addSync("web", "web", "status:504 from upstream", false)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{`status:504`, []string{"api", "web"}},
+		{`status>=500 process:api`, []string{"api", "api"}},
+		{`duration_ms>=200`, []string{"api"}},
+		{`user:"jane doe"`, []string{"api"}},
+		{`msg:failed`, []string{"api"}},
+		{`level:error`, []string{"api"}},
+	}
+	for _, tt := range tests {
+		results, err := store.SearchPage(tt.query, time.Time{}, 0)
+		require.NoError(t, err, tt.query)
+		var processes []string
+		for _, result := range results {
+			processes = append(processes, result.ProcessName)
+		}
+		assert.Equal(t, tt.want, processes, tt.query)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	mcplib "github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/standardbeagle/brummer/internal/logs"
 )
 
 // RegisterHubTools registers all hub proxy tools that include an instance_id parameter
@@ -483,8 +484,8 @@ func registerHubLogTools(srv *server.MCPServer, connMgr *ConnectionManager) {
 
 **Instance targeting:**
 - **instance_id**: Required - which brummer instance to search
-- **query**: Required - search pattern or keywords
-- **regex**: Optional - use regular expressions
+- **query**: Required - a query such as process:api level>=warn "timeout" since:10m -healthcheck
+- **regex**: Optional - treat the whole query as a regular expression
 - **level**: Optional - filter by log level
 - **processId**: Optional - search within specific process
 - **since**: Optional - time-bounded search
@@ -495,7 +496,7 @@ func registerHubLogTools(srv *server.MCPServer, connMgr *ConnectionManager) {
    → hub_logs_search with {"instance_id": "backend-def456", "query": "connection", "level": "error"}
 
 2. User: "Search for API timeouts across the last hour"
-   → hub_logs_search with {"instance_id": "api-ghi789", "query": "timeout", "since": "2024-01-15T10:00:00Z"}
+   → hub_logs_search with {"instance_id": "api-ghi789", "query": "timeout since:1h"}
 
 3. User: "Find all database errors in the data service"
    → hub_logs_search with {"instance_id": "data-jkl012", "query": "database.*error", "regex": true}
@@ -516,10 +517,10 @@ func registerHubLogTools(srv *server.MCPServer, connMgr *ConnectionManager) {
 		),
 		mcplib.WithString("query",
 			mcplib.Required(),
-			mcplib.Description("Search query (text or regex)"),
+			mcplib.Description("Search query: field terms (process, level, tag, priority, message, since, until, or any field of a JSON or logfmt line such as status:504), \"phrases\", /regex/, AND, OR, NOT, - and parentheses"),
 		),
 		mcplib.WithBoolean("regex",
-			mcplib.Description("Whether query is a regex pattern"),
+			mcplib.Description("Treat the whole query as one regex pattern instead of a query"),
 		),
 		mcplib.WithString("level",
 			mcplib.Description("Filter by log level (all, error, warn, info)"),
@@ -530,8 +531,11 @@ func registerHubLogTools(srv *server.MCPServer, connMgr *ConnectionManager) {
 		mcplib.WithString("since",
 			mcplib.Description("Search logs since this time (RFC3339 format)"),
 		),
+		mcplib.WithString("before",
+			mcplib.Description("Search logs before this time (RFC3339 format); pass the timestamp of the oldest result to get the previous page"),
+		),
 		mcplib.WithNumber("limit",
-			mcplib.Description("Maximum results to return (default: 100)"),
+			mcplib.Description("Maximum results to return, newest first (default: 100)"),
 		),
 		mcplib.WithString("output_file",
 			mcplib.Description("Optional file path to write search results (e.g., 'search-results.json', 'debug/logs-search.json')"),
@@ -548,11 +552,19 @@ func registerHubLogTools(srv *server.MCPServer, connMgr *ConnectionManager) {
 			return mcplib.NewToolResultError(err.Error()), nil
 		}
 
+		// Report syntax errors here rather than after a round trip to the instance
+		regex := request.GetBool("regex", false)
+		if !regex {
+			if _, err := logs.ParseQuery(query); err != nil {
+				return mcplib.NewToolResultError(fmt.Sprintf("Invalid query: %v", err)), nil
+			}
+		}
+
 		args := map[string]interface{}{
 			"query": query,
 		}
 
-		if regex := request.GetBool("regex", false); regex {
+		if regex {
 			args["regex"] = regex
 		}
 		if level := request.GetString("level", ""); level != "" {
@@ -564,6 +576,9 @@ func registerHubLogTools(srv *server.MCPServer, connMgr *ConnectionManager) {
 		if since := request.GetString("since", ""); since != "" {
 			args["since"] = since
 		}
+		if before := request.GetString("before", ""); before != "" {
+			args["before"] = before
+		}
 		if limit := request.GetInt("limit", 0); limit > 0 {
 			args["limit"] = limit
 		}
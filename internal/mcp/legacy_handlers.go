@@ -163,8 +163,26 @@ func (s *MCPServer) logStoreGetByProcessInterface(processID string) []interface{
 	return result
 }
 
+// logStoreRecentInterface returns the newest limit entries, of one process when
+// processID is set, reaching into the history on disk
+func (s *MCPServer) logStoreRecentInterface(processID string, limit int) []interface{} {
+	if limit <= 0 {
+		return []interface{}{}
+	}
+	page := logs.PageQuery{Limit: limit}
+	if processID != "" {
+		page.Match = func(entry logs.LogEntry) bool { return entry.ProcessID == processID }
+	}
+	entries := s.logStore.Page(page)
+	result := make([]interface{}, len(entries))
+	for i, entry := range entries {
+		result[i] = s.logEntryToInterface(entry)
+	}
+	return result
+}
+
 func (s *MCPServer) logEntryToInterface(entry logs.LogEntry) map[string]interface{} {
-	return map[string]interface{}{
+	result := map[string]interface{}{
 		"id":          entry.ID,
 		"processId":   entry.ProcessID,
 		"processName": entry.ProcessName,
@@ -175,4 +193,8 @@ func (s *MCPServer) logEntryToInterface(entry logs.LogEntry) map[string]interfac
 		"level":       entry.Level,
 		"tags":        entry.Tags,
 	}
+	if entry.Structured != nil {
+		result["structured"] = entry.Structured
+	}
+	return result
 }
@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/standardbeagle/brummer/internal/aicoder"
@@ -35,7 +36,7 @@ func (s *MCPServer) registerResources() {
 	s.resources["telemetry://errors"] = Resource{
 		URI:         "telemetry://errors",
 		Name:        "Browser Errors",
-		Description: "JavaScript errors from browser sessions",
+		Description: "JavaScript errors from browser sessions, with stacks mapped to their sources when source maps are found",
 		MimeType:    "application/json",
 	}
 
@@ -338,13 +339,15 @@ func (s *MCPServer) getBrowserErrors() []interface{} {
 	for _, session := range s.proxyServer.GetTelemetryStore().GetAllSessions() {
 		for _, event := range session.Events {
 			if event.Type == "javascript_error" || event.Type == "unhandled_rejection" {
-				errors = append(errors, map[string]interface{}{
+				browserError := map[string]interface{}{
 					"sessionId": session.SessionID,
 					"url":       session.URL,
 					"timestamp": event.Timestamp,
 					"type":      event.Type,
 					"data":      event.Data,
-				})
+				}
+				s.addSourceMappedLocation(browserError, event.Data)
+				errors = append(errors, browserError)
 			}
 		}
 	}
@@ -352,6 +355,32 @@ func (s *MCPServer) getBrowserErrors() []interface{} {
 	return errors
 }
 
+// addSourceMappedLocation adds the original stack and location of a browser error
+// when source maps cover them and the log store has resolved them in the background.
+// The data keeps the minified ones.
+func (s *MCPServer) addSourceMappedLocation(browserError map[string]interface{}, data map[string]interface{}) {
+	if s.logStore == nil {
+		return
+	}
+	if stack, ok := data["stack"].(string); ok && stack != "" {
+		if resolved, ok := s.logStore.ResolvedStack(strings.Split(stack, "\n")); ok {
+			browserError["stack"] = strings.Join(resolved, "\n")
+			browserError["generatedStack"] = stack
+		}
+	}
+	filename, _ := data["filename"].(string)
+	line, _ := data["lineno"].(float64)
+	column, _ := data["colno"].(float64)
+	if filename != "" && line > 0 {
+		column = max(column, 1)
+		generated := fmt.Sprintf("%s:%d:%d", filename, int(line), int(column))
+		if resolved, ok := s.logStore.ResolvedStack([]string{generated}); ok {
+			browserError["location"] = resolved[0]
+			browserError["generatedLocation"] = generated
+		}
+	}
+}
+
 func (s *MCPServer) getConsoleErrors() []interface{} {
 	if s.proxyServer == nil || s.proxyServer.GetTelemetryStore() == nil {
 		return []interface{}{}
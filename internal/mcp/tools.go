@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -11,6 +12,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/standardbeagle/brummer/internal/logs"
+	"github.com/standardbeagle/brummer/internal/process"
 	"github.com/standardbeagle/brummer/internal/proxy"
 	"github.com/standardbeagle/brummer/internal/repl"
 	"github.com/standardbeagle/brummer/pkg/events"
@@ -166,9 +169,9 @@ func (s *MCPServer) registerScriptTools() {
 	// scripts_list - List all available scripts
 	s.tools["scripts_list"] = MCPTool{
 		Name: "scripts_list",
-		Description: `List all available npm/yarn/pnpm/bun scripts from package.json.
+		Description: `List all available npm/yarn/pnpm/bun scripts from package.json, plus services declared in .brum.toml or a Procfile, and the Makefile targets, justfile recipes, Taskfile tasks and pyproject.toml scripts of the project.
 
-Use this to see what scripts are available before running them with scripts_run.
+Use this to see what scripts are available before running them with scripts_run, which takes the names of commands too. Services are grouped; run a whole group with name "@<group>".
 
 For detailed documentation and examples, use: about tool="scripts_list"`,
 		InputSchema: json.RawMessage(`{
@@ -178,7 +181,10 @@ For detailed documentation and examples, use: about tool="scripts_list"`,
 		Handler: func(args json.RawMessage) (interface{}, error) {
 			scripts := s.processMgr.GetScripts()
 			return map[string]interface{}{
-				"scripts": scripts,
+				"scripts":  scripts,
+				"services": s.processMgr.Services(),
+				"groups":   s.processMgr.Groups(),
+				"commands": s.taskRunnerCommands(),
 			}, nil
 		},
 	}
@@ -186,9 +192,9 @@ For detailed documentation and examples, use: about tool="scripts_list"`,
 	// scripts_run - Start a script
 	s.tools["scripts_run"] = MCPTool{
 		Name: "scripts_run",
-		Description: `Start a package.json script with full process management, log capture, and URL detection.
+		Description: `Start a package.json script or declared service with full process management, log capture, and URL detection.
 
-Automatically captures output, detects URLs for proxy setup, and handles duplicate prevention.
+Automatically captures output, detects URLs for proxy setup, and handles duplicate prevention. A name of "@<group>" starts every service in that group. With allPackages or filter the script runs in each matching workspace package and the result is a pass/fail summary. A name of "<package>#<task>" runs a Turborepo or Nx task after its upstream tasks.
 
 For detailed documentation and examples, use: about tool="scripts_run"`,
 		InputSchema: json.RawMessage(`{
@@ -196,7 +202,20 @@ For detailed documentation and examples, use: about tool="scripts_run"`,
 			"properties": {
 				"name": {
 					"type": "string",
-					"description": "The name of the script to run"
+					"description": "The name of the script or service to run, @<group> to start a group, or <package>#<task> to run a Turborepo or Nx task with its dependencies"
+				},
+				"allPackages": {
+					"type": "boolean",
+					"description": "Run the script in every workspace package that defines it"
+				},
+				"filter": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": "Run the script in the workspace packages matching these globs, e.g. pkg-* or packages/api"
+				},
+				"concurrency": {
+					"type": "integer",
+					"description": "How many packages or tasks run at once (default: 4)"
 				}
 			},
 			"required": ["name"]
@@ -204,11 +223,23 @@ For detailed documentation and examples, use: about tool="scripts_run"`,
 		Streaming: true,
 		StreamingHandler: func(args json.RawMessage, send func(interface{})) (interface{}, error) {
 			var params struct {
-				Name string `json:"name"`
+				Name        string   `json:"name"`
+				AllPackages bool     `json:"allPackages"`
+				Filter      []string `json:"filter"`
+				Concurrency int      `json:"concurrency"`
 			}
 			if err := json.Unmarshal(args, &params); err != nil {
 				return nil, err
 			}
+			if group, ok := strings.CutPrefix(params.Name, "@"); ok {
+				return s.startGroup(group)
+			}
+			if params.AllPackages || len(params.Filter) > 0 {
+				return s.runMatrix(params.Name, process.MatrixOptions{Filters: params.Filter, Concurrency: params.Concurrency})
+			}
+			if _, isScript := s.processMgr.GetScripts()[params.Name]; !isScript && process.IsTaskTarget(params.Name) {
+				return s.runTask(params.Name, params.Concurrency)
+			}
 
 			// Check if script is already running
 			for _, proc := range s.processMgr.GetAllProcesses() {
@@ -239,7 +270,7 @@ For detailed documentation and examples, use: about tool="scripts_run"`,
 			}
 
 			// Start the script
-			process, err := s.processMgr.StartScript(params.Name)
+			process, err := s.processMgr.StartScriptOrTask(params.Name)
 			if err != nil {
 				return nil, err
 			}
@@ -293,11 +324,23 @@ For detailed documentation and examples, use: about tool="scripts_run"`,
 		},
 		Handler: func(args json.RawMessage) (interface{}, error) {
 			var params struct {
-				Name string `json:"name"`
+				Name        string   `json:"name"`
+				AllPackages bool     `json:"allPackages"`
+				Filter      []string `json:"filter"`
+				Concurrency int      `json:"concurrency"`
 			}
 			if err := json.Unmarshal(args, &params); err != nil {
 				return nil, err
 			}
+			if group, ok := strings.CutPrefix(params.Name, "@"); ok {
+				return s.startGroup(group)
+			}
+			if params.AllPackages || len(params.Filter) > 0 {
+				return s.runMatrix(params.Name, process.MatrixOptions{Filters: params.Filter, Concurrency: params.Concurrency})
+			}
+			if _, isScript := s.processMgr.GetScripts()[params.Name]; !isScript && process.IsTaskTarget(params.Name) {
+				return s.runTask(params.Name, params.Concurrency)
+			}
 
 			// Check if script is already running
 			for _, proc := range s.processMgr.GetAllProcesses() {
@@ -343,7 +386,7 @@ For detailed documentation and examples, use: about tool="scripts_run"`,
 			}
 
 			// Script not running, start it
-			process, err := s.processMgr.StartScript(params.Name)
+			process, err := s.processMgr.StartScriptOrTask(params.Name)
 			if err != nil {
 				return nil, err
 			}
@@ -365,7 +408,7 @@ For detailed documentation and examples, use: about tool="scripts_run"`,
 		Name: "scripts_stop",
 		Description: `Stop a running script process gracefully with proper cleanup.
 
-Requires process ID from scripts_status. Use for stopping development servers or freeing up resources.
+Requires process ID from scripts_status, or a service group to stop every running service in it. Use for stopping development servers or freeing up resources.
 
 For detailed documentation and examples, use: about tool="scripts_stop"`,
 		InputSchema: json.RawMessage(`{
@@ -374,29 +417,408 @@ For detailed documentation and examples, use: about tool="scripts_stop"`,
 				"processId": {
 					"type": "string",
 					"description": "The process ID to stop"
+				},
+				"group": {
+					"type": "string",
+					"description": "Stop every service in this group instead of a single process"
 				}
-			},
-			"required": ["processId"]
+			}
 		}`),
 		Handler: func(args json.RawMessage) (interface{}, error) {
 			var params struct {
 				ProcessID string `json:"processId"`
+				Group     string `json:"group"`
 			}
 			if err := json.Unmarshal(args, &params); err != nil {
 				return nil, err
 			}
 
-			if err := s.processMgr.StopProcess(params.ProcessID); err != nil {
+			if params.Group != "" {
+				stopped, err := s.processMgr.StopGroup(strings.TrimPrefix(params.Group, "@"))
+				if err != nil && len(stopped) == 0 {
+					return nil, err
+				}
+				result := map[string]interface{}{
+					"success": err == nil,
+					"group":   params.Group,
+					"stopped": stopped,
+				}
+				if err != nil {
+					result["error"] = err.Error()
+				}
+				return result, nil
+			}
+			if params.ProcessID == "" {
+				return nil, fmt.Errorf("processId or group is required")
+			}
+
+			// Dependents declared with depends_on are torn down first
+			stopped, err := s.processMgr.StopProcessWithDependents(params.ProcessID)
+			if err != nil {
 				return nil, err
 			}
 
 			return map[string]interface{}{
 				"success":   true,
 				"processId": params.ProcessID,
+				"stopped":   stopped,
+			}, nil
+		},
+	}
+
+	// scripts_send_input - Write to a running script's stdin
+	s.tools["scripts_send_input"] = MCPTool{
+		Name: "scripts_send_input",
+		Description: `Send input to a running script's stdin, for example to answer an interactive prompt.
+
+Only scripts with stdin = true or pty = true accept input. Scripts waiting on a prompt report it as awaitingInput in scripts_status. A newline is appended unless newline is false; set eof to close stdin after writing.
+
+For detailed documentation and examples, use: about tool="scripts_send_input"`,
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"processId": {
+					"type": "string",
+					"description": "The process ID to write to"
+				},
+				"name": {
+					"type": "string",
+					"description": "Script name; used when processId is not given"
+				},
+				"input": {
+					"type": "string",
+					"description": "Text to send"
+				},
+				"newline": {
+					"type": "boolean",
+					"description": "Append a newline to the input (default true)"
+				},
+				"eof": {
+					"type": "boolean",
+					"description": "Close stdin after sending the input"
+				}
+			}
+		}`),
+		Handler: func(args json.RawMessage) (interface{}, error) {
+			var params struct {
+				ProcessID string `json:"processId"`
+				Name      string `json:"name"`
+				Input     string `json:"input"`
+				Newline   *bool  `json:"newline"`
+				EOF       bool   `json:"eof"`
+			}
+			if err := json.Unmarshal(args, &params); err != nil {
+				return nil, err
+			}
+
+			var proc *process.Process
+			if params.ProcessID != "" {
+				p, exists := s.processMgr.GetProcess(params.ProcessID)
+				if !exists {
+					return nil, fmt.Errorf("process not found: %s", params.ProcessID)
+				}
+				proc = p
+			} else if params.Name != "" {
+				for _, p := range s.processMgr.GetAllProcesses() {
+					if p.Name == params.Name && p.GetStatus() == process.StatusRunning {
+						proc = p
+						break
+					}
+				}
+				if proc == nil {
+					return nil, fmt.Errorf("no running process named %s", params.Name)
+				}
+			} else {
+				return nil, fmt.Errorf("processId or name is required")
+			}
+
+			data := params.Input
+			if params.Newline == nil || *params.Newline {
+				data += "\n"
+			}
+			if data != "" {
+				if err := proc.WriteInput([]byte(data)); err != nil {
+					return nil, err
+				}
+			}
+			if params.EOF {
+				if err := proc.CloseInput(); err != nil {
+					return nil, err
+				}
+			}
+
+			return map[string]interface{}{
+				"success":   true,
+				"processId": proc.ID,
+				"bytes":     len(data),
+				"eof":       params.EOF,
 			}, nil
 		},
 	}
 
+	// scripts_metrics - Resource usage of running scripts
+	s.tools["scripts_metrics"] = MCPTool{
+		Name: "scripts_metrics",
+		Description: `Show CPU, memory, thread and file-descriptor usage of running scripts, including all child processes.
+
+Samples are taken every few seconds and kept for ten minutes. The summary reports growth of memory and open file descriptors over that window, which helps spot a leaking dev server.
+
+For detailed documentation and examples, use: about tool="scripts_metrics"`,
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"processId": {
+					"type": "string",
+					"description": "Optional process ID to inspect"
+				},
+				"name": {
+					"type": "string",
+					"description": "Optional script name to inspect"
+				},
+				"samples": {
+					"type": "integer",
+					"description": "Number of recent samples to include (default 0, summary only)"
+				}
+			}
+		}`),
+		Handler: func(args json.RawMessage) (interface{}, error) {
+			var params struct {
+				ProcessID string `json:"processId"`
+				Name      string `json:"name"`
+				Samples   int    `json:"samples"`
+			}
+			json.Unmarshal(args, &params)
+
+			result := make([]map[string]interface{}, 0)
+			for _, p := range s.processMgr.GetAllProcesses() {
+				if params.ProcessID != "" && p.ID != params.ProcessID {
+					continue
+				}
+				if params.Name != "" && p.Name != params.Name {
+					continue
+				}
+				if params.ProcessID == "" && p.GetStatus() != process.StatusRunning {
+					continue
+				}
+
+				samples := p.Metrics()
+				info := map[string]interface{}{
+					"processId": p.ID,
+					"name":      p.Name,
+					"status":    string(p.GetStatus()),
+				}
+				if len(samples) > 0 {
+					info["latest"] = samples[len(samples)-1]
+					info["summary"] = summarizeMetrics(samples)
+				}
+				if params.Samples > 0 {
+					if len(samples) > params.Samples {
+						samples = samples[len(samples)-params.Samples:]
+					}
+					info["samples"] = samples
+				}
+				result = append(result, info)
+			}
+
+			if params.ProcessID != "" && len(result) == 0 {
+				return nil, fmt.Errorf("process not found: %s", params.ProcessID)
+			}
+			return result, nil
+		},
+	}
+
+	// scripts_terminations - Report processes brummer signalled or killed
+	s.tools["scripts_terminations"] = MCPTool{
+		Name: "scripts_terminations",
+		Description: `List the processes brummer has signalled or killed, with the signal and the reason.
+
+Covers graceful stops (the script's stop_signal), escalations to SIGKILL after stop_timeout_seconds, and the dev-port and command-pattern cleanup that can hit processes brummer did not start. Set kill_dev_processes = false in .brum.toml to turn that cleanup off.
+
+For detailed documentation and examples, use: about tool="scripts_terminations"`,
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"limit": {
+					"type": "integer",
+					"description": "Number of most recent terminations to return (default all, up to 200)"
+				}
+			}
+		}`),
+		Handler: func(args json.RawMessage) (interface{}, error) {
+			var params struct {
+				Limit int `json:"limit"`
+			}
+			json.Unmarshal(args, &params)
+
+			terminations := s.processMgr.Terminations()
+			if params.Limit > 0 && len(terminations) > params.Limit {
+				terminations = terminations[len(terminations)-params.Limit:]
+			}
+			return map[string]interface{}{
+				"terminations":     terminations,
+				"killDevProcesses": s.processMgr.KillsDevProcesses(),
+			}, nil
+		},
+	}
+
+	// scripts_schedules - Report recurring tasks
+	s.tools["scripts_schedules"] = MCPTool{
+		Name: "scripts_schedules",
+		Description: `List the recurring tasks declared under [schedules.<name>] in .brum.toml with their next and last run times.
+
+Each schedule runs its command on a cron expression or a fixed interval. A run is skipped, and counted, when the previous one is still going. Use lastProcessId with logs_search to read the output of the last run.
+
+For detailed documentation and examples, use: about tool="scripts_schedules"`,
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"name": {
+					"type": "string",
+					"description": "Only return this schedule"
+				}
+			}
+		}`),
+		Handler: func(args json.RawMessage) (interface{}, error) {
+			var params struct {
+				Name string `json:"name"`
+			}
+			json.Unmarshal(args, &params)
+
+			schedules := s.processMgr.Schedules()
+			if params.Name != "" {
+				for _, sched := range schedules {
+					if sched.Name == params.Name {
+						return sched, nil
+					}
+				}
+				return nil, fmt.Errorf("schedule '%s' not found", params.Name)
+			}
+			return map[string]interface{}{
+				"schedules": schedules,
+			}, nil
+		},
+	}
+
+	// ports_inspect - Report which processes listen on which ports
+	s.tools["ports_inspect"] = MCPTool{
+		Name: "ports_inspect",
+		Description: `Show which processes listen on TCP ports, with their PID, command line and the brummer instance that started them.
+
+Use it when a script fails with EADDRINUSE or "address already in use" to find out who holds the port. Owners started by a brummer report processName and instance; instance.self is true for this brummer. Resolve the conflict with ports_resolve. Reads /proc, so it is only available on Linux.
+
+For detailed documentation and examples, use: about tool="ports_inspect"`,
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"port": {
+					"type": "integer",
+					"description": "Only return the owners of this port (default every listening port)"
+				}
+			}
+		}`),
+		Handler: func(args json.RawMessage) (interface{}, error) {
+			var params struct {
+				Port int `json:"port"`
+			}
+			json.Unmarshal(args, &params)
+
+			if params.Port == 0 {
+				owners, err := s.processMgr.ListeningPorts()
+				if err != nil {
+					return nil, err
+				}
+				return map[string]interface{}{"listeners": owners}, nil
+			}
+			owners, err := s.processMgr.InspectPort(params.Port)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{
+				"port":   params.Port,
+				"inUse":  len(owners) > 0,
+				"owners": owners,
+			}, nil
+		},
+	}
+
+	// ports_resolve - Free a port or move a script off it
+	s.tools["ports_resolve"] = MCPTool{
+		Name: "ports_resolve",
+		Description: `Resolve a port conflict by killing the port's owner or by moving a script to another port.
+
+action "kill_owner" stops whatever listens on port: processes started by this brummer are stopped normally. Anything else has its process tree killed only when force is true; without it the call fails and lists those processes, so confirm with the user first. PID 1, brummer's own parents and other users' processes are never killed. action "use_another_port" restarts the named script or service with a free port from the [ports] range in its PORT variable (or its port_env), which only helps when the process reads its port from the environment.
+
+For detailed documentation and examples, use: about tool="ports_resolve"`,
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"action": {
+					"type": "string",
+					"enum": ["kill_owner", "use_another_port"],
+					"description": "How to resolve the conflict"
+				},
+				"port": {
+					"type": "integer",
+					"description": "Port whose owner to kill (kill_owner)"
+				},
+				"name": {
+					"type": "string",
+					"description": "Script or service to move (use_another_port)"
+				},
+				"force": {
+					"type": "boolean",
+					"description": "Also kill processes brummer did not start (kill_owner)",
+					"default": false
+				}
+			},
+			"required": ["action"]
+		}`),
+		Handler: func(args json.RawMessage) (interface{}, error) {
+			var params struct {
+				Action string `json:"action"`
+				Port   int    `json:"port"`
+				Name   string `json:"name"`
+				Force  bool   `json:"force"`
+			}
+			if err := json.Unmarshal(args, &params); err != nil {
+				return nil, err
+			}
+
+			switch params.Action {
+			case "kill_owner":
+				if params.Port == 0 {
+					return nil, fmt.Errorf("port is required for kill_owner")
+				}
+				killed, err := s.processMgr.KillPortOwner(params.Port, params.Force)
+				var unmanaged *process.UnmanagedPortOwnerError
+				if errors.As(err, &unmanaged) {
+					return nil, fmt.Errorf("%w; call again with force true once the user agrees", err)
+				}
+				if err != nil {
+					return nil, err
+				}
+				return map[string]interface{}{
+					"port":   params.Port,
+					"killed": killed,
+				}, nil
+			case "use_another_port":
+				if params.Name == "" {
+					return nil, fmt.Errorf("name is required for use_another_port")
+				}
+				port, err := s.processMgr.UseAnotherPort(params.Name)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]interface{}{
+					"name": params.Name,
+					"port": port,
+				}, nil
+			default:
+				return nil, fmt.Errorf("unknown action '%s': use kill_owner or use_another_port", params.Action)
+			}
+		},
+	}
+
 	// scripts_status - Check script status
 	s.tools["scripts_status"] = MCPTool{
 		Name: "scripts_status",
@@ -438,6 +860,8 @@ For detailed documentation and examples, use: about tool="scripts_status"`,
 							"processId": state.ID,
 							"name":      state.Name,
 							"status":    string(state.Status),
+							"ready":     p.IsReady(),
+							"health":    string(p.GetHealth()),
 							"startTime": state.StartTime,
 							"uptime":    state.Duration().String(),
 						}
@@ -459,6 +883,19 @@ For detailed documentation and examples, use: about tool="scripts_status"`,
 							}
 						}
 
+						if restart := s.restartInfo(state.Name); restart != nil {
+							result["restart"] = restart
+						}
+						if port := p.AssignedPort(); port > 0 {
+							result["port"] = port
+						}
+						if prompt := p.AwaitingInput(); prompt != "" {
+							result["awaitingInput"] = prompt
+						}
+						if reason := p.ExitReason(); reason != process.ExitReasonNone {
+							result["exitReason"] = string(reason)
+						}
+
 						// Add commands for managing the process
 						if state.IsRunning() {
 							result["commands"] = map[string]string{
@@ -485,6 +922,8 @@ For detailed documentation and examples, use: about tool="scripts_status"`,
 					"processId": state.ID,
 					"name":      state.Name,
 					"status":    string(state.Status),
+					"ready":     p.IsReady(),
+					"health":    string(p.GetHealth()),
 					"startTime": state.StartTime,
 					"uptime":    state.Duration().String(),
 				}
@@ -506,12 +945,201 @@ For detailed documentation and examples, use: about tool="scripts_status"`,
 					}
 				}
 
+				if restart := s.restartInfo(state.Name); restart != nil {
+					procInfo["restart"] = restart
+				}
+				if port := p.AssignedPort(); port > 0 {
+					procInfo["port"] = port
+				}
+				if prompt := p.AwaitingInput(); prompt != "" {
+					procInfo["awaitingInput"] = prompt
+				}
+				if reason := p.ExitReason(); reason != process.ExitReasonNone {
+					procInfo["exitReason"] = string(reason)
+				}
+
 				result = append(result, procInfo)
 			}
 
 			return result, nil
 		},
 	}
+
+	// env_list - Show the resolved environment for a script
+	s.tools["env_list"] = MCPTool{
+		Name: "env_list",
+		Description: `List the environment variables a script receives, with the file each value came from.
+
+Values are layered from the system environment, .env, .env.<profile>, .env.local and [scripts.<name>.env] in .brum.toml. Secret values are always masked.
+
+For detailed documentation and examples, use: about tool="env_list"`,
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"name": {
+					"type": "string",
+					"description": "Optional script name whose overrides should be applied"
+				},
+				"includeSystem": {
+					"type": "boolean",
+					"description": "Include variables inherited from the system environment (default: false)"
+				}
+			}
+		}`),
+		Handler: func(args json.RawMessage) (interface{}, error) {
+			var params struct {
+				Name          string `json:"name"`
+				IncludeSystem bool   `json:"includeSystem"`
+			}
+			if err := json.Unmarshal(args, &params); err != nil {
+				return nil, err
+			}
+
+			resolved, err := s.processMgr.ResolveEnvironment(params.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			return map[string]interface{}{
+				"profile":   s.processMgr.GetEnvProfile(),
+				"files":     resolved.Files(),
+				"variables": resolved.Masked(params.IncludeSystem),
+			}, nil
+		},
+	}
+}
+
+// restartInfo describes a script's restart policy state, or nil when no policy is active
+func (s *MCPServer) restartInfo(name string) map[string]interface{} {
+	restart, ok := s.processMgr.GetRestartState(name)
+	if !ok {
+		return nil
+	}
+
+	info := map[string]interface{}{
+		"policy":     string(restart.Policy),
+		"restarts":   restart.Restarts,
+		"maxRetries": restart.MaxRetries,
+		"crashLoop":  restart.CrashLoop,
+	}
+	if restart.NextRestart != nil {
+		info["nextRestart"] = restart.NextRestart.Format(time.RFC3339)
+	}
+	return info
+}
+
+// startGroup starts the services in a group for scripts_run and reports what started
+func (s *MCPServer) startGroup(group string) (interface{}, error) {
+	started, err := s.processMgr.StartGroup(group)
+	if err != nil && len(started) == 0 {
+		return nil, err
+	}
+
+	processes := make([]map[string]interface{}, 0, len(started))
+	for _, proc := range started {
+		state := proc.GetStateAtomic()
+		processes = append(processes, map[string]interface{}{
+			"processId": state.ID,
+			"name":      state.Name,
+			"script":    state.Script,
+			"status":    string(state.Status),
+		})
+	}
+	result := map[string]interface{}{
+		"group":   group,
+		"started": processes,
+	}
+	if err != nil {
+		result["error"] = err.Error()
+	}
+	return result, nil
+}
+
+// runMatrix runs a script across workspace packages and waits for the summary
+func (s *MCPServer) runMatrix(script string, opts process.MatrixOptions) (interface{}, error) {
+	run, err := s.processMgr.RunMatrix(script, opts)
+	if err != nil {
+		return nil, err
+	}
+	<-run.Done()
+
+	summary := run.Summary()
+	return map[string]interface{}{
+		"script":   summary.Target,
+		"total":    summary.Total,
+		"passed":   summary.Passed,
+		"failed":   summary.Failed,
+		"duration": summary.Duration.String(),
+		"packages": summary.Results,
+		"message":  summary.String(),
+	}, nil
+}
+
+// taskRunnerCommands lists the Makefile targets, justfile recipes, Taskfile tasks and
+// pyproject.toml scripts
+func (s *MCPServer) taskRunnerCommands() []map[string]interface{} {
+	commands := make([]map[string]interface{}, 0)
+	for _, cmd := range s.processMgr.ProjectTasks() {
+		commands = append(commands, map[string]interface{}{
+			"name":        cmd.Name,
+			"command":     strings.Join(append([]string{cmd.Command}, cmd.Args...), " "),
+			"description": cmd.Description,
+			"category":    cmd.Category,
+		})
+	}
+	return commands
+}
+
+// runTask runs a Turborepo or Nx task after its upstream tasks and waits for the summary
+func (s *MCPServer) runTask(target string, concurrency int) (interface{}, error) {
+	run, err := s.processMgr.RunTask(target, concurrency)
+	if err != nil {
+		return nil, err
+	}
+	<-run.Done()
+
+	summary := run.Summary()
+	return map[string]interface{}{
+		"target":   summary.Target,
+		"total":    summary.Total,
+		"passed":   summary.Passed,
+		"failed":   summary.Failed,
+		"skipped":  summary.Skipped,
+		"duration": summary.Duration.String(),
+		"tasks":    summary.Results,
+		"message":  summary.String(),
+	}, nil
+}
+
+// summarizeMetrics describes the trend across a process's resource samples
+func summarizeMetrics(samples []process.MetricSample) map[string]interface{} {
+	first, last := samples[0], samples[len(samples)-1]
+
+	var cpuTotal, cpuMax float64
+	rssMin, rssMax := first.RSSBytes, first.RSSBytes
+	for _, sample := range samples {
+		cpuTotal += sample.CPUPercent
+		if sample.CPUPercent > cpuMax {
+			cpuMax = sample.CPUPercent
+		}
+		if sample.RSSBytes < rssMin {
+			rssMin = sample.RSSBytes
+		}
+		if sample.RSSBytes > rssMax {
+			rssMax = sample.RSSBytes
+		}
+	}
+
+	return map[string]interface{}{
+		"samples":        len(samples),
+		"window":         last.Time.Sub(first.Time).Round(time.Second).String(),
+		"cpuAvgPercent":  cpuTotal / float64(len(samples)),
+		"cpuMaxPercent":  cpuMax,
+		"rssMinBytes":    rssMin,
+		"rssMaxBytes":    rssMax,
+		"rssGrowthBytes": int64(last.RSSBytes) - int64(first.RSSBytes),
+		"fdGrowth":       last.OpenFDs - first.OpenFDs,
+	}
 }
 
 func (s *MCPServer) registerLogTools() {
@@ -565,18 +1193,8 @@ For detailed documentation and examples, use: about tool="logs_stream"`,
 			params.Limit = 100
 			json.Unmarshal(args, &params)
 
-			// Send historical logs first
-			var logs []interface{}
-			if params.ProcessID != "" {
-				logs = s.logStoreGetByProcessInterface(params.ProcessID)
-			} else {
-				logs = s.logStoreGetAllInterface()
-			}
-
-			// Apply limit
-			if len(logs) > params.Limit {
-				logs = logs[len(logs)-params.Limit:]
-			}
+			// Send historical logs first, reaching into the history on disk
+			logs := s.logStoreRecentInterface(params.ProcessID, params.Limit)
 
 			for _, log := range logs {
 				send(map[string]interface{}{
@@ -638,16 +1256,7 @@ For detailed documentation and examples, use: about tool="logs_stream"`,
 			params.Limit = 100
 			json.Unmarshal(args, &params)
 
-			var logs []interface{}
-			if params.ProcessID != "" {
-				logs = s.logStoreGetByProcessInterface(params.ProcessID)
-			} else {
-				logs = s.logStoreGetAllInterface()
-			}
-
-			if len(logs) > params.Limit {
-				logs = logs[len(logs)-params.Limit:]
-			}
+			logs := s.logStoreRecentInterface(params.ProcessID, params.Limit)
 
 			result := map[string]interface{}{
 				"logs": logs,
@@ -702,7 +1311,16 @@ For detailed documentation and examples, use: about tool="logs_stream"`,
 		Name: "logs_search",
 		Description: `Search through historical logs using text patterns, regex, and advanced filtering.
 
+The query language combines field terms, phrases and regex literals:
+  process:api level>=warn tag:build "timeout" since:10m -"healthcheck"
+Fields: process, level, tag, priority, message, since, until. Any other field matches the
+fields of JSON and logfmt lines, e.g. status:504 or duration_ms>=250; such matches carry a
+structured object with the line's level, message, error, stack and attributes.
+Terms combine with AND (implied), OR, NOT or a leading -, and parentheses. A syntax error
+reports its column.
+
 Supports time-range filtering, level filtering, and file output for saving search results.
+Returns the newest matches; page back through the history kept on disk with before.
 
 For detailed documentation and examples, use: about tool="logs_search"`,
 		InputSchema: json.RawMessage(`{
@@ -710,12 +1328,12 @@ For detailed documentation and examples, use: about tool="logs_search"`,
 			"properties": {
 				"query": {
 					"type": "string",
-					"description": "Search query (text or regex)"
+					"description": "Search query, e.g. process:api level>=warn \"timeout\" since:10m -healthcheck"
 				},
 				"regex": {
 					"type": "boolean",
 					"default": false,
-					"description": "Whether query is a regex pattern"
+					"description": "Treat the whole query as one regex pattern instead of a query"
 				},
 				"level": {
 					"type": "string",
@@ -731,10 +1349,15 @@ For detailed documentation and examples, use: about tool="logs_search"`,
 					"format": "date-time",
 					"description": "Search logs since this time"
 				},
+				"before": {
+					"type": "string",
+					"format": "date-time",
+					"description": "Search logs before this time; pass the timestamp of the oldest result to get the previous page"
+				},
 				"limit": {
 					"type": "integer",
 					"default": 100,
-					"description": "Maximum results to return"
+					"description": "Maximum results to return, newest first"
 				},
 				"output_file": {
 					"type": "string",
@@ -750,6 +1373,7 @@ For detailed documentation and examples, use: about tool="logs_search"`,
 				Level      string `json:"level"`
 				ProcessID  string `json:"processId"`
 				Since      string `json:"since"`
+				Before     string `json:"before"`
 				Limit      int    `json:"limit"`
 				OutputFile string `json:"output_file"`
 			}
@@ -758,66 +1382,82 @@ For detailed documentation and examples, use: about tool="logs_search"`,
 				return nil, err
 			}
 
-			results := s.logStore.Search(params.Query)
-
-			// Parse since time if provided
-			var sinceTime time.Time
+			// Parse since and before times if provided
+			var sinceTime, beforeTime time.Time
 			if params.Since != "" {
 				if t, err := time.Parse(time.RFC3339, params.Since); err == nil {
 					sinceTime = t
 				}
 			}
-
-			// Apply additional filters
-			filtered := make([]interface{}, 0)
-			for _, logEntry := range results {
-				// Filter by level
-				if params.Level != "" && params.Level != "all" {
-					switch params.Level {
-					case "error":
-						if !logEntry.IsError {
-							continue
-						}
-					case "warn":
-						// Simple heuristic for warnings
-						if !strings.Contains(strings.ToLower(logEntry.Content), "warn") {
-							continue
-						}
-					case "info":
-						if logEntry.IsError {
-							continue
-						}
-					}
+			if params.Before != "" {
+				t, err := time.Parse(time.RFC3339Nano, params.Before)
+				if err != nil {
+					return nil, fmt.Errorf("invalid before time: %w", err)
 				}
+				beforeTime = t
+			}
 
-				// Filter by processId
-				if params.ProcessID != "" {
-					if logEntry.ProcessID != params.ProcessID {
-						continue
-					}
+			// Parse the query; regex treats the whole query as one pattern
+			var query *logs.Query
+			var err error
+			if params.Regex {
+				query, err = logs.RegexQuery(params.Query)
+			} else {
+				query, err = logs.ParseQuery(params.Query)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid query: %w", err)
+			}
+
+			// The newest matches first, reaching into the history on disk
+			page := query.Page(beforeTime, params.Limit)
+			if sinceTime.After(page.Since) {
+				page.Since = sinceTime
+			}
+			page.Match = func(logEntry logs.LogEntry) bool {
+				if !query.Match(logEntry) {
+					return false
 				}
 
-				// Filter by since time
-				if !sinceTime.IsZero() {
-					if logEntry.Timestamp.Before(sinceTime) {
-						continue
+				// Filter by level
+				switch params.Level {
+				case "error":
+					if !logEntry.IsError {
+						return false
+					}
+				case "warn":
+					// Simple heuristic for warnings
+					if !strings.Contains(strings.ToLower(logEntry.Content), "warn") {
+						return false
+					}
+				case "info":
+					if logEntry.IsError {
+						return false
 					}
 				}
 
-				// Convert to interface format for JSON response
-				filtered = append(filtered, map[string]interface{}{
+				// Filter by processId
+				return params.ProcessID == "" || logEntry.ProcessID == params.ProcessID
+			}
+			results := s.logStore.Page(page)
+
+			// Convert to interface format for JSON response
+			filtered := make([]interface{}, 0, len(results))
+			for _, logEntry := range results {
+				item := map[string]interface{}{
 					"id":          logEntry.ID,
 					"processId":   logEntry.ProcessID,
 					"processName": logEntry.ProcessName,
-					"timestamp":   logEntry.Timestamp.Format(time.RFC3339),
+					"timestamp":   logEntry.Timestamp.Format(time.RFC3339Nano),
 					"message":     logEntry.Content,
 					"isError":     logEntry.IsError,
 					"tags":        logEntry.Tags,
 					"priority":    logEntry.Priority,
-				})
-				if len(filtered) >= params.Limit {
-					break
 				}
+				if logEntry.Structured != nil {
+					item["structured"] = logEntry.Structured
+				}
+				filtered = append(filtered, item)
 			}
 
 			result := map[string]interface{}{
@@ -841,6 +1481,7 @@ For detailed documentation and examples, use: about tool="logs_search"`,
 						"level":     params.Level,
 						"processId": params.ProcessID,
 						"since":     params.Since,
+						"before":    params.Before,
 						"limit":     params.Limit,
 					},
 					"count":   len(filtered),
@@ -871,6 +1512,100 @@ For detailed documentation and examples, use: about tool="logs_search"`,
 			return result, nil
 		},
 	}
+
+	// errors_list - Report errors by fingerprint
+	s.tools["errors_list"] = MCPTool{
+		Name: "errors_list",
+		Description: `List the errors seen in this project by fingerprint, with when each was first and last seen, how often it happened and in which processes.
+
+A fingerprint is computed from the error type, the message with numbers, times and IDs taken out, and the top frames of the application's own code, so the same error keeps its fingerprint across hot reloads and restarts. Occurrences are kept next to the log history and survive restarts. Errors marked ignored, or resolved and not seen since, are left out of the Errors view; an error seen again after it was resolved is reopened with regressed set.
+
+For detailed documentation and examples, use: about tool="errors_list"`,
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"status": {
+					"type": "string",
+					"enum": ["open", "resolved", "ignored"],
+					"description": "Only list errors with this status (default all)"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Number of most recently seen errors to return (default all)"
+				}
+			}
+		}`),
+		Handler: func(args json.RawMessage) (interface{}, error) {
+			var params struct {
+				Status string `json:"status"`
+				Limit  int    `json:"limit"`
+			}
+			json.Unmarshal(args, &params)
+
+			fingerprints, err := s.logStore.GetErrorFingerprints()
+			if err != nil {
+				return nil, err
+			}
+			if params.Status != "" {
+				status, ok := logs.ParseErrorStatus(params.Status)
+				if !ok {
+					return nil, fmt.Errorf("invalid status %q: use open, resolved or ignored", params.Status)
+				}
+				filtered := fingerprints[:0]
+				for _, fp := range fingerprints {
+					if fp.Status == status {
+						filtered = append(filtered, fp)
+					}
+				}
+				fingerprints = filtered
+			}
+			if params.Limit > 0 && len(fingerprints) > params.Limit {
+				fingerprints = fingerprints[:params.Limit]
+			}
+			return map[string]interface{}{
+				"errors": fingerprints,
+			}, nil
+		},
+	}
+
+	// errors_set_status - Resolve or ignore an error
+	s.tools["errors_set_status"] = MCPTool{
+		Name: "errors_set_status",
+		Description: `Mark an error resolved, ignored or open again by its fingerprint from errors_list or the Errors view.
+
+A resolved error is hidden until it happens again, when it is reopened and marked regressed. An ignored error stays hidden but is still counted. A unique prefix of the fingerprint is enough.
+
+For detailed documentation and examples, use: about tool="errors_set_status"`,
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"fingerprint": {
+					"type": "string",
+					"description": "Fingerprint of the error, or a unique prefix of it"
+				},
+				"status": {
+					"type": "string",
+					"enum": ["open", "resolved", "ignored"],
+					"description": "New status of the error"
+				}
+			},
+			"required": ["fingerprint", "status"]
+		}`),
+		Handler: func(args json.RawMessage) (interface{}, error) {
+			var params struct {
+				Fingerprint string `json:"fingerprint"`
+				Status      string `json:"status"`
+			}
+			if err := json.Unmarshal(args, &params); err != nil {
+				return nil, err
+			}
+			status, ok := logs.ParseErrorStatus(params.Status)
+			if !ok {
+				return nil, fmt.Errorf("invalid status %q: use open, resolved or ignored", params.Status)
+			}
+			return s.logStore.SetErrorStatus(params.Fingerprint, status)
+		},
+	}
 }
 
 func (s *MCPServer) registerProxyTools() {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -37,9 +38,39 @@ type ExecutableCommand struct {
 	Priority    int // Higher priority commands appear first
 }
 
+// Categories of the project tasks a developer runs by name, next to package.json
+// scripts
+const (
+	CategoryMakefile = "Makefile"
+	CategoryJustfile = "justfile"
+	CategoryTaskfile = "Taskfile"
+
+	CategoryPythonScripts = "Python scripts"
+	CategoryHatch         = "Hatch scripts"
+	CategoryPDM           = "PDM scripts"
+	CategoryTaskipy       = "Taskipy tasks"
+)
+
+var projectTaskCategories = map[string]bool{
+	CategoryMakefile: true,
+	CategoryJustfile: true,
+	CategoryTaskfile: true,
+
+	CategoryPythonScripts: true,
+	CategoryHatch:         true,
+	CategoryPDM:           true,
+	CategoryTaskipy:       true,
+}
+
+// IsProjectTask reports whether the command is a task runner target or pyproject.toml
+// script that can be run by name like a script
+func (c ExecutableCommand) IsProjectTask() bool {
+	return projectTaskCategories[c.Category]
+}
+
 // MonorepoInfo contains information about a monorepo structure
 type MonorepoInfo struct {
-	Type       string // pnpm, npm-workspaces, yarn-workspaces, lerna, nx, rush
+	Type       string // pnpm, npm-workspaces, yarn-workspaces, lerna, nx, rush, cargo, go-work
 	Root       string
 	Workspaces []string
 	Packages   []PackageInfo
@@ -47,10 +78,12 @@ type MonorepoInfo struct {
 
 // PackageInfo represents a package in a monorepo
 type PackageInfo struct {
-	Path        string
-	Name        string
-	Scripts     map[string]string
-	HasLockFile bool
+	Path         string
+	Name         string
+	Scripts      map[string]string
+	Dependencies []string // names from dependencies and devDependencies, sorted
+	HasLockFile  bool
+	Commands     []ExecutableCommand // commands run from the workspace root, for Cargo crates and Go modules
 }
 
 // DetectProjectCommands detects available commands based on project files
@@ -110,7 +143,10 @@ func DetectProjectCommands(projectPath string) []ExecutableCommand {
 		commands = append(commands, dotnetCommands()...)
 	}
 
-	// Check for Python
+	// Check for Python, starting with the scripts and tasks pyproject.toml declares
+	if _, err := os.Stat(filepath.Join(projectPath, "pyproject.toml")); err == nil {
+		commands = append(commands, pyprojectCommands(projectPath)...)
+	}
 	if _, err := os.Stat(filepath.Join(projectPath, "setup.py")); err == nil {
 		commands = append(commands, pythonCommands()...)
 	} else if _, err := os.Stat(filepath.Join(projectPath, "pyproject.toml")); err == nil {
@@ -138,11 +174,42 @@ func DetectProjectCommands(projectPath string) []ExecutableCommand {
 	vscodeCommands := detectVSCodeTasks(projectPath)
 	commands = append(commands, vscodeCommands...)
 
+	// Add Makefile targets, justfile recipes and Taskfile tasks
+	commands = append(commands, detectTaskRunners(projectPath)...)
+
 	return commands
 }
 
-// DetectMonorepo detects if the project is a monorepo and returns info
+// DetectMonorepo detects if the project is a monorepo and returns info. A JS
+// workspace, a Cargo workspace and a go.work file can sit side by side; the members
+// of all of them are listed and Type names each, e.g. "pnpm+cargo+go-work".
 func DetectMonorepo(projectPath string) (*MonorepoInfo, error) {
+	info, err := detectJSMonorepo(projectPath)
+
+	var others []*MonorepoInfo
+	if cargo := detectCargoWorkspace(projectPath); cargo != nil {
+		others = append(others, cargo)
+	}
+	if goWork := detectGoWorkspace(projectPath); goWork != nil {
+		others = append(others, goWork)
+	}
+	if len(others) == 0 {
+		return info, err
+	}
+
+	if err != nil || info == nil {
+		info, others = others[0], others[1:]
+	}
+	for _, other := range others {
+		info.Type += "+" + other.Type
+		info.Workspaces = append(info.Workspaces, other.Workspaces...)
+		info.Packages = append(info.Packages, other.Packages...)
+	}
+	return info, nil
+}
+
+// detectJSMonorepo detects pnpm, npm and yarn workspaces, Lerna, Nx and Rush
+func detectJSMonorepo(projectPath string) (*MonorepoInfo, error) {
 	// Check for pnpm workspace
 	if _, err := os.Stat(filepath.Join(projectPath, "pnpm-workspace.yaml")); err == nil {
 		return detectPnpmWorkspace(projectPath)
@@ -286,11 +353,27 @@ func loadPackageInfo(pkgPath string) *PackageInfo {
 	}
 
 	return &PackageInfo{
-		Path:        pkgPath,
-		Name:        pkg.Name,
-		Scripts:     pkg.Scripts,
-		HasLockFile: hasLock,
+		Path:         pkgPath,
+		Name:         pkg.Name,
+		Scripts:      pkg.Scripts,
+		Dependencies: dependencyNames(pkg),
+		HasLockFile:  hasLock,
+	}
+}
+
+// dependencyNames lists the packages a package.json depends on, dev dependencies included
+func dependencyNames(pkg *PackageJSON) []string {
+	names := make([]string, 0, len(pkg.Dependencies)+len(pkg.DevDependencies))
+	for name := range pkg.Dependencies {
+		names = append(names, name)
+	}
+	for name := range pkg.DevDependencies {
+		if _, ok := pkg.Dependencies[name]; !ok {
+			names = append(names, name)
+		}
 	}
+	sort.Strings(names)
+	return names
 }
 
 // Command generators for different project types
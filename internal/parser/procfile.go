@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ProcfileEntry is one process type declared in a Procfile
+type ProcfileEntry struct {
+	Name    string
+	Command string // shell command line
+}
+
+// procfileLine matches "name: command"; names follow the foreman convention
+var procfileLine = regexp.MustCompile(`^([A-Za-z0-9_-]+):\s*(.+)$`)
+
+// ParseProcfile reads the process types from a Procfile, in file order. Blank lines
+// and lines starting with # are ignored.
+func ParseProcfile(path string) ([]ProcfileEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Procfile: %w", err)
+	}
+	defer file.Close()
+
+	var entries []ProcfileEntry
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		match := procfileLine.FindStringSubmatch(line)
+		if match == nil {
+			return nil, fmt.Errorf("failed to parse Procfile line %d: expected 'name: command'", lineNum)
+		}
+		if seen[match[1]] {
+			return nil, fmt.Errorf("failed to parse Procfile line %d: '%s' is declared twice", lineNum, match[1])
+		}
+		seen[match[1]] = true
+		entries = append(entries, ProcfileEntry{Name: match[1], Command: strings.TrimSpace(match[2])})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read Procfile: %w", err)
+	}
+
+	return entries, nil
+}
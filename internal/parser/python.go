@@ -0,0 +1,283 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// PythonRunner is the tool that runs commands inside a Python project's environment
+type PythonRunner string
+
+const (
+	Poetry PythonRunner = "poetry"
+	Hatch  PythonRunner = "hatch"
+	PDM    PythonRunner = "pdm"
+	UV     PythonRunner = "uv"
+	Venv   PythonRunner = "venv" // the project's virtualenv, or the python on PATH
+)
+
+type InstalledPythonRunner struct {
+	Runner  PythonRunner
+	Version string
+	Path    string
+}
+
+var (
+	cachedPythonRunners []InstalledPythonRunner
+	pythonRunnersOnce   sync.Once
+)
+
+// DetectInstalledPythonRunners checks which Python project tools are installed on the system
+func DetectInstalledPythonRunners() []InstalledPythonRunner {
+	pythonRunnersOnce.Do(func() {
+		cachedPythonRunners = detectInstalledPythonRunnersUncached()
+	})
+	return cachedPythonRunners
+}
+
+// detectInstalledPythonRunnersUncached performs the actual detection
+func detectInstalledPythonRunnersUncached() []InstalledPythonRunner {
+	var installed []InstalledPythonRunner
+
+	for _, runner := range []PythonRunner{Poetry, Hatch, PDM, UV} {
+		path, err := findExecutable(string(runner))
+		if err != nil {
+			continue
+		}
+
+		// Get version with timeout
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		output, err := exec.CommandContext(ctx, string(runner), "--version").Output()
+		cancel()
+		if err != nil {
+			continue
+		}
+
+		installed = append(installed, InstalledPythonRunner{
+			Runner:  runner,
+			Version: strings.TrimSpace(string(output)),
+			Path:    path,
+		})
+	}
+
+	return installed
+}
+
+// PyProject is the part of pyproject.toml that declares scripts and tasks
+type PyProject struct {
+	Project struct {
+		Name    string            `toml:"name"`
+		Scripts map[string]string `toml:"scripts"` // console entry points, name = "module:function"
+	} `toml:"project"`
+	Tool struct {
+		Poetry *struct {
+			Scripts map[string]interface{} `toml:"scripts"` // entry points, as a string or a table with callable
+		} `toml:"poetry"`
+		Hatch *struct {
+			Envs map[string]struct {
+				Scripts map[string]interface{} `toml:"scripts"` // a command or a list of commands
+			} `toml:"envs"`
+		} `toml:"hatch"`
+		PDM *struct {
+			Scripts map[string]interface{} `toml:"scripts"` // a command, or a table with cmd, shell, call or composite
+		} `toml:"pdm"`
+		UV      map[string]interface{} `toml:"uv"`
+		Taskipy *struct {
+			Tasks map[string]interface{} `toml:"tasks"` // a command, or a table with cmd and help
+		} `toml:"taskipy"`
+	} `toml:"tool"`
+}
+
+// ParsePyProject reads pyproject.toml
+func ParsePyProject(path string) (*PyProject, error) {
+	var project PyProject
+	if _, err := toml.DecodeFile(path, &project); err != nil {
+		return nil, fmt.Errorf("failed to parse pyproject.toml: %w", err)
+	}
+	return &project, nil
+}
+
+// DetectPythonRunner determines the runner for a Python project, like
+// GetPreferredPackageManager does for JS:
+// 1. The tool whose lock file or [tool.*] table the project has, if it is installed
+// 2. The project's virtualenv
+// 3. uv, if it is installed
+// 4. The python on PATH
+func DetectPythonRunner(projectPath string, project *PyProject) PythonRunner {
+	installed := make(map[PythonRunner]bool)
+	for _, runner := range DetectInstalledPythonRunners() {
+		installed[runner.Runner] = true
+	}
+
+	fileExists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(projectPath, name))
+		return err == nil
+	}
+	var preferred []PythonRunner
+	if fileExists("poetry.lock") || (project != nil && project.Tool.Poetry != nil) {
+		preferred = append(preferred, Poetry)
+	}
+	if fileExists("pdm.lock") || (project != nil && project.Tool.PDM != nil) {
+		preferred = append(preferred, PDM)
+	}
+	if fileExists("uv.lock") || (project != nil && project.Tool.UV != nil) {
+		preferred = append(preferred, UV)
+	}
+	if fileExists("hatch.toml") || (project != nil && project.Tool.Hatch != nil) {
+		preferred = append(preferred, Hatch)
+	}
+	for _, runner := range preferred {
+		if installed[runner] {
+			return runner
+		}
+	}
+
+	if venvBin(projectPath) != "" {
+		return Venv
+	}
+	if installed[UV] {
+		return UV
+	}
+	return Venv
+}
+
+// RunCommand returns the command line that runs argv in the project's environment
+func (r PythonRunner) RunCommand(projectPath string, argv ...string) []string {
+	switch r {
+	case Poetry, Hatch, PDM, UV:
+		return append([]string{string(r), "run"}, argv...)
+	}
+	if bin := venvBin(projectPath); bin != "" {
+		exe := filepath.Join(bin, argv[0])
+		if runtime.GOOS == "windows" {
+			exe += ".exe"
+		}
+		if _, err := os.Stat(exe); err == nil {
+			return append([]string{exe}, argv[1:]...)
+		}
+	}
+	return argv
+}
+
+// venvBin returns the scripts directory of the project's .venv or venv, or ""
+func venvBin(projectPath string) string {
+	binDir, python := "bin", "python"
+	if runtime.GOOS == "windows" {
+		binDir, python = "Scripts", "python.exe"
+	}
+	for _, name := range []string{".venv", "venv"} {
+		bin := filepath.Join(projectPath, name, binDir)
+		if _, err := os.Stat(filepath.Join(bin, python)); err == nil {
+			return bin
+		}
+	}
+	return ""
+}
+
+// pyprojectCommands returns the scripts and tasks declared in pyproject.toml. Entry
+// points and taskipy tasks run through the project's runner; Hatch and PDM scripts
+// run through their own tool.
+func pyprojectCommands(projectPath string) []ExecutableCommand {
+	project, err := ParsePyProject(filepath.Join(projectPath, "pyproject.toml"))
+	if err != nil {
+		return nil
+	}
+	runner := DetectPythonRunner(projectPath, project)
+
+	var commands []ExecutableCommand
+	add := func(name, description, category string, cmdLine []string) {
+		commands = append(commands, ExecutableCommand{
+			Name:        name,
+			Command:     cmdLine[0],
+			Args:        cmdLine[1:],
+			Description: description,
+			Category:    category,
+			ProjectType: ProjectTypePython,
+			Priority:    90,
+		})
+	}
+
+	entryPoints := make(map[string]string)
+	for name, target := range project.Project.Scripts {
+		entryPoints[name] = target
+	}
+	if project.Tool.Poetry != nil {
+		for name, value := range project.Tool.Poetry.Scripts {
+			entryPoints[name] = pyTaskText(value, "callable", "reference")
+		}
+	}
+	for _, name := range sortedKeys(entryPoints) {
+		add(name, fmt.Sprintf("Entry point %s", entryPoints[name]), CategoryPythonScripts, runner.RunCommand(projectPath, name))
+	}
+
+	if project.Tool.Hatch != nil {
+		for _, env := range sortedKeys(project.Tool.Hatch.Envs) {
+			scripts := project.Tool.Hatch.Envs[env].Scripts
+			for _, name := range sortedKeys(scripts) {
+				target := name
+				if env != "default" {
+					target = env + ":" + name
+				}
+				add(target, pyTaskText(scripts[name]), CategoryHatch, []string{"hatch", "run", target})
+			}
+		}
+	}
+
+	if project.Tool.PDM != nil {
+		for _, name := range sortedKeys(project.Tool.PDM.Scripts) {
+			if name == "_" {
+				continue // options shared by every script
+			}
+			add(name, pyTaskText(project.Tool.PDM.Scripts[name], "help", "cmd", "shell", "call", "composite"), CategoryPDM, []string{"pdm", "run", name})
+		}
+	}
+
+	if project.Tool.Taskipy != nil {
+		for _, name := range sortedKeys(project.Tool.Taskipy.Tasks) {
+			add(name, pyTaskText(project.Tool.Taskipy.Tasks[name], "help", "cmd"), CategoryTaskipy, runner.RunCommand(projectPath, "task", name))
+		}
+	}
+
+	return commands
+}
+
+// pyTaskText describes a script declared as a string, a list of commands, or a table
+// whose first non-empty field among fields is used
+func pyTaskText(value interface{}, fields ...string) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, pyTaskText(item, fields...))
+		}
+		return strings.Join(parts, " && ")
+	case map[string]interface{}:
+		for _, field := range fields {
+			if text := pyTaskText(v[field]); text != "" {
+				return text
+			}
+		}
+	}
+	return ""
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPyprojectCommands(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "pyproject.toml", `[project]
+name = "shop"
+
+[project.scripts]
+shop-api = "shop.api:main"
+
+[tool.poetry.scripts]
+migrate = { callable = "shop.db:migrate" }
+
+[tool.hatch.envs.default.scripts]
+cov = "pytest --cov"
+
+[tool.hatch.envs.docs.scripts]
+build = ["mkdocs build", "mkdocs serve"]
+
+[tool.pdm.scripts]
+_ = { env_file = ".env" }
+start = { cmd = "flask run", help = "Start the dev server" }
+lint = "ruff check ."
+
+[tool.taskipy.tasks]
+test = { cmd = "pytest", help = "Run the tests" }
+fmt = "black ."
+`)
+
+	commands := DetectProjectCommands(dir)
+	entryPoints := commandsByName(commands, "Python scripts")
+	require.Len(t, entryPoints, 2)
+	assert.Equal(t, "Entry point shop.api:main", entryPoints["shop-api"].Description)
+	assert.Equal(t, "Entry point shop.db:migrate", entryPoints["migrate"].Description)
+	migrate := commandLine(entryPoints["migrate"])
+	assert.Equal(t, "migrate", migrate[len(migrate)-1])
+
+	hatch := commandsByName(commands, "Hatch scripts")
+	require.Len(t, hatch, 2)
+	assert.Equal(t, []string{"run", "cov"}, hatch["cov"].Args)
+	assert.Equal(t, []string{"run", "docs:build"}, hatch["docs:build"].Args)
+	assert.Equal(t, "mkdocs build && mkdocs serve", hatch["docs:build"].Description)
+
+	pdm := commandsByName(commands, "PDM scripts")
+	require.Len(t, pdm, 2)
+	assert.Equal(t, "pdm", pdm["start"].Command)
+	assert.Equal(t, "Start the dev server", pdm["start"].Description)
+	assert.Equal(t, "ruff check .", pdm["lint"].Description)
+
+	tasks := commandsByName(commands, "Taskipy tasks")
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "Run the tests", tasks["test"].Description)
+	line := commandLine(tasks["test"])
+	assert.Equal(t, []string{"task", "test"}, line[len(line)-2:])
+
+	// Every pyproject.toml script can be started by name, like a package.json script
+	var projectTasks []string
+	for _, cmd := range commands {
+		if cmd.IsProjectTask() {
+			projectTasks = append(projectTasks, cmd.Name)
+		}
+	}
+	assert.ElementsMatch(t, []string{"shop-api", "migrate", "cov", "docs:build", "start", "lint", "test", "fmt"}, projectTasks)
+}
+
+// commandLine is the command followed by its arguments
+func commandLine(cmd ExecutableCommand) []string {
+	return append([]string{cmd.Command}, cmd.Args...)
+}
+
+func TestPythonRunnerRunCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("virtualenv layout differs on Windows")
+	}
+	dir := t.TempDir()
+	bin := filepath.Join(dir, ".venv", "bin")
+	require.NoError(t, os.MkdirAll(bin, 0755))
+	writeFile(t, bin, "python", "")
+	writeFile(t, bin, "shop-api", "")
+
+	assert.Equal(t, []string{filepath.Join(bin, "shop-api"), "--port", "80"}, Venv.RunCommand(dir, "shop-api", "--port", "80"))
+	assert.Equal(t, []string{"task", "test"}, Venv.RunCommand(dir, "task", "test"))
+	assert.Equal(t, []string{"poetry", "run", "shop-api"}, Poetry.RunCommand(dir, "shop-api"))
+	assert.Equal(t, []string{"uv", "run", "task", "test"}, UV.RunCommand(dir, "task", "test"))
+}
@@ -0,0 +1,589 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// TaskGraph is the task pipeline a Turborepo or Nx workspace declares: the tasks each
+// package has and the tasks that must finish before them
+type TaskGraph struct {
+	Tool     string // turbo or nx
+	Root     string
+	projects map[string]*taskProject
+	defaults map[string]taskDef // tasks declared for every package
+}
+
+// taskProject is a package or Nx project in the graph
+type taskProject struct {
+	name    string
+	dir     string
+	scripts map[string]string
+	deps    []string           // workspace projects this one depends on
+	targets map[string]taskDef // tasks declared for this project only
+}
+
+// taskDef is a task in turbo.json or an Nx target
+type taskDef struct {
+	dependsOn []taskDep // nil inherits the dependencies declared for every package
+	command   string    // shell command of an Nx run-commands target
+	script    string    // package script an Nx run-script target runs
+	executor  string
+}
+
+// taskDep is one dependsOn entry
+type taskDep struct {
+	task     string
+	upstream bool     // the task in every workspace dependency, written ^task
+	projects []string // the task in these projects; the same project when empty
+}
+
+// TaskNode is one package task in a plan
+type TaskNode struct {
+	ID        string   `json:"id"` // package#task
+	Package   string   `json:"package"`
+	Task      string   `json:"task"`
+	Dir       string   `json:"dir"`
+	Script    string   `json:"script,omitempty"`    // package script to run
+	Command   string   `json:"command,omitempty"`   // shell command to run when there is no script
+	DependsOn []string `json:"dependsOn,omitempty"` // nodes that must succeed first
+}
+
+// LoadTaskGraph reads the task pipeline from turbo.json or, failing that, from nx.json
+// and the project.json files of an Nx workspace
+func LoadTaskGraph(root string) (*TaskGraph, error) {
+	if data, err := os.ReadFile(filepath.Join(root, "turbo.json")); err == nil {
+		return loadTurboGraph(root, data)
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "nx.json")); err == nil {
+		return loadNxGraph(root, data)
+	}
+	return nil, fmt.Errorf("no turbo.json or nx.json found")
+}
+
+// Projects returns the names of the packages in the graph, sorted
+func (g *TaskGraph) Projects() []string {
+	names := make([]string, 0, len(g.projects))
+	for name := range g.projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Plan returns the tasks that running task in project takes, upstream tasks first.
+// Tasks a package does not define are left out, but what they depend on still runs.
+func (g *TaskGraph) Plan(project, task string) ([]TaskNode, error) {
+	target, ok := g.projects[project]
+	if !ok {
+		return nil, fmt.Errorf("no package named '%s' in the %s workspace", project, g.Tool)
+	}
+	if !g.runnable(target, task) {
+		return nil, fmt.Errorf("package '%s' has no '%s' task", project, task)
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	reach := make(map[string][]string) // the runnable nodes a task stands for
+	var nodes []TaskNode
+	var path []string
+
+	var visit func(p *taskProject, task string) ([]string, error)
+	visit = func(p *taskProject, task string) ([]string, error) {
+		id := p.name + "#" + task
+		switch state[id] {
+		case visited:
+			return reach[id], nil
+		case visiting:
+			start := 0
+			for i, step := range path {
+				if step == id {
+					start = i
+					break
+				}
+			}
+			cycle := append(append([]string{}, path[start:]...), id)
+			return nil, fmt.Errorf("circular task dependency: %s", strings.Join(cycle, " -> "))
+		}
+
+		state[id] = visiting
+		path = append(path, id)
+		var upstream []string
+		for _, dep := range g.taskDef(p, task).dependsOn {
+			for _, next := range g.depProjects(p, dep) {
+				ids, err := visit(next, dep.task)
+				if err != nil {
+					return nil, err
+				}
+				for _, depID := range ids {
+					if !contains(upstream, depID) {
+						upstream = append(upstream, depID)
+					}
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = visited
+
+		if g.runnable(p, task) {
+			nodes = append(nodes, g.node(p, task, upstream))
+			reach[id] = []string{id}
+		} else {
+			reach[id] = upstream
+		}
+		return reach[id], nil
+	}
+
+	if _, err := visit(target, task); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// taskDef returns how task is declared for p, falling back to the declaration for
+// every package
+func (g *TaskGraph) taskDef(p *taskProject, task string) taskDef {
+	def, ok := p.targets[task]
+	if !ok {
+		return g.defaults[task]
+	}
+	if def.dependsOn == nil {
+		def.dependsOn = g.defaults[task].dependsOn
+	}
+	return def
+}
+
+// depProjects returns the projects a dependsOn entry of p points at
+func (g *TaskGraph) depProjects(p *taskProject, dep taskDep) []*taskProject {
+	names := dep.projects
+	switch {
+	case dep.upstream:
+		names = p.deps
+	case len(names) == 0:
+		return []*taskProject{p}
+	}
+	var projects []*taskProject
+	for _, name := range names {
+		if next, ok := g.projects[name]; ok {
+			projects = append(projects, next)
+		}
+	}
+	return projects
+}
+
+// runnable reports whether p has something to run for task. Turborepo runs package
+// scripts; Nx also runs targets from project.json.
+func (g *TaskGraph) runnable(p *taskProject, task string) bool {
+	if _, ok := p.scripts[task]; ok {
+		return true
+	}
+	if g.Tool == "nx" {
+		_, ok := p.targets[task]
+		return ok
+	}
+	return false
+}
+
+func (g *TaskGraph) node(p *taskProject, task string, upstream []string) TaskNode {
+	node := TaskNode{
+		ID:        p.name + "#" + task,
+		Package:   p.name,
+		Task:      task,
+		Dir:       p.dir,
+		DependsOn: upstream,
+	}
+	def := p.targets[task]
+	switch {
+	case def.command != "":
+		node.Command = def.command
+	case def.script != "":
+		node.Script = def.script
+	case p.scripts[task] != "":
+		node.Script = task
+	default:
+		// Any other executor is left to Nx, without letting it run the dependencies again
+		node.Command = fmt.Sprintf("npx nx run %s:%s --excludeTaskDependencies", p.name, task)
+	}
+	return node
+}
+
+// newTaskGraph starts a graph with the packages of the workspace at root
+func newTaskGraph(tool, root string) *TaskGraph {
+	g := &TaskGraph{
+		Tool:     tool,
+		Root:     root,
+		projects: make(map[string]*taskProject),
+		defaults: make(map[string]taskDef),
+	}
+	if info, err := DetectMonorepo(root); err == nil {
+		for _, pkg := range info.Packages {
+			if pkg.Name == "" {
+				continue
+			}
+			g.projects[pkg.Name] = &taskProject{
+				name:    pkg.Name,
+				dir:     pkg.Path,
+				scripts: pkg.Scripts,
+				deps:    pkg.Dependencies,
+				targets: make(map[string]taskDef),
+			}
+		}
+	}
+	return g
+}
+
+// linkDependencies keeps only the dependencies that are packages of the workspace
+func (g *TaskGraph) linkDependencies() {
+	for _, p := range g.projects {
+		var deps []string
+		for _, dep := range p.deps {
+			if _, ok := g.projects[dep]; ok && dep != p.name && !contains(deps, dep) {
+				deps = append(deps, dep)
+			}
+		}
+		p.deps = deps
+	}
+}
+
+// checkDependencies makes sure every dependsOn entry that names a package points at
+// a package of the workspace with that task. Entries such as ^build or build may
+// match nothing, which Turborepo and Nx allow.
+func (g *TaskGraph) checkDependencies() error {
+	check := func(owner string, def taskDef) error {
+		for _, dep := range def.dependsOn {
+			for _, name := range dep.projects {
+				if strings.ContainsAny(name, "*:!") {
+					continue // an Nx pattern or tag, not a project name
+				}
+				missing := name + "#" + dep.task
+				p, ok := g.projects[name]
+				if !ok {
+					return fmt.Errorf("task '%s' depends on '%s', but there is no package named '%s'", owner, missing, name)
+				}
+				_, declared := p.targets[dep.task]
+				if _, isDefault := g.defaults[dep.task]; !declared && !isDefault && !g.runnable(p, dep.task) {
+					return fmt.Errorf("task '%s' depends on '%s', which is not defined", owner, missing)
+				}
+			}
+		}
+		return nil
+	}
+
+	for task, def := range g.defaults {
+		if err := check(task, def); err != nil {
+			return err
+		}
+	}
+	for _, p := range g.projects {
+		for task, def := range p.targets {
+			if err := check(p.name+"#"+task, def); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// loadTurboGraph reads the pipeline ("tasks" since Turborepo 2) from turbo.json.
+// Keys are a task for every package, or package#task for one of them; "//" is the
+// root package.
+func loadTurboGraph(root string, data []byte) (*TaskGraph, error) {
+	var config struct {
+		Pipeline map[string]struct {
+			DependsOn []string `json:"dependsOn"`
+		} `json:"pipeline"`
+		Tasks map[string]struct {
+			DependsOn []string `json:"dependsOn"`
+		} `json:"tasks"`
+	}
+	if err := json.Unmarshal(stripJSONComments(data), &config); err != nil {
+		return nil, fmt.Errorf("failed to parse turbo.json: %w", err)
+	}
+
+	g := newTaskGraph("turbo", root)
+	if pkg, err := ParsePackageJSON(filepath.Join(root, "package.json")); err == nil && len(pkg.Scripts) > 0 {
+		g.projects["//"] = &taskProject{name: "//", dir: root, scripts: pkg.Scripts, targets: make(map[string]taskDef)}
+	}
+
+	tasks := config.Tasks
+	if tasks == nil {
+		tasks = config.Pipeline
+	}
+	for key, task := range tasks {
+		def := taskDef{dependsOn: make([]taskDep, 0, len(task.DependsOn))}
+		for _, entry := range task.DependsOn {
+			switch {
+			case strings.HasPrefix(entry, "$"):
+				continue // an environment variable in Turborepo 1
+			case strings.HasPrefix(entry, "^"):
+				def.dependsOn = append(def.dependsOn, taskDep{task: entry[1:], upstream: true})
+			default:
+				if pkg, name, ok := strings.Cut(entry, "#"); ok {
+					def.dependsOn = append(def.dependsOn, taskDep{task: name, projects: []string{pkg}})
+				} else {
+					def.dependsOn = append(def.dependsOn, taskDep{task: entry})
+				}
+			}
+		}
+
+		if pkg, name, ok := strings.Cut(key, "#"); ok {
+			if p, exists := g.projects[pkg]; exists {
+				p.targets[name] = def
+			}
+			continue
+		}
+		g.defaults[key] = def
+	}
+	g.linkDependencies()
+	if err := g.checkDependencies(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// nxTarget is a target in project.json, or in the targetDefaults of nx.json
+type nxTarget struct {
+	Executor  string            `json:"executor"`
+	Command   string            `json:"command"`
+	DependsOn []json.RawMessage `json:"dependsOn"`
+	Options   struct {
+		Command  string            `json:"command"`
+		Commands []json.RawMessage `json:"commands"`
+		Script   string            `json:"script"`
+	} `json:"options"`
+}
+
+// loadNxGraph reads the target defaults from nx.json and the projects from the
+// workspace packages and every project.json
+func loadNxGraph(root string, data []byte) (*TaskGraph, error) {
+	var config struct {
+		TargetDefaults     map[string]nxTarget          `json:"targetDefaults"`
+		TargetDependencies map[string][]json.RawMessage `json:"targetDependencies"` // before Nx 15
+	}
+	if err := json.Unmarshal(stripJSONComments(data), &config); err != nil {
+		return nil, fmt.Errorf("failed to parse nx.json: %w", err)
+	}
+
+	g := newTaskGraph("nx", root)
+	for name, deps := range config.TargetDependencies {
+		g.defaults[name] = taskDef{dependsOn: parseNxDependsOn(deps)}
+	}
+	for name, target := range config.TargetDefaults {
+		g.defaults[name] = nxTaskDef(target)
+	}
+
+	// Package names that differ from their Nx project name
+	byPackage := make(map[string]string)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != root && (d.Name() == "node_modules" || d.Name() == "dist" || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() != "project.json" {
+			return nil
+		}
+		project, err := loadNxProject(filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		for pkgName, existing := range g.projects {
+			if existing.dir == project.dir && pkgName != project.name {
+				delete(g.projects, pkgName)
+				byPackage[pkgName] = project.name
+			}
+		}
+		g.projects[project.name] = project
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range g.projects {
+		for i, dep := range p.deps {
+			if name, ok := byPackage[dep]; ok {
+				p.deps[i] = name
+			}
+		}
+	}
+	g.linkDependencies()
+	if err := g.checkDependencies(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// loadNxProject reads project.json in dir along with the package.json beside it
+func loadNxProject(dir string) (*taskProject, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "project.json"))
+	if err != nil {
+		return nil, err
+	}
+	var config struct {
+		Name                 string              `json:"name"`
+		ImplicitDependencies []string            `json:"implicitDependencies"`
+		Targets              map[string]nxTarget `json:"targets"`
+	}
+	if err := json.Unmarshal(stripJSONComments(data), &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, "project.json"), err)
+	}
+
+	p := &taskProject{name: config.Name, dir: dir, targets: make(map[string]taskDef)}
+	if pkg, err := ParsePackageJSON(filepath.Join(dir, "package.json")); err == nil {
+		p.scripts = pkg.Scripts
+		p.deps = dependencyNames(pkg)
+		if p.name == "" {
+			p.name = pkg.Name
+		}
+	}
+	if p.name == "" {
+		p.name = filepath.Base(dir)
+	}
+	for _, dep := range config.ImplicitDependencies {
+		if !strings.HasPrefix(dep, "!") {
+			p.deps = append(p.deps, dep)
+		}
+	}
+	for name, target := range config.Targets {
+		p.targets[name] = nxTaskDef(target)
+	}
+	return p, nil
+}
+
+// nxTaskDef converts an Nx target. The commands of a run-commands target run one
+// after another.
+func nxTaskDef(target nxTarget) taskDef {
+	def := taskDef{executor: target.Executor, script: target.Options.Script}
+	if target.DependsOn != nil {
+		def.dependsOn = parseNxDependsOn(target.DependsOn)
+	}
+
+	var commands []string
+	for _, raw := range target.Options.Commands {
+		var command string
+		if json.Unmarshal(raw, &command) != nil {
+			var entry struct {
+				Command string `json:"command"`
+			}
+			json.Unmarshal(raw, &entry)
+			command = entry.Command
+		}
+		if command != "" {
+			commands = append(commands, command)
+		}
+	}
+	switch {
+	case target.Command != "":
+		def.command = target.Command
+	case target.Options.Command != "":
+		def.command = target.Options.Command
+	case len(commands) > 0:
+		def.command = strings.Join(commands, " && ")
+	}
+	return def
+}
+
+// parseNxDependsOn reads Nx dependsOn entries: "^build", "build", or objects such as
+// {"projects": "dependencies", "target": "build"} and {"dependencies": true, "target": "build"}
+func parseNxDependsOn(entries []json.RawMessage) []taskDep {
+	deps := make([]taskDep, 0, len(entries))
+	for _, raw := range entries {
+		var name string
+		if json.Unmarshal(raw, &name) == nil {
+			if task, ok := strings.CutPrefix(name, "^"); ok {
+				deps = append(deps, taskDep{task: task, upstream: true})
+			} else {
+				deps = append(deps, taskDep{task: name})
+			}
+			continue
+		}
+
+		var entry struct {
+			Target       string          `json:"target"`
+			Projects     json.RawMessage `json:"projects"`
+			Dependencies bool            `json:"dependencies"`
+		}
+		if json.Unmarshal(raw, &entry) != nil || entry.Target == "" {
+			continue
+		}
+		dep := taskDep{task: entry.Target, upstream: entry.Dependencies}
+		var projects []string
+		var single string
+		switch {
+		case json.Unmarshal(entry.Projects, &projects) == nil:
+			dep.projects = projects
+		case json.Unmarshal(entry.Projects, &single) == nil:
+			switch single {
+			case "dependencies", "{dependencies}":
+				dep.upstream = true
+			case "self", "{self}", "":
+			default:
+				dep.projects = []string{single}
+			}
+		}
+		deps = append(deps, dep)
+	}
+	return deps
+}
+
+// stripJSONComments removes // and /* */ comments, which turbo.json and the Nx files
+// allow, leaving string contents alone
+func stripJSONComments(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case inString:
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i++
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,340 @@
+package parser
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Task runner detection: Makefile targets, justfile recipes and Taskfile tasks
+
+var (
+	// makeAssignment matches variable assignments such as CC := gcc or FLAGS += -O2
+	makeAssignment = regexp.MustCompile(`^(export\s+|override\s+)?[A-Za-z0-9_.-]+\s*(\?|\+|:|::|!)?=`)
+	// makeRule matches "targets: prerequisites ## description"
+	makeRule = regexp.MustCompile(`^([^:#=\t][^:#=]*?)\s*::?([^=].*)?$`)
+	// justRecipe matches "name params: dependencies"; a leading @ silences the recipe.
+	// Assignments, aliases and settings use := and do not match.
+	justRecipe = regexp.MustCompile(`^@?([A-Za-z_][A-Za-z0-9_-]*)((?:\s+[^:]+?)?)\s*:([^=].*)?$`)
+	// justAttribute matches attribute lines such as [private] or [doc('Build it')]
+	justAttribute = regexp.MustCompile(`^\[(.*)\]\s*$`)
+	justDoc       = regexp.MustCompile(`doc\(\s*['"](.*)['"]\s*\)`)
+)
+
+// detectTaskRunners returns the Makefile targets, justfile recipes and Taskfile tasks
+// in the project
+func detectTaskRunners(projectPath string) []ExecutableCommand {
+	var commands []ExecutableCommand
+	if path := firstExisting(projectPath, "GNUmakefile", "makefile", "Makefile"); path != "" {
+		commands = append(commands, parseMakefileTargets(path)...)
+	}
+	if path := firstExisting(projectPath, "justfile", "Justfile", ".justfile"); path != "" {
+		commands = append(commands, parseJustfileRecipes(path)...)
+	}
+	if path := firstExisting(projectPath, "Taskfile.yml", "taskfile.yml", "Taskfile.yaml", "taskfile.yaml", "Taskfile.dist.yml", "taskfile.dist.yml"); path != "" {
+		commands = append(commands, parseTaskfileTasks(path)...)
+	}
+	return commands
+}
+
+// firstExisting returns the first of names that exists in dir, in the order make,
+// just and task look for them
+func firstExisting(dir string, names ...string) string {
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// parseMakefileTargets returns the targets a developer would run: those marked
+// .PHONY, those documented with a ## comment, and any other target that does not look
+// like a file. Documentation is either "target: deps ## text" or a "## text" line
+// right above the rule.
+func parseMakefileTargets(path string) []ExecutableCommand {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	type makeTarget struct {
+		name string
+		doc  string
+	}
+	var targets []makeTarget
+	phony := make(map[string]bool)
+	seen := make(map[string]bool)
+	var pendingDoc string
+	inDefine := false
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case inDefine:
+			inDefine = !strings.HasPrefix(trimmed, "endef")
+			continue
+		case strings.HasPrefix(trimmed, "define "):
+			inDefine = true
+			continue
+		case strings.HasPrefix(line, "\t"), trimmed == "":
+			pendingDoc = ""
+			continue
+		case strings.HasPrefix(trimmed, "##"):
+			pendingDoc = strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			continue
+		case strings.HasPrefix(trimmed, "#"), makeAssignment.MatchString(line):
+			pendingDoc = ""
+			continue
+		}
+
+		match := makeRule.FindStringSubmatch(line)
+		if match == nil {
+			pendingDoc = ""
+			continue
+		}
+		names := strings.Fields(match[1])
+		rest := match[2]
+		doc := pendingDoc
+		pendingDoc = ""
+		if _, comment, ok := strings.Cut(rest, "##"); ok {
+			doc = strings.TrimSpace(comment)
+		}
+
+		if len(names) == 1 && names[0] == ".PHONY" {
+			deps, _, _ := strings.Cut(rest, "#")
+			for _, name := range strings.Fields(deps) {
+				phony[name] = true
+			}
+			continue
+		}
+		for _, name := range names {
+			if strings.HasPrefix(name, ".") || strings.ContainsAny(name, "%$()") || seen[name] {
+				continue
+			}
+			seen[name] = true
+			targets = append(targets, makeTarget{name: name, doc: doc})
+		}
+	}
+
+	var commands []ExecutableCommand
+	for _, target := range targets {
+		if !phony[target.name] && target.doc == "" && strings.ContainsAny(target.name, "./") {
+			continue // a file the build produces
+		}
+		description := target.doc
+		if description == "" {
+			description = "make " + target.name
+		}
+		priority := 78
+		if target.doc != "" {
+			priority = 80
+		}
+		commands = append(commands, ExecutableCommand{
+			Name:        target.name,
+			Command:     "make",
+			Args:        []string{target.name},
+			Description: description,
+			Category:    CategoryMakefile,
+			Priority:    priority,
+		})
+	}
+	return commands
+}
+
+// parseJustfileRecipes returns the public recipes of a justfile. A recipe's
+// description is the comment above it or its [doc] attribute; recipes starting with
+// an underscore or marked [private] are left out.
+func parseJustfileRecipes(path string) []ExecutableCommand {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var commands []ExecutableCommand
+	var doc string
+	private := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			doc, private = "", false
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			if !strings.HasPrefix(trimmed, "#!") {
+				doc = strings.TrimSpace(strings.TrimPrefix(trimmed, "#"))
+			}
+			continue
+		}
+		if match := justAttribute.FindStringSubmatch(trimmed); match != nil {
+			if strings.Contains(match[1], "private") {
+				private = true
+			}
+			if docMatch := justDoc.FindStringSubmatch(match[1]); docMatch != nil {
+				doc = docMatch[1]
+			}
+			continue
+		}
+
+		match := justRecipe.FindStringSubmatch(line)
+		if match == nil {
+			doc, private = "", false
+			continue
+		}
+		name := match[1]
+		if !private && !strings.HasPrefix(name, "_") {
+			description := doc
+			if description == "" {
+				description = "just " + name
+			}
+			if params := strings.TrimSpace(match[2]); params != "" {
+				description += " (parameters: " + params + ")"
+			}
+			priority := 78
+			if doc != "" {
+				priority = 80
+			}
+			commands = append(commands, ExecutableCommand{
+				Name:        name,
+				Command:     "just",
+				Args:        []string{name},
+				Description: description,
+				Category:    CategoryJustfile,
+				Priority:    priority,
+			})
+		}
+		doc, private = "", false
+	}
+	return commands
+}
+
+// parseTaskfileTasks returns the tasks under "tasks:" in a Taskfile, with their desc
+// as the description. Internal tasks are left out. Only the layout Taskfiles use is
+// read: task names one level under tasks, their settings one level further in.
+func parseTaskfileTasks(path string) []ExecutableCommand {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	type taskEntry struct {
+		name     string
+		desc     string
+		internal bool
+	}
+	var tasks []*taskEntry
+	var current *taskEntry
+	inTasks := false
+	taskIndent := -1
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		if indent == 0 {
+			inTasks = trimmed == "tasks:"
+			current = nil
+			continue
+		}
+		if !inTasks {
+			continue
+		}
+		if taskIndent < 0 {
+			taskIndent = indent
+		}
+
+		key, value, ok := yamlKeyValue(trimmed)
+		switch {
+		case indent == taskIndent && ok:
+			current = &taskEntry{name: key}
+			tasks = append(tasks, current)
+			if value != "" && !strings.HasPrefix(value, "{") {
+				current.desc = value // the short form, name: command
+			}
+		case indent < taskIndent:
+			current = nil
+		case current != nil && ok && indent > taskIndent:
+			switch key {
+			case "desc":
+				current.desc = value
+			case "internal":
+				current.internal = value == "true"
+			}
+		}
+	}
+
+	var commands []ExecutableCommand
+	for _, task := range tasks {
+		if task.internal {
+			continue
+		}
+		description := task.desc
+		if description == "" {
+			description = "task " + task.name
+		}
+		priority := 78
+		if task.desc != "" {
+			priority = 80
+		}
+		commands = append(commands, ExecutableCommand{
+			Name:        task.name,
+			Command:     "task",
+			Args:        []string{task.name},
+			Description: description,
+			Category:    CategoryTaskfile,
+			Priority:    priority,
+		})
+	}
+	return commands
+}
+
+// yamlKeyValue splits a "key: value" line, unquoting both. Keys may contain colons,
+// as namespaced task names such as docker:build do.
+func yamlKeyValue(line string) (string, string, bool) {
+	var key, rest string
+	if line[0] == '"' || line[0] == '\'' {
+		end := strings.IndexByte(line[1:], line[0])
+		if end < 0 {
+			return "", "", false
+		}
+		key, rest = line[1:end+1], strings.TrimSpace(line[end+2:])
+		if !strings.HasPrefix(rest, ":") {
+			return "", "", false
+		}
+		rest = rest[1:]
+	} else {
+		idx := strings.Index(line, ": ")
+		if idx < 0 {
+			if !strings.HasSuffix(line, ":") {
+				return "", "", false
+			}
+			idx = len(line) - 1
+		}
+		key, rest = line[:idx], line[idx+1:]
+	}
+	if strings.HasPrefix(key, "- ") {
+		return "", "", false // a list item
+	}
+	value := strings.TrimSpace(rest)
+	if comment := strings.Index(value, " #"); comment >= 0 {
+		value = strings.TrimSpace(value[:comment])
+	}
+	return key, strings.Trim(value, `"'`), true
+}
@@ -0,0 +1,130 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+}
+
+// commandsByName indexes detected commands of one category
+func commandsByName(commands []ExecutableCommand, category string) map[string]ExecutableCommand {
+	byName := make(map[string]ExecutableCommand)
+	for _, cmd := range commands {
+		if cmd.Category == category {
+			byName[cmd.Name] = cmd
+		}
+	}
+	return byName
+}
+
+func TestMakefileTargets(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "Makefile", `CC := gcc
+VERSION = 1.0
+.PHONY: build test clean
+
+## Build the binary
+build: deps
+	$(CC) -o app main.c
+
+test: build ## Run the tests
+	./app --test
+
+clean:
+	rm -f app
+
+bin/app: main.c
+	$(CC) -o $@ $<
+
+%.o: %.c
+	$(CC) -c $<
+
+define HELP
+usage: make target
+endef
+`)
+
+	targets := commandsByName(DetectProjectCommands(dir), "Makefile")
+	assert.Len(t, targets, 3)
+	assert.Equal(t, "Build the binary", targets["build"].Description)
+	assert.Equal(t, "Run the tests", targets["test"].Description)
+	assert.Equal(t, "make clean", targets["clean"].Description)
+	assert.Equal(t, "make", targets["build"].Command)
+	assert.Equal(t, []string{"build"}, targets["build"].Args)
+	assert.NotContains(t, targets, "deps")
+	assert.NotContains(t, targets, "bin/app")
+}
+
+func TestJustfileRecipes(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "justfile", `set shell := ["bash", "-c"]
+version := "1.0"
+alias b := build
+
+# Build everything
+build:
+    cargo build
+
+[doc('Run the tests')]
+test filter="": build
+    cargo test {{filter}}
+
+[private]
+setup:
+    ./setup.sh
+
+_helper:
+    echo hidden
+
+@lint:
+    cargo clippy
+`)
+
+	recipes := commandsByName(DetectProjectCommands(dir), "justfile")
+	assert.Len(t, recipes, 3)
+	assert.Equal(t, "Build everything", recipes["build"].Description)
+	assert.Equal(t, `Run the tests (parameters: filter="")`, recipes["test"].Description)
+	assert.Equal(t, "just lint", recipes["lint"].Description)
+	assert.Equal(t, []string{"lint"}, recipes["lint"].Args)
+}
+
+func TestTaskfileTasks(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "Taskfile.yml", `version: '3'
+
+vars:
+  NAME: app
+
+tasks:
+  build:
+    desc: Build the app
+    cmds:
+      - go build -o {{.NAME}}
+  docker:build:
+    desc: "Build the image" # with a comment
+    cmds:
+      - task: build
+      - docker build .
+  generate:
+    internal: true
+    cmds:
+      - go generate ./...
+  'test':
+    cmds:
+      - go test ./...
+`)
+
+	tasks := commandsByName(DetectProjectCommands(dir), "Taskfile")
+	assert.Len(t, tasks, 3)
+	assert.Equal(t, "Build the app", tasks["build"].Description)
+	assert.Equal(t, "Build the image", tasks["docker:build"].Description)
+	assert.Equal(t, "task test", tasks["test"].Description)
+	assert.Equal(t, "task", tasks["test"].Command)
+}
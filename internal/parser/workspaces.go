@@ -0,0 +1,269 @@
+package parser
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Cargo and Go workspaces. Their members get commands that run from the workspace
+// root: cargo selects a crate with -p and go takes a package pattern.
+
+// cargoManifest is the part of Cargo.toml that describes a workspace and its crates
+type cargoManifest struct {
+	Package *struct {
+		Name string `toml:"name"`
+	} `toml:"package"`
+	Workspace *struct {
+		Members []string `toml:"members"`
+		Exclude []string `toml:"exclude"`
+	} `toml:"workspace"`
+	Bin []struct {
+		Name string `toml:"name"`
+	} `toml:"bin"`
+}
+
+// detectCargoWorkspace returns the crates of the [workspace] in Cargo.toml, or nil
+// when there is none. The root crate is included when the root is a package too.
+func detectCargoWorkspace(projectPath string) *MonorepoInfo {
+	var root cargoManifest
+	if _, err := toml.DecodeFile(filepath.Join(projectPath, "Cargo.toml"), &root); err != nil || root.Workspace == nil {
+		return nil
+	}
+
+	excluded := make(map[string]bool)
+	for _, pattern := range root.Workspace.Exclude {
+		matches, _ := filepath.Glob(filepath.Join(projectPath, pattern))
+		for _, match := range matches {
+			excluded[match] = true
+		}
+	}
+
+	dirs := []string{}
+	if root.Package != nil {
+		dirs = append(dirs, projectPath)
+	}
+	for _, pattern := range root.Workspace.Members {
+		matches, _ := filepath.Glob(filepath.Join(projectPath, pattern))
+		for _, match := range matches {
+			if !excluded[match] {
+				dirs = append(dirs, match)
+			}
+		}
+	}
+
+	var packages []PackageInfo
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		var manifest cargoManifest
+		if _, err := toml.DecodeFile(filepath.Join(dir, "Cargo.toml"), &manifest); err != nil || manifest.Package == nil {
+			continue
+		}
+		name := manifest.Package.Name
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		packages = append(packages, PackageInfo{
+			Path:        dir,
+			Name:        name,
+			HasLockFile: fileExistsIn(projectPath, "Cargo.lock"),
+			Commands:    crateCommands(dir, name, len(manifest.Bin) > 0),
+		})
+	}
+
+	return &MonorepoInfo{
+		Type:       "cargo",
+		Root:       projectPath,
+		Workspaces: root.Workspace.Members,
+		Packages:   packages,
+	}
+}
+
+// crateCommands builds, tests and, for crates with a binary, runs one crate
+func crateCommands(dir, name string, declaresBin bool) []ExecutableCommand {
+	commands := []ExecutableCommand{
+		{
+			Name:        "cargo build -p " + name,
+			Command:     "cargo",
+			Args:        []string{"build", "-p", name},
+			Description: "Build crate " + name,
+			Category:    "Cargo workspace",
+			ProjectType: ProjectTypeRust,
+			Priority:    88,
+		},
+		{
+			Name:        "cargo test -p " + name,
+			Command:     "cargo",
+			Args:        []string{"test", "-p", name},
+			Description: "Test crate " + name,
+			Category:    "Cargo workspace",
+			ProjectType: ProjectTypeRust,
+			Priority:    87,
+		},
+	}
+	if declaresBin || fileExistsIn(dir, filepath.Join("src", "main.rs")) || fileExistsIn(dir, filepath.Join("src", "bin")) {
+		commands = append([]ExecutableCommand{{
+			Name:        "cargo run -p " + name,
+			Command:     "cargo",
+			Args:        []string{"run", "-p", name},
+			Description: "Run crate " + name,
+			Category:    "Cargo workspace",
+			ProjectType: ProjectTypeRust,
+			Priority:    89,
+		}}, commands...)
+	}
+	return commands
+}
+
+// detectGoWorkspace returns the modules a go.work file uses, or nil when there is none
+func detectGoWorkspace(projectPath string) *MonorepoInfo {
+	uses, err := parseGoWork(filepath.Join(projectPath, "go.work"))
+	if err != nil {
+		return nil
+	}
+
+	var packages []PackageInfo
+	for _, use := range uses {
+		dir := use
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(projectPath, use)
+		}
+		module := goModulePath(filepath.Join(dir, "go.mod"))
+		if module == "" {
+			continue
+		}
+
+		// Patterns are relative to the workspace root, where the commands run
+		pattern := "."
+		if rel, err := filepath.Rel(projectPath, dir); err == nil && rel != "." {
+			pattern = "./" + filepath.ToSlash(rel)
+		}
+		packages = append(packages, PackageInfo{
+			Path:        dir,
+			Name:        module,
+			HasLockFile: fileExistsIn(dir, "go.sum"),
+			Commands:    goModuleCommands(dir, module, pattern),
+		})
+	}
+
+	return &MonorepoInfo{
+		Type:       "go-work",
+		Root:       projectPath,
+		Workspaces: uses,
+		Packages:   packages,
+	}
+}
+
+// goModuleCommands builds and tests one module of a workspace, and runs it when its
+// root is a main package
+func goModuleCommands(dir, module, pattern string) []ExecutableCommand {
+	all := strings.TrimSuffix(pattern, "/") + "/..."
+	commands := []ExecutableCommand{
+		{
+			Name:        "go build " + all,
+			Command:     "go",
+			Args:        []string{"build", all},
+			Description: "Build module " + module,
+			Category:    "Go workspace",
+			ProjectType: ProjectTypeGo,
+			Priority:    88,
+		},
+		{
+			Name:        "go test " + all,
+			Command:     "go",
+			Args:        []string{"test", all},
+			Description: "Test module " + module,
+			Category:    "Go workspace",
+			ProjectType: ProjectTypeGo,
+			Priority:    87,
+		},
+	}
+	if isGoMainPackage(dir) {
+		commands = append([]ExecutableCommand{{
+			Name:        "go run " + pattern,
+			Command:     "go",
+			Args:        []string{"run", pattern},
+			Description: "Run module " + module,
+			Category:    "Go workspace",
+			ProjectType: ProjectTypeGo,
+			Priority:    89,
+		}}, commands...)
+	}
+	return commands
+}
+
+// parseGoWork returns the directories of the use directives in a go.work file, in
+// both the single-line and the block form
+func parseGoWork(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var uses []string
+	inUse := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if comment := strings.Index(line, "//"); comment >= 0 {
+			line = line[:comment]
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case inUse && line == ")":
+			inUse = false
+		case inUse && line != "":
+			uses = append(uses, strings.Trim(line, "\"`"))
+		case line == "use (":
+			inUse = true
+		case strings.HasPrefix(line, "use "):
+			uses = append(uses, strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "use ")), "\"`"))
+		}
+	}
+	return uses, scanner.Err()
+}
+
+// goModulePath reads the module directive of a go.mod file, or returns ""
+func goModulePath(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if module, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(module), "\"`")
+		}
+	}
+	return ""
+}
+
+// isGoMainPackage reports whether the Go files in dir, tests aside, are package main
+func isGoMainPackage(dir string) bool {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if pkg, ok := strings.CutPrefix(strings.TrimSpace(line), "package "); ok {
+				return strings.TrimSpace(pkg) == "main"
+			}
+		}
+	}
+	return false
+}
+
+func fileExistsIn(dir, name string) bool {
+	_, err := os.Stat(filepath.Join(dir, name))
+	return err == nil
+}
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCargoAndGoWorkspaces(t *testing.T) {
+	dir := t.TempDir()
+	mkdir := func(path string) string {
+		full := filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(full, 0755))
+		return full
+	}
+
+	writeFile(t, dir, "Cargo.toml", `[workspace]
+members = ["crates/*"]
+exclude = ["crates/scratch"]
+`)
+	writeFile(t, mkdir("crates/api"), "Cargo.toml", "[package]\nname = \"shop-api\"\n")
+	writeFile(t, mkdir("crates/api/src"), "main.rs", "fn main() {}\n")
+	writeFile(t, mkdir("crates/core"), "Cargo.toml", "[package]\nname = \"shop-core\"\n")
+	writeFile(t, mkdir("crates/scratch"), "Cargo.toml", "[package]\nname = \"scratch\"\n")
+
+	writeFile(t, dir, "go.work", `go 1.22
+
+use ./services/gateway // the public API
+use (
+	./tools/migrate
+	"./missing"
+)
+`)
+	writeFile(t, mkdir("services/gateway"), "go.mod", "module example.com/gateway\n\ngo 1.22\n")
+	writeFile(t, dir, "services/gateway/main.go", "package main\n\nfunc main() {}\n")
+	writeFile(t, mkdir("tools/migrate"), "go.mod", "module example.com/migrate\n")
+	writeFile(t, dir, "tools/migrate/migrate.go", "// Package migrate moves data\npackage migrate\n")
+
+	info, err := DetectMonorepo(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "cargo+go-work", info.Type)
+
+	packages := make(map[string]PackageInfo)
+	for _, pkg := range info.Packages {
+		packages[pkg.Name] = pkg
+	}
+	assert.Len(t, packages, 4)
+	assert.NotContains(t, packages, "scratch")
+
+	var crates []string
+	for _, cmd := range packages["shop-api"].Commands {
+		crates = append(crates, cmd.Name)
+	}
+	assert.Equal(t, []string{"cargo run -p shop-api", "cargo build -p shop-api", "cargo test -p shop-api"}, crates)
+	assert.Len(t, packages["shop-core"].Commands, 2, "a library crate has nothing to run")
+
+	gateway := commandsByName(packages["example.com/gateway"].Commands, "Go workspace")
+	assert.Contains(t, gateway, "go run ./services/gateway")
+	assert.Equal(t, []string{"test", "./services/gateway/..."}, gateway["go test ./services/gateway/..."].Args)
+	migrate := commandsByName(packages["example.com/migrate"].Commands, "Go workspace")
+	assert.Len(t, migrate, 2)
+	assert.Contains(t, migrate, "go build ./tools/migrate/...")
+}
@@ -0,0 +1,139 @@
+package process
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/standardbeagle/brummer/internal/config"
+)
+
+// DependencyGraph maps each script to the scripts it depends on
+type DependencyGraph struct {
+	deps map[string][]string
+}
+
+// NewDependencyGraph builds a graph from the depends_on settings in the config
+func NewDependencyGraph(cfg *config.Config) *DependencyGraph {
+	g := &DependencyGraph{deps: make(map[string][]string)}
+	if cfg == nil {
+		return g
+	}
+	for name, sc := range cfg.Scripts {
+		if deps := sc.GetDependsOn(); len(deps) > 0 {
+			g.deps[name] = deps
+		}
+	}
+	return g
+}
+
+// DependsOn returns the direct dependencies of a script
+func (g *DependencyGraph) DependsOn(name string) []string {
+	return g.deps[name]
+}
+
+// StartOrder returns the scripts that must be started for target, dependencies first
+// and target last. It returns an error naming the cycle if one is reachable from target.
+func (g *DependencyGraph) StartOrder(target string) ([]string, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int)
+	var order []string
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			// Report the cycle starting from the first occurrence of name on the path
+			start := 0
+			for i, p := range path {
+				if p == name {
+					start = i
+					break
+				}
+			}
+			cycle := append(append([]string{}, path[start:]...), name)
+			return fmt.Errorf("circular dependency: %s", strings.Join(cycle, " -> "))
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range g.deps[name] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		order = append(order, name)
+		return nil
+	}
+
+	if err := visit(target); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// Validate checks the whole graph for cycles
+func (g *DependencyGraph) Validate() error {
+	names := make([]string, 0, len(g.deps))
+	for name := range g.deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := g.StartOrder(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Dependents returns every script that transitively depends on name, ordered for
+// teardown: scripts furthest from name come first.
+func (g *DependencyGraph) Dependents(name string) []string {
+	// Reverse the edges once
+	reverse := make(map[string][]string)
+	for script, deps := range g.deps {
+		for _, dep := range deps {
+			reverse[dep] = append(reverse[dep], script)
+		}
+	}
+
+	// Depth is the longest path from name, so a dependent is always torn down
+	// before anything it depends on
+	depth := make(map[string]int)
+	var walk func(script string, d int)
+	walk = func(script string, d int) {
+		for _, dependent := range reverse[script] {
+			if d+1 > depth[dependent] && d < len(g.deps) {
+				depth[dependent] = d + 1
+				walk(dependent, d+1)
+			}
+		}
+	}
+	walk(name, 0)
+
+	dependents := make([]string, 0, len(depth))
+	for script := range depth {
+		if script != name {
+			dependents = append(dependents, script)
+		}
+	}
+	sort.Slice(dependents, func(i, j int) bool {
+		if depth[dependents[i]] != depth[dependents[j]] {
+			return depth[dependents[i]] > depth[dependents[j]]
+		}
+		return dependents[i] < dependents[j]
+	})
+	return dependents
+}
@@ -0,0 +1,160 @@
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/standardbeagle/brummer/internal/config"
+	"github.com/standardbeagle/brummer/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dependencyConfig(deps map[string][]string) *config.Config {
+	cfg := &config.Config{Scripts: make(map[string]*config.ScriptConfig)}
+	for name, d := range deps {
+		cfg.Scripts[name] = &config.ScriptConfig{DependsOn: d}
+	}
+	return cfg
+}
+
+// TestDependencyStartOrder tests topological ordering of dependencies
+func TestDependencyStartOrder(t *testing.T) {
+	g := NewDependencyGraph(dependencyConfig(map[string][]string{
+		"web": {"api"},
+		"api": {"db:migrate"},
+	}))
+
+	order, err := g.StartOrder("web")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db:migrate", "api", "web"}, order)
+
+	order, err = g.StartOrder("db:migrate")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db:migrate"}, order)
+}
+
+// TestDependencyCycleDetection tests that cycles are reported with their path
+func TestDependencyCycleDetection(t *testing.T) {
+	g := NewDependencyGraph(dependencyConfig(map[string][]string{
+		"web":    {"api"},
+		"api":    {"worker"},
+		"worker": {"api"},
+	}))
+
+	_, err := g.StartOrder("web")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "api -> worker -> api")
+	assert.Error(t, g.Validate())
+}
+
+// TestDependentsTeardownOrder tests that dependents are ordered furthest-first
+func TestDependentsTeardownOrder(t *testing.T) {
+	g := NewDependencyGraph(dependencyConfig(map[string][]string{
+		"web":    {"api"},
+		"admin":  {"api", "db"},
+		"api":    {"db"},
+		"worker": {"db"},
+	}))
+
+	dependents := g.Dependents("db")
+	require.Len(t, dependents, 4)
+	// web and admin sit two levels above db and must be stopped before api
+	assert.Equal(t, []string{"admin", "web", "api", "worker"}, dependents)
+	assert.Empty(t, g.Dependents("web"))
+}
+
+// TestStartScriptWaitsForDependencies tests that a dependency's log readiness gates the dependent
+func TestStartScriptWaitsForDependencies(t *testing.T) {
+	tempDir := t.TempDir()
+	packageJSON := map[string]interface{}{
+		"name": "deps-project",
+		"scripts": map[string]interface{}{
+			"db":  "echo booting && sleep 0.2 && echo database ready && sleep 5",
+			"api": "echo api up",
+		},
+	}
+	data, err := json.Marshal(packageJSON)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "package.json"), data, 0644))
+
+	mgr, err := NewManager(tempDir, events.NewEventBus(), true)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	pattern := "database ready"
+	mgr.config = &config.Config{
+		Scripts: map[string]*config.ScriptConfig{
+			"api": {DependsOn: []string{"db"}},
+			"db":  {Ready: &config.ReadinessConfig{LogPattern: &pattern}},
+		},
+	}
+
+	start := time.Now()
+	api, err := mgr.StartScript("api")
+	require.NoError(t, err)
+	assert.Equal(t, "api", api.Name)
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+
+	db := mgr.findProcessByName("db")
+	require.NotNil(t, db)
+	assert.True(t, db.IsReady())
+	assert.Equal(t, StatusRunning, db.GetStatus())
+}
+
+// TestConcurrentStartsShareDependency tests that scripts started at once with a
+// common dependency start it only once
+func TestConcurrentStartsShareDependency(t *testing.T) {
+	tempDir := t.TempDir()
+	scripts := map[string]interface{}{
+		"db": "echo booting && sleep 0.2 && echo database ready && sleep 5",
+	}
+	pattern := "database ready"
+	cfg := &config.Config{
+		Scripts: map[string]*config.ScriptConfig{
+			"db": {Ready: &config.ReadinessConfig{LogPattern: &pattern}},
+		},
+	}
+	var dependents []string
+	for i := 0; i < 6; i++ {
+		name := fmt.Sprintf("api-%d", i)
+		dependents = append(dependents, name)
+		scripts[name] = "echo up"
+		cfg.Scripts[name] = &config.ScriptConfig{DependsOn: []string{"db"}}
+	}
+	data, err := json.Marshal(map[string]interface{}{"name": "deps-project", "scripts": scripts})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "package.json"), data, 0644))
+
+	eventBus := events.NewEventBus()
+	mgr, err := NewManager(tempDir, eventBus, true)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+	mgr.config = cfg
+
+	var started atomic.Int32
+	eventBus.Subscribe(events.ProcessStarted, func(e events.Event) {
+		if e.Data["name"] == "db" {
+			started.Add(1)
+		}
+	})
+
+	errs := make(chan error, len(dependents))
+	for _, name := range dependents {
+		go func(name string) {
+			_, err := mgr.StartScript(name)
+			errs <- err
+		}(name)
+	}
+	for range dependents {
+		require.NoError(t, <-errs)
+	}
+
+	time.Sleep(200 * time.Millisecond) // events are delivered asynchronously
+	assert.Equal(t, int32(1), started.Load())
+}
@@ -0,0 +1,69 @@
+package process
+
+import (
+	"fmt"
+
+	"github.com/standardbeagle/brummer/internal/env"
+)
+
+// SetEnvProfile selects the .env.<profile> layer used for processes started from now on
+func (m *Manager) SetEnvProfile(profile string) {
+	m.envMu.Lock()
+	defer m.envMu.Unlock()
+	m.envProfile = profile
+}
+
+// GetEnvProfile returns the active environment profile, or "" if none is selected
+func (m *Manager) GetEnvProfile() string {
+	m.envMu.RLock()
+	defer m.envMu.RUnlock()
+	return m.envProfile
+}
+
+// ResolveEnvironment builds the environment a process with the given name would receive:
+// the system environment, the project's .env files for the active profile, the env of
+// a service with that name, and any [scripts.<name>.env] overrides
+func (m *Manager) ResolveEnvironment(name string) (*env.Environment, error) {
+	overrides := m.config.GetScriptConfig(name).GetEnv()
+	if svc, ok := m.GetService(name); ok && len(svc.Env) > 0 {
+		merged := make(map[string]string, len(svc.Env)+len(overrides))
+		for key, value := range svc.Env {
+			merged[key] = value
+		}
+		for key, value := range overrides {
+			merged[key] = value
+		}
+		overrides = merged
+	}
+
+	return env.Load(env.Options{
+		Dir:            m.workDir,
+		Profile:        m.GetEnvProfile(),
+		Overrides:      overrides,
+		SecretKeys:     m.config.GetSecretKeys(),
+		OverrideSystem: m.config.GetEnvOverride(),
+	})
+}
+
+// buildProcessEnv returns the exec environment for a process, with color output forced
+// and the assigned port, if the process gets one
+func (m *Manager) buildProcessEnv(name string) ([]string, error) {
+	resolved, err := m.ResolveEnvironment(name)
+	if err != nil {
+		return nil, err
+	}
+
+	environ := resolved.Environ()
+	if m.wantsPort(name) {
+		port, err := m.assignPort(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to assign a port: %w", err)
+		}
+		environ = append(environ, fmt.Sprintf("%s=%d", m.config.GetScriptConfig(name).GetPortEnv(), port))
+	}
+	// Force color output for common tools
+	environ = append(environ, "FORCE_COLOR=1")
+	environ = append(environ, "COLORTERM=truecolor")
+	environ = append(environ, "TERM=xterm-256color")
+	return environ, nil
+}
@@ -0,0 +1,218 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/standardbeagle/brummer/internal/config"
+	"github.com/standardbeagle/brummer/pkg/events"
+)
+
+// HealthStatus is the liveness state reported by a process's health probes
+type HealthStatus string
+
+const (
+	HealthUnknown   HealthStatus = "unknown"
+	HealthHealthy   HealthStatus = "healthy"
+	HealthUnhealthy HealthStatus = "unhealthy"
+)
+
+// GetHealth returns the liveness state of the process
+func (p *Process) GetHealth() HealthStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.healthLocked()
+}
+
+// probeTracker turns individual probe results into state transitions. A failing
+// state is only entered after threshold consecutive failures; one success leaves it.
+type probeTracker struct {
+	threshold int
+	failures  int
+	passing   *bool
+}
+
+// record adds a probe result and reports whether the passing state changed
+func (t *probeTracker) record(ok bool) (passing bool, changed bool) {
+	if ok {
+		t.failures = 0
+	} else {
+		t.failures++
+	}
+
+	switch {
+	case ok && (t.passing == nil || !*t.passing):
+		passing, changed = true, true
+	case !ok && t.failures >= t.threshold && (t.passing == nil || *t.passing):
+		passing, changed = false, true
+	default:
+		if t.passing != nil {
+			passing = *t.passing
+		}
+		return passing, false
+	}
+	t.passing = &passing
+	return passing, changed
+}
+
+// runProbe executes a single probe and returns nil when it passes
+func runProbe(probe *config.ProbeConfig, workDir string) error {
+	timeout := probe.GetTimeout()
+
+	if url := probe.GetHTTP(); url != "" {
+		client := &http.Client{Timeout: timeout}
+		resp, err := client.Get(url)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+			return fmt.Errorf("GET %s returned %d", url, resp.StatusCode)
+		}
+		return nil
+	}
+
+	if port := probe.GetTCP(); port > 0 {
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort("localhost", strconv.Itoa(port)), timeout)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	if command := probe.GetExec(); command != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		var cmd *exec.Cmd
+		if runtime.GOOS == "windows" {
+			cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+		} else {
+			cmd = exec.CommandContext(ctx, "sh", "-c", command)
+		}
+		cmd.Dir = workDir
+		return cmd.Run()
+	}
+
+	return fmt.Errorf("probe has no http, tcp or exec check")
+}
+
+// startHealthChecks launches the configured probes for a running process
+func (m *Manager) startHealthChecks(p *Process, hc *config.HealthConfig) {
+	if liveness := hc.GetLiveness(); liveness != nil {
+		go m.runProbeLoop(p, liveness, func(passing bool, err error) {
+			m.setHealth(p, passing, err, hc.GetRestartOnUnhealthy())
+		})
+	}
+	if readiness := hc.GetReadiness(); readiness != nil {
+		go m.runProbeLoop(p, readiness, func(passing bool, err error) {
+			m.setProbeReadiness(p, passing, err)
+		})
+	}
+}
+
+// runProbeLoop runs a probe on its interval until the process exits
+func (m *Manager) runProbeLoop(p *Process, probe *config.ProbeConfig, onChange func(passing bool, err error)) {
+	tracker := &probeTracker{threshold: probe.GetFailureThreshold()}
+
+	select {
+	case <-p.done:
+		return
+	case <-time.After(probe.GetInitialDelay()):
+	}
+
+	ticker := time.NewTicker(probe.GetInterval())
+	defer ticker.Stop()
+
+	for {
+		err := runProbe(probe, m.workDir)
+		if passing, changed := tracker.record(err == nil); changed {
+			onChange(passing, err)
+		}
+
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// setHealth records a liveness transition, announces it, and optionally restarts the process
+func (m *Manager) setHealth(p *Process, passing bool, probeErr error, restart bool) {
+	status := HealthUnhealthy
+	eventType := events.ProcessUnhealthy
+	if passing {
+		status = HealthHealthy
+		eventType = events.ProcessHealthy
+	}
+
+	p.mu.Lock()
+	p.health = status
+	p.mu.Unlock()
+
+	data := map[string]interface{}{
+		"name":   p.Name,
+		"health": string(status),
+	}
+	if probeErr != nil {
+		data["error"] = probeErr.Error()
+	}
+	m.eventBus.Publish(events.Event{
+		Type:      eventType,
+		ProcessID: p.ID,
+		Data:      data,
+	})
+
+	if passing {
+		return
+	}
+
+	m.emitSystemLog(p.Name, fmt.Sprintf("💔 Process '%s' is unhealthy: %v", p.Name, probeErr), true)
+	if restart && p.GetStatus() == StatusRunning {
+		go m.restartUnhealthy(p)
+	}
+}
+
+// setProbeReadiness records a readiness probe transition
+func (m *Manager) setProbeReadiness(p *Process, passing bool, probeErr error) {
+	p.mu.Lock()
+	p.probeNotReady = !passing
+	p.mu.Unlock()
+
+	if passing {
+		// Unlike startup readiness, probe readiness can be regained after a failure
+		p.markReady()
+		m.publishReady(p, "readiness probe")
+		return
+	}
+
+	m.eventBus.Publish(events.Event{
+		Type:      events.ProcessNotReady,
+		ProcessID: p.ID,
+		Data: map[string]interface{}{
+			"name":  p.Name,
+			"error": probeErr.Error(),
+		},
+	})
+}
+
+// restartUnhealthy replaces an unhealthy process with a fresh instance
+func (m *Manager) restartUnhealthy(p *Process) {
+	m.emitSystemLog(p.Name, fmt.Sprintf("🔁 Restarting unhealthy process '%s'", p.Name), false)
+
+	if err := m.StopProcessAndWait(p.ID, 5*time.Second); err != nil {
+		m.emitSystemLog(p.Name, fmt.Sprintf("❌ Failed to stop unhealthy process '%s': %v", p.Name, err), true)
+		return
+	}
+
+	if _, err := m.relaunch(p.spec); err != nil {
+		m.emitSystemLog(p.Name, fmt.Sprintf("❌ Failed to restart unhealthy process '%s': %v", p.Name, err), true)
+	}
+}
@@ -0,0 +1,67 @@
+package process
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/standardbeagle/brummer/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProbeTrackerThreshold tests that failures only flip state after the threshold
+func TestProbeTrackerThreshold(t *testing.T) {
+	tracker := &probeTracker{threshold: 3}
+
+	passing, changed := tracker.record(true)
+	assert.True(t, passing)
+	assert.True(t, changed)
+
+	_, changed = tracker.record(false)
+	assert.False(t, changed)
+	_, changed = tracker.record(false)
+	assert.False(t, changed)
+
+	passing, changed = tracker.record(false)
+	assert.False(t, passing)
+	assert.True(t, changed)
+
+	// Staying unhealthy is not a change
+	_, changed = tracker.record(false)
+	assert.False(t, changed)
+
+	passing, changed = tracker.record(true)
+	assert.True(t, passing)
+	assert.True(t, changed)
+}
+
+// TestRunProbe tests each probe kind against passing and failing targets
+func TestRunProbe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	healthURL := server.URL + "/health"
+	brokenURL := server.URL + "/broken"
+	assert.NoError(t, runProbe(&config.ProbeConfig{HTTP: &healthURL}, t.TempDir()))
+	assert.Error(t, runProbe(&config.ProbeConfig{HTTP: &brokenURL}, t.TempDir()))
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	assert.NoError(t, runProbe(&config.ProbeConfig{TCP: &port}, t.TempDir()))
+	listener.Close()
+	assert.Error(t, runProbe(&config.ProbeConfig{TCP: &port}, t.TempDir()))
+
+	assert.NoError(t, runProbe(&config.ProbeConfig{Exec: stringPtr("true")}, t.TempDir()))
+	assert.Error(t, runProbe(&config.ProbeConfig{Exec: stringPtr("exit 1")}, t.TempDir()))
+
+	assert.Error(t, runProbe(&config.ProbeConfig{}, t.TempDir()))
+}
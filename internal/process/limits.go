@@ -0,0 +1,60 @@
+package process
+
+import (
+	"fmt"
+
+	"github.com/standardbeagle/brummer/internal/cgroup"
+)
+
+// ExitReason explains why a process exited when its exit code alone does not
+type ExitReason string
+
+const (
+	ExitReasonNone ExitReason = ""
+	ExitReasonOOM  ExitReason = "oom_killed" // killed for exceeding its memory limit
+)
+
+// ExitReason returns why the process exited, if something other than the process decided it
+func (p *Process) ExitReason() ExitReason {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.exitReason
+}
+
+// startLimited starts a process through start, confined to the script's configured
+// resource limits. Where the kernel allows it the process is created inside its
+// cgroup, so children it forks right away (npm run → node) are confined too;
+// elsewhere it is moved in as soon as it has started.
+func (m *Manager) startLimited(p *Process, limits cgroup.Limits, start func() error) error {
+	group, warning, err := cgroup.Start(p.Name, p.Cmd, limits, start)
+	if err != nil {
+		return err
+	}
+	if warning != nil {
+		// Processes are started with the manager lock held, which logging needs
+		go m.emitSystemLog(p.Name, fmt.Sprintf("⚠️ Resource limits for '%s': %v", p.Name, warning), true)
+	}
+
+	p.mu.Lock()
+	p.limits = limits
+	p.cgroup = group
+	p.mu.Unlock()
+	return nil
+}
+
+// releaseLimits removes the process's cgroup once it has exited and reports whether
+// the kernel killed it for running out of memory
+func (m *Manager) releaseLimits(p *Process) bool {
+	p.mu.RLock()
+	group := p.cgroup
+	p.mu.RUnlock()
+	if group == nil {
+		return false
+	}
+
+	oom := group.OOMKilled()
+	if err := group.Close(); err != nil {
+		m.emitSystemLog(p.Name, fmt.Sprintf("⚠️ %v", err), true)
+	}
+	return oom
+}
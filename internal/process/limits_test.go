@@ -0,0 +1,60 @@
+//go:build linux
+
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standardbeagle/brummer/internal/config"
+	"github.com/standardbeagle/brummer/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestResourceLimitsApplied tests that a script's memory limit reaches the kernel,
+// through a cgroup when one can be created and through RLIMIT_DATA otherwise
+func TestResourceLimitsApplied(t *testing.T) {
+	mgr, err := NewManager(t.TempDir(), events.NewEventBus(), false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	mgr.config = &config.Config{
+		Scripts: map[string]*config.ScriptConfig{
+			"limited": {
+				Limits: &config.ProcessLimits{MaxMemoryMB: intPtr(64)},
+			},
+		},
+	}
+
+	proc, err := mgr.StartCommand("limited", "sleep", []string{"5"})
+	require.NoError(t, err)
+
+	limit := strconv.Itoa(64 * 1024 * 1024)
+	proc.mu.RLock()
+	group := proc.cgroup
+	proc.mu.RUnlock()
+
+	if group != nil {
+		data, err := os.ReadFile(filepath.Join(group.Path(), "memory.max"))
+		require.NoError(t, err)
+		assert.Equal(t, limit, strings.TrimSpace(string(data)))
+	} else {
+		data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(proc.Cmd.Process.Pid), "limits"))
+		require.NoError(t, err)
+		var dataLimit string
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.HasPrefix(line, "Max data size") {
+				dataLimit = line
+			}
+		}
+		assert.Contains(t, dataLimit, limit)
+	}
+
+	require.NoError(t, mgr.StopProcessAndWait(proc.ID, 5*time.Second))
+	assert.Equal(t, ExitReasonNone, proc.ExitReason())
+}
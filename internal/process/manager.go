@@ -1,12 +1,12 @@
 package process
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
@@ -16,8 +16,11 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/hinshun/vt10x"
 	"github.com/standardbeagle/brummer/internal/aicoder"
+	"github.com/standardbeagle/brummer/internal/cgroup"
 	"github.com/standardbeagle/brummer/internal/config"
+	"github.com/standardbeagle/brummer/internal/discovery"
 	"github.com/standardbeagle/brummer/internal/parser"
 	"github.com/standardbeagle/brummer/pkg/events"
 )
@@ -34,6 +37,46 @@ type Process struct {
 	cancel    context.CancelFunc
 	mu        sync.RWMutex
 
+	// How the process was launched, used by the restart supervisor
+	spec          startSpec
+	stopRequested bool
+
+	// Readiness tracking for dependents
+	readyCh      chan struct{} // closed once the readiness check passes
+	done         chan struct{} // closed once the process has exited
+	readyPattern *regexp.Regexp
+	readyOnExit  bool
+
+	// Health probe state
+	health        HealthStatus
+	probeNotReady bool
+
+	// Pseudo-terminal state, set when the script runs with pty = true
+	pty      *os.File
+	terminal vt10x.Terminal
+	ptyDone  chan struct{} // closed once all PTY output has been read
+
+	// Input forwarding
+	interactive   bool        // stdin is a terminal or writable pipe rather than /dev/null
+	inputCh       chan []byte // queued writes to stdin; nil once closed
+	awaitingInput string      // prompt the process is waiting on, if any
+
+	// Resource samples of the process tree
+	metrics *metricsRing
+
+	// Resource limits and the cgroup enforcing them
+	limits     cgroup.Limits
+	cgroup     *cgroup.Group
+	exitReason ExitReason
+
+	// Output streams and the kernel start time, kept for session persistence
+	output    *outputPipes
+	procStart uint64
+
+	// Port handed to the process through its environment, 0 if none
+	assignedPort int
+	portConflict int // port the process reported as taken
+
 	// Atomic state for lock-free reads (30-300x faster than mutex)
 	atomicState unsafe.Pointer // *ProcessState
 }
@@ -97,7 +140,23 @@ func (p *Process) GetSnapshot() ProcessSnapshot {
 		StartTime: p.StartTime,
 		EndTime:   p.EndTime,
 		ExitCode:  p.ExitCode,
+		Health:    p.healthLocked(),
+	}
+}
+
+// healthLocked returns the health status; the caller must hold p.mu
+func (p *Process) healthLocked() HealthStatus {
+	if p.health == "" {
+		return HealthUnknown
 	}
+	return p.health
+}
+
+// isStopRequested reports whether the process was stopped deliberately
+func (p *Process) isStopRequested() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.stopRequested
 }
 
 // Thread-safe setters for Process fields
@@ -197,6 +256,7 @@ type ProcessSnapshot struct {
 	StartTime time.Time
 	EndTime   *time.Time
 	ExitCode  *int
+	Health    HealthStatus
 }
 
 // String implements fmt.Stringer for ProcessSnapshot
@@ -231,8 +291,39 @@ type Manager struct {
 	eventBus       *events.EventBus
 	logCallbacks   []LogCallback
 	installedMgrs  []parser.InstalledPackageManager
+	config         *config.Config
+	procfile       []parser.ProcfileEntry
 	mu             sync.RWMutex // Still needed for logCallbacks and other fields
 
+	// Environment profile selecting .env.<profile>
+	envProfile string
+	envMu      sync.RWMutex
+
+	// Restart supervision, keyed by script name
+	restarts  map[string]*restartTracker
+	restartMu sync.Mutex
+
+	// Held while a dependency is checked and started, keyed by script name, so that
+	// starts sharing a dependency start it once
+	dependencyLocks sync.Map // map[string]*sync.Mutex
+
+	// File watchers restarting scripts on change, keyed by script name
+	watchers map[string]*fileWatcher
+	watchMu  sync.Mutex
+
+	// Recurring commands from [schedules], keyed by schedule name
+	schedules  map[string]*scheduledTask
+	scheduleMu sync.Mutex
+
+	// Ports handed to processes, reserved across instances
+	ports portAllocator
+
+	// Persisted process state, set by OpenSession before any process starts
+	session *session
+
+	// Processes signalled or killed, with the reason
+	killLog terminationLog
+
 	// AI Coder integration
 	aiCoderMgr         *aicoder.AICoderManager
 	aiCoderIntegration *AICoderIntegration
@@ -256,6 +347,14 @@ func NewManager(workDir string, eventBus *events.EventBus, hasPackageJSON bool)
 		}
 	}
 
+	// Procfile entries become services alongside those in .brum.toml. A Procfile
+	// that cannot be parsed only costs its entries, not the whole manager.
+	procfile, err := loadProcfile(workDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Ignoring Procfile: %v\n", err)
+		procfile = nil
+	}
+
 	// Load config
 	cfg, _ := config.Load()
 
@@ -265,10 +364,16 @@ func NewManager(workDir string, eventBus *events.EventBus, hasPackageJSON bool)
 	m := &Manager{
 		// processes is sync.Map - zero value is ready to use
 		packageJSON:    pkgJSON,
+		procfile:       procfile,
 		workDir:        workDir,
 		eventBus:       eventBus,
 		installedMgrs:  installedMgrs,
 		userPackageMgr: cfg.PreferredPackageManager,
+		config:         cfg,
+		restarts:       make(map[string]*restartTracker),
+		watchers:       make(map[string]*fileWatcher),
+		envProfile:     cfg.GetEnvProfile(),
+		ports:          portAllocator{dir: discovery.GetPortsDir()},
 	}
 
 	// Set initial package manager based on detection
@@ -277,8 +382,22 @@ func NewManager(workDir string, eventBus *events.EventBus, hasPackageJSON bool)
 	return m, nil
 }
 
+// GetScripts returns everything that can be started by name: the package.json scripts
+// and the declared services, which replace scripts with the same name
 func (m *Manager) GetScripts() map[string]string {
-	return m.packageJSON.Scripts
+	services := m.Services()
+	if len(services) == 0 {
+		return m.packageJSON.Scripts
+	}
+
+	scripts := make(map[string]string, len(m.packageJSON.Scripts)+len(services))
+	for name, script := range m.packageJSON.Scripts {
+		scripts[name] = script
+	}
+	for _, svc := range services {
+		scripts[svc.Name] = svc.CommandLine()
+	}
+	return scripts
 }
 
 // GetDetectedCommands returns all detected executable commands
@@ -286,12 +405,57 @@ func (m *Manager) GetDetectedCommands() []parser.ExecutableCommand {
 	return parser.DetectProjectCommands(m.workDir)
 }
 
+// ProjectTasks returns the detected task runner targets that can be started by name.
+// Scripts and services keep their names, and the first task detected with a name wins.
+func (m *Manager) ProjectTasks() []parser.ExecutableCommand {
+	scripts := m.GetScripts()
+	seen := make(map[string]bool)
+	var tasks []parser.ExecutableCommand
+	for _, cmd := range m.GetDetectedCommands() {
+		if !cmd.IsProjectTask() || seen[cmd.Name] {
+			continue
+		}
+		if _, isScript := scripts[cmd.Name]; isScript {
+			continue
+		}
+		seen[cmd.Name] = true
+		tasks = append(tasks, cmd)
+	}
+	return tasks
+}
+
+// StartScriptOrTask starts the script or service with that name, or else the project
+// task with that name through its runner
+func (m *Manager) StartScriptOrTask(name string) (*Process, error) {
+	if _, isScript := m.GetScripts()[name]; !isScript {
+		for _, task := range m.ProjectTasks() {
+			if task.Name == name {
+				return m.StartCommand(task.Name, task.Command, task.Args)
+			}
+		}
+	}
+	return m.StartScript(name)
+}
+
 // GetMonorepoInfo returns monorepo information if detected
 func (m *Manager) GetMonorepoInfo() (*parser.MonorepoInfo, error) {
 	return parser.DetectMonorepo(m.workDir)
 }
 
 func (m *Manager) StartScript(scriptName string) (*Process, error) {
+	// A manual start resets any restart backoff or crash loop
+	m.clearRestartState(scriptName)
+	if err := m.startDependencies(scriptName); err != nil {
+		return nil, err
+	}
+	return m.startScript(scriptName)
+}
+
+func (m *Manager) startScript(scriptName string) (*Process, error) {
+	if svc, ok := m.GetService(scriptName); ok {
+		return m.startService(svc)
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -307,12 +471,13 @@ func (m *Manager) StartScript(scriptName string) (*Process, error) {
 	cmd := exec.CommandContext(ctx, cmdArgs[0], cmdArgs[1:]...)
 	cmd.Dir = m.workDir
 
-	// Set up environment
-	cmd.Env = os.Environ()
-	// Force color output for common tools
-	cmd.Env = append(cmd.Env, "FORCE_COLOR=1")
-	cmd.Env = append(cmd.Env, "COLORTERM=truecolor")
-	cmd.Env = append(cmd.Env, "TERM=xterm-256color")
+	// Set up environment from the system, .env files and script overrides
+	environ, err := m.buildProcessEnv(scriptName)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to load environment for '%s': %w", scriptName, err)
+	}
+	cmd.Env = environ
 
 	// Set process group for easier cleanup (platform-specific)
 	setupProcessGroup(cmd)
@@ -325,6 +490,7 @@ func (m *Manager) StartScript(scriptName string) (*Process, error) {
 		Status:    StatusPending,
 		StartTime: time.Now(),
 		cancel:    cancel,
+		spec:      startSpec{name: scriptName, fromScript: true},
 	}
 
 	m.processes.Store(processID, process)
@@ -339,6 +505,19 @@ func (m *Manager) StartScript(scriptName string) (*Process, error) {
 
 // StartCommand starts a custom command (not from package.json)
 func (m *Manager) StartCommand(name string, command string, args []string) (*Process, error) {
+	m.clearRestartState(name)
+	if err := m.startDependencies(name); err != nil {
+		return nil, err
+	}
+	return m.startCommand(name, command, args)
+}
+
+func (m *Manager) startCommand(name string, command string, args []string) (*Process, error) {
+	return m.startCommandIn(name, "", command, args)
+}
+
+// startCommandIn starts a custom command in dir, or in the project directory when dir is empty
+func (m *Manager) startCommandIn(name, dir, command string, args []string) (*Process, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -347,13 +526,17 @@ func (m *Manager) StartCommand(name string, command string, args []string) (*Pro
 	ctx, cancel := context.WithCancel(context.Background())
 	cmd := exec.CommandContext(ctx, command, args...)
 	cmd.Dir = m.workDir
+	if dir != "" {
+		cmd.Dir = dir
+	}
 
-	// Set up environment
-	cmd.Env = os.Environ()
-	// Force color output for common tools
-	cmd.Env = append(cmd.Env, "FORCE_COLOR=1")
-	cmd.Env = append(cmd.Env, "COLORTERM=truecolor")
-	cmd.Env = append(cmd.Env, "TERM=xterm-256color")
+	// Set up environment from the system, .env files and script overrides
+	environ, err := m.buildProcessEnv(name)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to load environment for '%s': %w", name, err)
+	}
+	cmd.Env = environ
 
 	// Set process group for easier cleanup (platform-specific)
 	setupProcessGroup(cmd)
@@ -366,6 +549,7 @@ func (m *Manager) StartCommand(name string, command string, args []string) (*Pro
 		Status:    StatusPending,
 		StartTime: time.Now(),
 		cancel:    cancel,
+		spec:      startSpec{name: name, command: command, args: args, dir: dir},
 	}
 
 	m.processes.Store(processID, process)
@@ -379,40 +563,102 @@ func (m *Manager) StartCommand(name string, command string, args []string) (*Pro
 }
 
 func (m *Manager) runProcess(p *Process) error {
-	stdout, err := p.Cmd.StdoutPipe()
-	if err != nil {
+	scriptCfg := m.config.GetScriptConfig(p.Name)
+	readiness := scriptCfg.GetReadiness()
+	health := scriptCfg.GetHealth()
+	if err := m.prepareReadiness(p, readiness); err != nil {
 		return err
 	}
 
-	stderr, err := p.Cmd.StderrPipe()
-	if err != nil {
-		return err
-	}
+	// Scripts that need a TTY get a pseudo-terminal; everything else uses pipes
+	usePTY := scriptCfg.GetPTY() && ptySupported
+	var output *outputPipes
+	var stdin io.WriteCloser
+	var inputCh chan []byte
+	limits := scriptCfg.GetLimits()
+	if usePTY {
+		setupPTYSession(p.Cmd) // before the cgroup is attached to SysProcAttr
+		if err := m.startLimited(p, limits, func() error { return m.startPTY(p) }); err != nil {
+			return fmt.Errorf("failed to start command %v in a terminal: %w", p.Cmd.Args, err)
+		}
+	} else {
+		var err error
+		// Stdin stays /dev/null unless the script asks for input, so tools that
+		// read stdin when it isn't a terminal see end-of-file instead of hanging
+		if scriptCfg.GetStdin() {
+			stdin, inputCh, err = m.openInput(p)
+			if err != nil {
+				return err
+			}
+		}
 
-	if err := p.Cmd.Start(); err != nil {
-		// Add more context to the error
-		return fmt.Errorf("failed to start command %v: %w", p.Cmd.Args, err)
+		// Persisted sessions log to files so the process survives brummer exiting
+		if m.session != nil {
+			stdoutPath, stderrPath := m.session.logPaths(p)
+			output, err = openLogOutput(p.Cmd, stdoutPath, stderrPath, m.config.GetLogs().GetSegmentSize())
+		} else {
+			output, err = openOutput(p.Cmd)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := m.startLimited(p, limits, p.Cmd.Start); err != nil {
+			output.closeAll()
+			removeOutputLogs(output)
+			// Add more context to the error
+			return fmt.Errorf("failed to start command %v: %w", p.Cmd.Args, err)
+		}
+		output.closeWriters()
 	}
 
+	port := m.AssignedPort(p.Name)
 	p.mu.Lock()
 	p.Status = StatusRunning
+	p.interactive = usePTY || stdin != nil
+	p.output = output
+	p.assignedPort = port
+	if p.Cmd.Process != nil {
+		p.procStart, _ = procStartTime(p.Cmd.Process.Pid)
+	}
 	p.mu.Unlock()
+	m.saveSession()
 
+	data := map[string]interface{}{
+		"name":   p.Name,
+		"script": p.Script,
+		"cmd":    p.Cmd.Args,
+	}
+	if port > 0 {
+		data["port"] = port
+	}
 	m.eventBus.Publish(events.Event{
 		Type:      events.ProcessStarted,
 		ProcessID: p.ID,
-		Data: map[string]interface{}{
-			"name":   p.Name,
-			"script": p.Script,
-			"cmd":    p.Cmd.Args,
-		},
+		Data:      data,
 	})
 
-	go m.streamLogs(p.ID, stdout, false)
-	go m.streamLogs(p.ID, stderr, true)
+	if usePTY {
+		go m.streamPTY(p)
+	} else {
+		go output.stream(m, p)
+		if stdin != nil {
+			go m.forwardInput(p, stdin, inputCh)
+		}
+	}
+	go m.watchReadiness(p, readiness, health.GetReadiness() != nil)
+	m.startHealthChecks(p, health)
+	m.startMetrics(p)
+	m.startWatch(p, scriptCfg.GetWatch())
 
 	go func() {
 		err := p.Cmd.Wait()
+		p.closePTY()
+		if output != nil {
+			output.close()
+			removeOutputLogs(output)
+		}
+		oomKilled := m.releaseLimits(p)
 
 		// Ensure clean log separation when process exits
 		// This adds a newline to ensure the next process starts on a new line
@@ -441,8 +687,22 @@ func (m *Manager) runProcess(p *Process) error {
 			p.ExitCode = &code
 			p.Status = StatusSuccess
 		}
+		if oomKilled {
+			p.Status = StatusFailed
+			p.exitReason = ExitReasonOOM
+		} else if p.stopRequested {
+			// However it exited, a process that was asked to stop is stopped
+			p.Status = StatusStopped
+		}
+		exitStatus := p.Status
+		uptime := now.Sub(p.StartTime)
 		p.mu.Unlock()
 
+		if p.readyOnExit && exitStatus == StatusSuccess {
+			m.setReady(p, "exit")
+		}
+		close(p.done)
+
 		// Add a failure summary log for failed processes
 		if p.Status == StatusFailed {
 			m.mu.RLock()
@@ -455,6 +715,9 @@ func (m *Manager) runProcess(p *Process) error {
 			}
 
 			failureMsg := fmt.Sprintf("❌ Process '%s' failed%s", p.Name, exitCodeStr)
+			if oomKilled {
+				failureMsg = fmt.Sprintf("💥 Process '%s' was killed for exceeding its memory limit (%d MB)", p.Name, p.limits.MemoryMB)
+			}
 			for _, cb := range callbacks {
 				cb(p.ID, failureMsg, true)
 			}
@@ -467,65 +730,48 @@ func (m *Manager) runProcess(p *Process) error {
 				"name":     p.Name,
 				"status":   p.Status,
 				"exitCode": p.ExitCode,
+				"reason":   string(p.exitReason),
 			},
 		})
+
+		m.saveSession()
+		m.handleProcessExit(p, p.spec, exitStatus, uptime)
 	}()
 
 	return nil
 }
 
-func (m *Manager) streamLogs(processID string, reader io.Reader, isError bool) {
-	// Use a buffered reader to handle partial lines
-	bufReader := bufio.NewReader(reader)
-
-	for {
-		line, err := bufReader.ReadString('\n')
-		if err != nil {
-			// If we have partial data when EOF is reached, still process it
-			if err == io.EOF && len(line) > 0 {
-				// Remove any trailing newline if present
-				line = strings.TrimSuffix(line, "\n")
-
-				m.mu.RLock()
-				callbacks := m.logCallbacks
-				m.mu.RUnlock()
-
-				for _, cb := range callbacks {
-					cb(processID, line, isError)
-				}
-
-				m.eventBus.Publish(events.Event{
-					Type:      events.LogLine,
-					ProcessID: processID,
-					Data: map[string]interface{}{
-						"line":    line,
-						"isError": isError,
-					},
-				})
-			}
-			break
-		}
-
-		// Remove the newline character
-		line = strings.TrimSuffix(line, "\n")
+func (m *Manager) streamLogs(p *Process, reader io.Reader, isError bool) {
+	p.mu.RLock()
+	interactive := p.interactive
+	p.mu.RUnlock()
+	m.readOutput(p, reader, isError, interactive, nil, func(line string) string { return line })
+}
 
-		m.mu.RLock()
-		callbacks := m.logCallbacks
-		m.mu.RUnlock()
+// emitLogLine checks a line of output against the readiness pattern and delivers it
+// to the log callbacks and the event bus
+func (m *Manager) emitLogLine(p *Process, line string, isError bool) {
+	if p.matchesReadyLine(line) {
+		m.setReady(p, "log")
+	}
+	m.checkPortConflict(p, line)
 
-		for _, cb := range callbacks {
-			cb(processID, line, isError)
-		}
+	m.mu.RLock()
+	callbacks := m.logCallbacks
+	m.mu.RUnlock()
 
-		m.eventBus.Publish(events.Event{
-			Type:      events.LogLine,
-			ProcessID: processID,
-			Data: map[string]interface{}{
-				"line":    line,
-				"isError": isError,
-			},
-		})
+	for _, cb := range callbacks {
+		cb(p.ID, line, isError)
 	}
+
+	m.eventBus.Publish(events.Event{
+		Type:      events.LogLine,
+		ProcessID: p.ID,
+		Data: map[string]interface{}{
+			"line":    line,
+			"isError": isError,
+		},
+	})
 }
 
 func (m *Manager) StopProcess(processID string) error {
@@ -543,8 +789,11 @@ func (m *Manager) StopProcess(processID string) error {
 	process.mu.Lock()
 	if process.Status != StatusRunning {
 		process.mu.Unlock()
+		// Stopping a crashed process also calls off its pending restart
+		m.clearRestartState(process.Name)
 		return fmt.Errorf("process %s is not running", processID)
 	}
+	process.stopRequested = true
 
 	// Get the PID before we start killing
 	var mainPID int
@@ -552,59 +801,30 @@ func (m *Manager) StopProcess(processID string) error {
 		mainPID = process.Cmd.Process.Pid
 	}
 
-	// First try graceful shutdown
-	if process.cancel != nil {
-		process.cancel()
-	}
+	process.Status = StatusStopped
+	now := time.Now()
+	process.EndTime = &now
+	exitCode := -1
+	process.ExitCode = &exitCode
+	process.mu.Unlock()
 
-	// Kill the process tree aggressively
-	if process.Cmd != nil && process.Cmd.Process != nil {
-		m.killProcessTree(process.Cmd.Process.Pid)
+	// Run the pre_stop hook, send the stop signal and kill what is left after the timeout
+	if mainPID > 0 {
+		m.stopProcessTree(process, mainPID)
+	} else if process.cancel != nil {
+		process.cancel()
 	}
 
 	// Also kill any processes that might be using development ports
-	// Do this asynchronously but with immediate action for dev processes
 	if process.Name == "dev" || process.Name == "start" ||
 		strings.Contains(process.Script, "npm") ||
 		strings.Contains(process.Script, "pnpm") ||
 		strings.Contains(process.Script, "yarn") {
-		// Don't wait - kill immediately in background
+		// Don't wait - kill in background
 		go m.KillProcessesByPort()
 	}
 
-	process.Status = StatusStopped
-	now := time.Now()
-	process.EndTime = &now
-	exitCode := -1
-	process.ExitCode = &exitCode
-	process.mu.Unlock()
-
-	// Asynchronously verify process termination
-	go func() {
-		// Small delay to allow processes to terminate gracefully
-		timer := time.NewTimer(100 * time.Millisecond)
-		defer timer.Stop()
-
-		select {
-		case <-timer.C:
-			// Double-check that the main process is dead
-			if mainPID > 0 {
-				m.ensureProcessDead(mainPID)
-			}
-
-			// For package manager processes, be extra thorough
-			if process.Name == "dev" || process.Name == "start" ||
-				strings.Contains(process.Script, "npm") ||
-				strings.Contains(process.Script, "pnpm") ||
-				strings.Contains(process.Script, "yarn") {
-				// Give package managers extra time then do additional cleanup
-				time.Sleep(200 * time.Millisecond)
-				m.KillProcessesByPort()
-			}
-		}
-	}()
-
-	// Publish stop event immediately
+	// Publish stop event
 	m.eventBus.Publish(events.Event{
 		Type:      events.ProcessExited,
 		ProcessID: processID,
@@ -789,6 +1009,9 @@ func (m *Manager) AddLogCallback(cb LogCallback) {
 
 // StopAllProcesses stops all running processes
 func (m *Manager) StopAllProcesses() error {
+	m.cancelAllRestarts()
+	m.stopAllWatches()
+
 	var processIDs []string
 
 	// Use sync.Map.Range for lock-free iteration
@@ -801,19 +1024,32 @@ func (m *Manager) StopAllProcesses() error {
 		return true // continue iteration
 	})
 
+	// Stop in parallel so each process gets its own stop timeout
+	var wg sync.WaitGroup
+	var errMu sync.Mutex
 	var lastError error
 	for _, id := range processIDs {
-		if err := m.StopProcess(id); err != nil {
-			lastError = err
-		}
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			if err := m.StopProcess(id); err != nil {
+				errMu.Lock()
+				lastError = err
+				errMu.Unlock()
+			}
+		}(id)
 	}
+	wg.Wait()
 
 	return lastError
 }
 
 // Cleanup stops all processes and cleans up resources
 func (m *Manager) Cleanup() error {
+	m.StopSchedules()
 	err := m.StopAllProcesses()
+	m.closeSession()
+	m.releasePorts()
 
 	// Kill any remaining development processes with minimal blocking
 	done := make(chan bool, 1)
@@ -830,11 +1066,20 @@ func (m *Manager) Cleanup() error {
 		// Timeout - continue shutdown
 	}
 
+	// Remove the cgroups limits were enforced in; best effort, like the above
+	cgroup.Teardown()
+
 	return err
 }
 
-// killProcessTree kills a process and all its children
-func (m *Manager) killProcessTree(pid int) {
+// killProcessTree kills a process and all its children, recording each one with
+// the reason it was killed
+func (m *Manager) killProcessTree(pid int, reason string) {
+	// Never take down brummer itself, whatever a pattern matched
+	if pid == os.Getpid() {
+		return
+	}
+	m.recordTerminations(processTreePIDs(pid), "", reason, true)
 	killProcessByPID(pid)
 
 	// Also try to find and kill child processes on Unix
@@ -879,8 +1124,13 @@ func (m *Manager) ensureProcessDead(pid int) {
 	ensureProcessDead(pid)
 }
 
-// KillProcessesByPort kills processes using development ports
+// KillProcessesByPort kills processes using development ports. It does nothing
+// when kill_dev_processes is turned off.
 func (m *Manager) KillProcessesByPort() {
+	if !m.config.GetKillDevProcesses() {
+		return
+	}
+
 	// Find processes using development ports (3000-3009)
 	for port := 3000; port <= 3009; port++ {
 		m.killProcessUsingPort(port)
@@ -936,7 +1186,7 @@ func (m *Manager) killProcessUsingPort(port int) {
 		for _, line := range strings.Split(lines, "\n") {
 			if pid, err := strconv.Atoi(strings.TrimSpace(line)); err == nil {
 				// Use the more aggressive killProcessTree for port-based killing
-				m.killProcessTree(pid)
+				m.killProcessTree(pid, fmt.Sprintf("listening on dev port %d", port))
 			}
 		}
 	}
@@ -961,7 +1211,7 @@ func (m *Manager) killProcessesByPattern(pattern string) {
 	for _, line := range strings.Split(lines, "\n") {
 		if pid, err := strconv.Atoi(strings.TrimSpace(line)); err == nil {
 			// Use the more aggressive killProcessTree for pattern-based killing
-			m.killProcessTree(pid)
+			m.killProcessTree(pid, fmt.Sprintf("command line matches %q", pattern))
 		}
 	}
 }
@@ -1021,7 +1271,7 @@ func (m *Manager) killNodeDevProcesses() {
 
 	// Kill the identified development server processes
 	for _, pid := range devNodePIDs {
-		m.killProcessTree(pid)
+		m.killProcessTree(pid, "node process that looks like a dev server")
 	}
 }
 
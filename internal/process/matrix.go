@@ -0,0 +1,103 @@
+package process
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/standardbeagle/brummer/internal/parser"
+	"github.com/standardbeagle/brummer/pkg/events"
+)
+
+// MatrixOptions selects the packages a script fans out across
+type MatrixOptions struct {
+	Filters     []string // globs matched against package names and paths; every package when empty
+	Concurrency int      // packages running at once; defaultRunConcurrency when zero
+}
+
+// MatrixPackages returns the workspace packages that define script and match one of
+// the filters, sorted by name
+func (m *Manager) MatrixPackages(script string, filters []string) ([]parser.PackageInfo, error) {
+	info, err := m.GetMonorepoInfo()
+	if err != nil {
+		return nil, fmt.Errorf("no workspace packages found: %w", err)
+	}
+
+	var packages []parser.PackageInfo
+	for _, pkg := range info.Packages {
+		if _, ok := pkg.Scripts[script]; !ok {
+			continue
+		}
+		if len(filters) > 0 && !m.matchesPackageFilter(pkg, filters) {
+			continue
+		}
+		packages = append(packages, pkg)
+	}
+	if len(packages) == 0 {
+		if len(filters) > 0 {
+			return nil, fmt.Errorf("no package matching %s defines a '%s' script", strings.Join(filters, ", "), script)
+		}
+		return nil, fmt.Errorf("no package defines a '%s' script", script)
+	}
+
+	sort.Slice(packages, func(i, j int) bool {
+		return packageLabel(packages[i]) < packageLabel(packages[j])
+	})
+	return packages, nil
+}
+
+// matchesPackageFilter matches a glob against the package name, its directory name
+// and its path relative to the project
+func (m *Manager) matchesPackageFilter(pkg parser.PackageInfo, filters []string) bool {
+	candidates := []string{pkg.Name, filepath.Base(pkg.Path)}
+	if rel, err := filepath.Rel(m.workDir, pkg.Path); err == nil {
+		candidates = append(candidates, filepath.ToSlash(rel))
+	}
+	for _, filter := range filters {
+		for _, candidate := range candidates {
+			if ok, _ := path.Match(filter, candidate); ok && candidate != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// packageLabel names a package in process names and summaries
+func packageLabel(pkg parser.PackageInfo) string {
+	if pkg.Name != "" {
+		return pkg.Name
+	}
+	return filepath.Base(pkg.Path)
+}
+
+// RunMatrix runs script in every selected workspace package, a few at a time. Each
+// package runs as its own process named "<package>:<script>". It returns once the run
+// is set up; Done reports when every package has finished.
+func (m *Manager) RunMatrix(script string, opts MatrixOptions) (*Run, error) {
+	packages, err := m.MatrixPackages(script, opts.Filters)
+	if err != nil {
+		return nil, err
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultRunConcurrency
+	}
+
+	results := make([]RunResult, len(packages))
+	for i, pkg := range packages {
+		label := packageLabel(pkg)
+		results[i] = RunResult{Name: label, Package: label, Dir: pkg.Path, Status: StatusPending}
+	}
+	run := newRun("matrix", script, "packages", results)
+
+	m.emitSystemLog("system", fmt.Sprintf("🧮 Running '%s' in %d packages, %d at a time", script, len(packages), concurrency), false)
+	cmdArgs := m.GetCurrentPackageManager().RunScriptCommand(script)
+	command := func(i int) (string, string, []string) {
+		return fmt.Sprintf("%s:%s", packageLabel(packages[i]), script), cmdArgs[0], cmdArgs[1:]
+	}
+	go m.executeRun(run, concurrency, nil, command, events.MatrixFinished)
+	return run, nil
+}
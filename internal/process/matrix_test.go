@@ -0,0 +1,115 @@
+//go:build !windows
+
+package process
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/standardbeagle/brummer/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeWorkspace creates an npm workspace with one package per entry in scripts,
+// each defining a test script
+func writeWorkspace(t *testing.T, scripts map[string]string) string {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"),
+		[]byte(`{"name": "root", "workspaces": ["packages/*"]}`), 0644))
+	for name, script := range scripts {
+		pkgDir := filepath.Join(dir, "packages", name)
+		require.NoError(t, os.MkdirAll(pkgDir, 0755))
+		pkgJSON := fmt.Sprintf(`{"name": %q, "scripts": {"test": %q}}`, name, script)
+		require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "package.json"), []byte(pkgJSON), 0644))
+	}
+	return dir
+}
+
+// TestRunMatrixSummarizesPackages tests that a matrix run starts one process per
+// package in its directory and reports which packages failed
+func TestRunMatrixSummarizesPackages(t *testing.T) {
+	if _, err := exec.LookPath("npm"); err != nil {
+		t.Skip("npm is not installed")
+	}
+	dir := writeWorkspace(t, map[string]string{
+		"pkg-a": "pwd",
+		"pkg-b": "exit 3",
+		"pkg-c": "pwd",
+	})
+	eventBus := events.NewEventBus()
+	mgr, err := NewManager(dir, eventBus, false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	finished := make(chan events.Event, 1)
+	eventBus.Subscribe(events.MatrixFinished, func(e events.Event) { finished <- e })
+	lines := &lineRecorder{}
+	mgr.RegisterLogCallback(lines.record)
+
+	run, err := mgr.RunMatrix("test", MatrixOptions{Concurrency: 2})
+	require.NoError(t, err)
+	select {
+	case <-run.Done():
+	case <-time.After(60 * time.Second):
+		t.Fatal("matrix run did not finish")
+	}
+
+	summary := run.Summary()
+	assert.True(t, summary.Finished)
+	assert.Equal(t, 3, summary.Total)
+	assert.Equal(t, 2, summary.Passed)
+	assert.Equal(t, 1, summary.Failed)
+	require.Len(t, summary.Results, 3)
+	assert.Equal(t, "pkg-b", summary.Results[1].Package)
+	require.NotNil(t, summary.Results[1].ExitCode)
+	assert.Equal(t, 3, *summary.Results[1].ExitCode)
+	assert.Contains(t, summary.String(), "failed: pkg-b (exit 3)")
+
+	proc, ok := mgr.GetProcess(summary.Results[0].ProcessID)
+	require.True(t, ok)
+	assert.Equal(t, "pkg-a:test", proc.Name)
+	assert.True(t, lines.contains(filepath.Join("packages", "pkg-a")))
+
+	select {
+	case e := <-finished:
+		assert.Equal(t, 1, e.Data["failed"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("no matrix.finished event")
+	}
+}
+
+// TestMatrixPackagesFilter tests that filters match package names and paths, and
+// that packages without the script are left out
+func TestMatrixPackagesFilter(t *testing.T) {
+	dir := writeWorkspace(t, map[string]string{
+		"pkg-a": "true",
+		"pkg-b": "true",
+		"web":   "true",
+	})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "packages", "pkg-b", "package.json"),
+		[]byte(`{"name": "pkg-b", "scripts": {"build": "true"}}`), 0644))
+	mgr, err := NewManager(dir, events.NewEventBus(), false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	packages, err := mgr.MatrixPackages("test", []string{"pkg-*"})
+	require.NoError(t, err)
+	require.Len(t, packages, 1)
+	assert.Equal(t, "pkg-a", packages[0].Name)
+
+	packages, err = mgr.MatrixPackages("test", []string{"packages/web", "pkg-a"})
+	require.NoError(t, err)
+	assert.Len(t, packages, 2)
+
+	packages, err = mgr.MatrixPackages("test", nil)
+	require.NoError(t, err)
+	assert.Len(t, packages, 2)
+
+	_, err = mgr.MatrixPackages("test", []string{"api"})
+	assert.Error(t, err)
+}
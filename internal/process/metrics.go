@@ -0,0 +1,107 @@
+package process
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// metricsInterval is how often a running process tree is sampled
+	metricsInterval = 2 * time.Second
+	// metricsCapacity is the number of samples kept per process (10 minutes)
+	metricsCapacity = 300
+)
+
+// MetricSample is the resource usage of a process and all of its descendants
+type MetricSample struct {
+	Time       time.Time `json:"time"`
+	CPUPercent float64   `json:"cpuPercent"`
+	RSSBytes   uint64    `json:"rssBytes"`
+	Threads    int       `json:"threads"`
+	OpenFDs    int       `json:"openFds"`
+	Processes  int       `json:"processes"`
+}
+
+// metricsRing is a fixed-size ring buffer of samples, oldest first
+type metricsRing struct {
+	mu      sync.RWMutex
+	samples []MetricSample
+	next    int
+	full    bool
+}
+
+func newMetricsRing(capacity int) *metricsRing {
+	return &metricsRing{samples: make([]MetricSample, capacity)}
+}
+
+func (r *metricsRing) add(s MetricSample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples[r.next] = s
+	r.next = (r.next + 1) % len(r.samples)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// snapshot returns the buffered samples in chronological order
+func (r *metricsRing) snapshot() []MetricSample {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if !r.full {
+		return append([]MetricSample(nil), r.samples[:r.next]...)
+	}
+	out := make([]MetricSample, 0, len(r.samples))
+	out = append(out, r.samples[r.next:]...)
+	return append(out, r.samples[:r.next]...)
+}
+
+// Metrics returns the resource samples recorded for the process, oldest first
+func (p *Process) Metrics() []MetricSample {
+	p.mu.RLock()
+	ring := p.metrics
+	p.mu.RUnlock()
+	if ring == nil {
+		return nil
+	}
+	return ring.snapshot()
+}
+
+// LatestMetrics returns the most recent resource sample, if any
+func (p *Process) LatestMetrics() (MetricSample, bool) {
+	samples := p.Metrics()
+	if len(samples) == 0 {
+		return MetricSample{}, false
+	}
+	return samples[len(samples)-1], true
+}
+
+// startMetrics samples the process tree on an interval until the process exits
+func (m *Manager) startMetrics(p *Process) {
+	if !metricsSupported || p.Cmd.Process == nil {
+		return
+	}
+
+	ring := newMetricsRing(metricsCapacity)
+	p.mu.Lock()
+	p.metrics = ring
+	p.mu.Unlock()
+
+	go func() {
+		sampler := newTreeSampler(p.Cmd.Process.Pid)
+		ticker := time.NewTicker(metricsInterval)
+		defer ticker.Stop()
+
+		for {
+			if sample, err := sampler.sample(); err == nil {
+				ring.add(sample)
+			}
+
+			select {
+			case <-p.done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,192 @@
+//go:build linux
+
+package process
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const metricsSupported = true
+
+// clockTicks is USER_HZ, the unit of the CPU times in /proc/<pid>/stat. It is
+// 100 on every Linux platform Go supports.
+const clockTicks = 100
+
+// procStat holds the fields of /proc/<pid>/stat the sampler needs
+type procStat struct {
+	pid     int
+	ppid    int
+	pgrp    int
+	state   byte
+	ticks   uint64 // utime + stime
+	threads int
+	started uint64 // clock ticks after boot
+	rss     uint64 // pages
+}
+
+// parseProcStat parses the contents of /proc/<pid>/stat
+func parseProcStat(data string) (procStat, error) {
+	// The command name is in parentheses and may itself contain spaces or parens
+	open := strings.IndexByte(data, '(')
+	closing := strings.LastIndexByte(data, ')')
+	if open < 0 || closing < open {
+		return procStat{}, fmt.Errorf("malformed stat line")
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(data[:open]))
+	if err != nil {
+		return procStat{}, err
+	}
+
+	// fields[0] is field 3 (state) in proc(5) numbering
+	fields := strings.Fields(data[closing+1:])
+	if len(fields) < 22 {
+		return procStat{}, fmt.Errorf("short stat line")
+	}
+
+	st := procStat{pid: pid, state: fields[0][0]}
+	st.ppid, _ = strconv.Atoi(fields[1])
+	st.pgrp, _ = strconv.Atoi(fields[2])
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	st.ticks = utime + stime
+	st.threads, _ = strconv.Atoi(fields[17])
+	st.started, _ = strconv.ParseUint(fields[19], 10, 64)
+	st.rss, _ = strconv.ParseUint(fields[21], 10, 64)
+	return st, nil
+}
+
+// procStartTime returns when a process started, in clock ticks after boot. Together
+// with the PID it identifies a process even after the PID is reused.
+func procStartTime(pid int) (uint64, bool) {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return 0, false
+	}
+	st, err := parseProcStat(string(data))
+	if err != nil {
+		return 0, false
+	}
+	return st.started, true
+}
+
+// readAllProcStats reads the stat file of every visible process
+func readAllProcStats() map[int]procStat {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil
+	}
+
+	stats := make(map[int]procStat, len(entries))
+	for _, entry := range entries {
+		if _, err := strconv.Atoi(entry.Name()); err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join("/proc", entry.Name(), "stat"))
+		if err != nil {
+			continue // exited since the directory was listed
+		}
+		if st, err := parseProcStat(string(data)); err == nil {
+			stats[st.pid] = st
+		}
+	}
+	return stats
+}
+
+// processTree returns root and all of its descendants
+func processTree(root int, stats map[int]procStat) []int {
+	children := make(map[int][]int)
+	for pid, st := range stats {
+		children[st.ppid] = append(children[st.ppid], pid)
+	}
+
+	tree := []int{root}
+	for i := 0; i < len(tree); i++ {
+		tree = append(tree, children[tree[i]]...)
+	}
+	return tree
+}
+
+// processTreePIDs returns pid and all of its descendants
+func processTreePIDs(pid int) []int {
+	return processTree(pid, readAllProcStats())
+}
+
+// processGroupMembers returns the processes in a process group that have not yet
+// exited. Zombies are left out, since nothing can signal them away.
+func processGroupMembers(pgid int) ([]int, bool) {
+	var members []int
+	for pid, st := range readAllProcStats() {
+		if st.pgrp == pgid && st.state != 'Z' {
+			members = append(members, pid)
+		}
+	}
+	return members, true
+}
+
+// processCommand returns the command line of a process, for reporting
+func processCommand(pid int) string {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "cmdline"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.ReplaceAll(string(data), "\x00", " "))
+}
+
+func countFDs(pid int) int {
+	entries, err := os.ReadDir(filepath.Join("/proc", strconv.Itoa(pid), "fd"))
+	if err != nil {
+		return 0
+	}
+	return len(entries)
+}
+
+// treeSampler measures a process tree; CPU usage is computed between samples
+type treeSampler struct {
+	root      int
+	pageSize  uint64
+	lastTicks uint64
+	lastTime  time.Time
+}
+
+func newTreeSampler(root int) *treeSampler {
+	return &treeSampler{root: root, pageSize: uint64(os.Getpagesize())}
+}
+
+func (s *treeSampler) sample() (MetricSample, error) {
+	stats := readAllProcStats()
+	if _, ok := stats[s.root]; !ok {
+		return MetricSample{}, fmt.Errorf("process %d not found", s.root)
+	}
+
+	now := time.Now()
+	sample := MetricSample{Time: now}
+	var ticks uint64
+	for _, pid := range processTree(s.root, stats) {
+		st, ok := stats[pid]
+		if !ok {
+			continue
+		}
+		ticks += st.ticks
+		sample.RSSBytes += st.rss * s.pageSize
+		sample.Threads += st.threads
+		sample.OpenFDs += countFDs(pid)
+		sample.Processes++
+	}
+
+	// Children that exit take their ticks with them, so the total can shrink
+	if !s.lastTime.IsZero() && ticks >= s.lastTicks {
+		elapsed := now.Sub(s.lastTime).Seconds()
+		if elapsed > 0 {
+			sample.CPUPercent = float64(ticks-s.lastTicks) / clockTicks / elapsed * 100
+		}
+	}
+	s.lastTicks = ticks
+	s.lastTime = now
+	return sample, nil
+}
@@ -0,0 +1,31 @@
+//go:build linux
+
+package process
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseProcStat tests parsing a stat line whose command name contains spaces and parens
+func TestParseProcStat(t *testing.T) {
+	line := "4242 (node (dev) x) S 4200 4242 4242 0 -1 4194560 1000 0 0 0 150 50 0 0 20 0 11 0 123456 987654321 2048 18446744073709551615"
+
+	st, err := parseProcStat(line)
+	require.NoError(t, err)
+	assert.Equal(t, 4242, st.pid)
+	assert.Equal(t, 4200, st.ppid)
+	assert.Equal(t, uint64(200), st.ticks)
+	assert.Equal(t, 11, st.threads)
+	assert.Equal(t, uint64(2048), st.rss)
+
+	tree := processTree(1, map[int]procStat{
+		1: {pid: 1},
+		2: {pid: 2, ppid: 1},
+		3: {pid: 3, ppid: 2},
+		4: {pid: 4, ppid: 99},
+	})
+	assert.ElementsMatch(t, []int{1, 2, 3}, tree)
+}
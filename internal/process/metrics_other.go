@@ -0,0 +1,38 @@
+//go:build !linux
+
+package process
+
+import "fmt"
+
+// Resource sampling reads /proc and is only available on Linux
+const metricsSupported = false
+
+type treeSampler struct{}
+
+func newTreeSampler(root int) *treeSampler {
+	return &treeSampler{}
+}
+
+func (s *treeSampler) sample() (MetricSample, error) {
+	return MetricSample{}, fmt.Errorf("process metrics are not supported on this platform")
+}
+
+// procStartTime is unavailable without /proc; PIDs are trusted as they are
+func procStartTime(pid int) (uint64, bool) {
+	return 0, false
+}
+
+// processTreePIDs cannot see descendants without /proc
+func processTreePIDs(pid int) []int {
+	return []int{pid}
+}
+
+// processGroupMembers is unavailable without /proc
+func processGroupMembers(pgid int) ([]int, bool) {
+	return nil, false
+}
+
+// processCommand is unavailable without /proc
+func processCommand(pid int) string {
+	return ""
+}
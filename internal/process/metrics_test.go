@@ -0,0 +1,50 @@
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/standardbeagle/brummer/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMetricsRing tests that the ring keeps the newest samples in order
+func TestMetricsRing(t *testing.T) {
+	ring := newMetricsRing(3)
+	assert.Empty(t, ring.snapshot())
+
+	for i := 1; i <= 5; i++ {
+		ring.add(MetricSample{Threads: i})
+	}
+
+	samples := ring.snapshot()
+	require.Len(t, samples, 3)
+	assert.Equal(t, 3, samples[0].Threads)
+	assert.Equal(t, 4, samples[1].Threads)
+	assert.Equal(t, 5, samples[2].Threads)
+}
+
+// TestProcessMetricsSampled tests that a running process tree is sampled
+func TestProcessMetricsSampled(t *testing.T) {
+	if !metricsSupported {
+		t.Skip("process metrics are not supported on this platform")
+	}
+
+	mgr, err := NewManager(t.TempDir(), events.NewEventBus(), false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	proc, err := mgr.StartCommand("sleeper", "sleep", []string{"5"})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		_, ok := proc.LatestMetrics()
+		return ok
+	}, 3*time.Second, 50*time.Millisecond)
+
+	sample, _ := proc.LatestMetrics()
+	assert.GreaterOrEqual(t, sample.Processes, 1)
+	assert.Greater(t, sample.RSSBytes, uint64(0))
+	assert.GreaterOrEqual(t, sample.Threads, 1)
+}
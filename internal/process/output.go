@@ -0,0 +1,276 @@
+package process
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// outputDrainTimeout bounds how long output is read after the process exits, for
+// background children that keep stdout or stderr open
+const outputDrainTimeout = time.Second
+
+// tailPollInterval is how often a log file tail checks for new output
+const tailPollInterval = 100 * time.Millisecond
+
+// outputPipes connects a process's stdout and stderr to pipes owned by the manager.
+// Unlike Cmd.StdoutPipe they are not closed by Cmd.Wait, so output written just
+// before the process exits is still read. With session persistence the process
+// writes to log files instead, which are tailed and outlive brummer.
+type outputPipes struct {
+	stdout, stderr             io.ReadCloser // read ends
+	stdoutWriter, stderrWriter *os.File      // nil when tailing an adopted process
+	stdoutTail, stderrTail     *logTail      // set when output goes to log files
+	done                       chan struct{} // closed once both read ends reach EOF
+}
+
+// openOutput creates the pipes and attaches their write ends to the command
+func openOutput(cmd *exec.Cmd) (*outputPipes, error) {
+	stdout, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, stderrWriter, err := os.Pipe()
+	if err != nil {
+		stdout.Close()
+		stdoutWriter.Close()
+		return nil, err
+	}
+
+	cmd.Stdout = stdoutWriter
+	cmd.Stderr = stderrWriter
+	return &outputPipes{
+		stdout:       stdout,
+		stderr:       stderr,
+		stdoutWriter: stdoutWriter,
+		stderrWriter: stderrWriter,
+		done:         make(chan struct{}),
+	}, nil
+}
+
+// openLogOutput sends the command's output to append-only log files and tails them.
+// A file is rotated once it has been read past maxBytes; zero means no limit.
+func openLogOutput(cmd *exec.Cmd, stdoutPath, stderrPath string, maxBytes int64) (*outputPipes, error) {
+	stdoutWriter, err := os.OpenFile(stdoutPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	stderrWriter, err := os.OpenFile(stderrPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		stdoutWriter.Close()
+		return nil, err
+	}
+
+	o, err := tailOutput(stdoutPath, 0, stderrPath, 0, maxBytes)
+	if err != nil {
+		stdoutWriter.Close()
+		stderrWriter.Close()
+		return nil, err
+	}
+
+	cmd.Stdout = stdoutWriter
+	cmd.Stderr = stderrWriter
+	o.stdoutWriter = stdoutWriter
+	o.stderrWriter = stderrWriter
+	return o, nil
+}
+
+// tailOutput follows log files another process is writing, from the given offsets
+func tailOutput(stdoutPath string, stdoutOffset int64, stderrPath string, stderrOffset int64, maxBytes int64) (*outputPipes, error) {
+	stdout, err := openTail(stdoutPath, stdoutOffset, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := openTail(stderrPath, stderrOffset, maxBytes)
+	if err != nil {
+		stdout.Close()
+		return nil, err
+	}
+
+	return &outputPipes{
+		stdout:     stdout,
+		stderr:     stderr,
+		stdoutTail: stdout,
+		stderrTail: stderr,
+		done:       make(chan struct{}),
+	}, nil
+}
+
+// closeWriters drops the parent's copies of the write ends once the child has
+// inherited them, so the readers see EOF when the process tree exits
+func (o *outputPipes) closeWriters() {
+	if o.stdoutWriter != nil {
+		o.stdoutWriter.Close()
+		o.stderrWriter.Close()
+	}
+}
+
+// stream reads both pipes until EOF
+func (o *outputPipes) stream(m *Manager, p *Process) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		m.streamLogs(p, o.stdout, false)
+	}()
+	go func() {
+		defer wg.Done()
+		m.streamLogs(p, o.stderr, true)
+	}()
+	wg.Wait()
+	close(o.done)
+}
+
+// close waits briefly for the readers to drain and then closes the read ends
+func (o *outputPipes) close() {
+	// Log files never report EOF on their own; tell the tails the writer is gone
+	if o.stdoutTail != nil {
+		o.stdoutTail.finish()
+		o.stderrTail.finish()
+	}
+
+	select {
+	case <-o.done:
+	case <-time.After(outputDrainTimeout):
+	}
+	o.stdout.Close()
+	o.stderr.Close()
+}
+
+// closeAll releases every pipe when the process failed to start
+func (o *outputPipes) closeAll() {
+	o.closeWriters()
+	o.stdout.Close()
+	o.stderr.Close()
+}
+
+// logFiles returns the log file paths and how far they have been read, or false
+// when the output goes through pipes
+func (o *outputPipes) logFiles() (stdoutPath string, stdoutOffset int64, stderrPath string, stderrOffset int64, ok bool) {
+	if o == nil || o.stdoutTail == nil {
+		return "", 0, "", 0, false
+	}
+	return o.stdoutTail.file.Name(), o.stdoutTail.offset.Load(),
+		o.stderrTail.file.Name(), o.stderrTail.offset.Load(), true
+}
+
+// logTail reads a log file as it grows, like tail -f, until it is finished. Once
+// everything up to maxBytes has been read, the file is copied to a rotated log and
+// emptied; the writer opened it with O_APPEND, so it carries on at the new end.
+type logTail struct {
+	file     *os.File
+	offset   atomic.Int64
+	maxBytes int64 // zero means the file is never rotated
+	finished chan struct{}
+	once     sync.Once
+}
+
+func openTail(path string, offset int64, maxBytes int64) (*logTail, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+
+	t := &logTail{file: file, maxBytes: maxBytes, finished: make(chan struct{})}
+	t.offset.Store(offset)
+	return t, nil
+}
+
+func (t *logTail) Read(b []byte) (int, error) {
+	for {
+		// Checked before reading, so EOF after finishing means everything was read
+		finished := t.isFinished()
+
+		n, err := t.file.Read(b)
+		if n > 0 {
+			t.offset.Add(int64(n))
+			return n, nil
+		}
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		if finished {
+			return 0, io.EOF
+		}
+		if t.maxBytes > 0 && t.offset.Load() >= t.maxBytes {
+			t.rotate()
+		}
+
+		select {
+		case <-t.finished:
+		case <-time.After(tailPollInterval):
+		}
+	}
+}
+
+// rotate copies what has been read to the rotated log, replacing the previous one,
+// and empties the file. It does nothing if more output arrived since the last read,
+// which is then read before trying again.
+func (t *logTail) rotate() {
+	path := t.file.Name()
+	offset := t.offset.Load()
+	if err := copyLog(path, rotatedLogPath(path), offset); err != nil {
+		return
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() != offset {
+		return
+	}
+	if err := os.Truncate(path, 0); err != nil {
+		return
+	}
+	if _, err := t.file.Seek(0, io.SeekStart); err == nil {
+		t.offset.Store(0)
+	}
+}
+
+// rotatedLogPath is where a log file's previous contents are kept
+func rotatedLogPath(path string) string {
+	return path + ".1"
+}
+
+// copyLog copies the first n bytes of a log file to dst
+func copyLog(src, dst string, n int64) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.CopyN(out, in, n); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// finish tells the tail that nothing more will be written
+func (t *logTail) finish() {
+	t.once.Do(func() { close(t.finished) })
+}
+
+func (t *logTail) isFinished() bool {
+	select {
+	case <-t.finished:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *logTail) Close() error {
+	t.finish()
+	return t.file.Close()
+}
@@ -0,0 +1,465 @@
+package process
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/standardbeagle/brummer/pkg/events"
+)
+
+// With persist_processes enabled, process output goes to log files in a session
+// directory instead of pipes, so processes keep running if brummer exits
+// unexpectedly, and the metadata needed to find them again is saved next to the
+// logs. A later brummer for the same project can then adopt them.
+
+// sessionFileName is the process metadata file in a session directory
+const sessionFileName = "processes.json"
+
+const (
+	sessionSaveInterval = time.Second            // how often log offsets are saved
+	adoptedPollInterval = 500 * time.Millisecond // how often adopted processes are checked for exit
+)
+
+// persistedProcess is the saved state of a running process
+type persistedProcess struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Script       string    `json:"script"`
+	Argv         []string  `json:"argv"`
+	FromScript   bool      `json:"fromScript"`
+	Command      string    `json:"command,omitempty"`
+	Args         []string  `json:"args,omitempty"`
+	Dir          string    `json:"dir,omitempty"`
+	PID          int       `json:"pid"`
+	ProcStart    uint64    `json:"procStart,omitempty"` // guards against PID reuse where known
+	StartTime    time.Time `json:"startTime"`
+	StdoutLog    string    `json:"stdoutLog"`
+	StdoutOffset int64     `json:"stdoutOffset"`
+	StderrLog    string    `json:"stderrLog"`
+	StderrOffset int64     `json:"stderrOffset"`
+	Port         int       `json:"port,omitempty"` // assigned port, claimed again on adoption
+}
+
+// OrphanedProcess is a process started by an earlier brummer that is still running
+type OrphanedProcess struct {
+	ID        string
+	Name      string
+	PID       int
+	StartTime time.Time
+}
+
+// session holds the persisted state for this project
+type session struct {
+	dir       string
+	mu        sync.Mutex
+	orphans   []persistedProcess // still running from an earlier session, not yet adopted
+	lastSaved []byte
+	closed    bool
+	stop      chan struct{}
+}
+
+// OpenSession enables process persistence in dir when persist_processes is set,
+// and returns the processes from an earlier session that are still running
+func (m *Manager) OpenSession(dir string) ([]OrphanedProcess, error) {
+	if !m.config.GetPersistProcesses() || m.session != nil {
+		return nil, nil
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	records, err := readSession(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &session{dir: dir, stop: make(chan struct{})}
+	for _, rec := range records {
+		if processAlive(rec.PID, rec.ProcStart) {
+			s.orphans = append(s.orphans, rec)
+		} else {
+			removeLogs(rec.StdoutLog, rec.StderrLog)
+		}
+	}
+	m.session = s
+	m.saveSession()
+
+	go func() {
+		ticker := time.NewTicker(sessionSaveInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				m.saveSession()
+			}
+		}
+	}()
+
+	return m.Orphans(), nil
+}
+
+// Orphans returns the processes from an earlier session that can be adopted
+func (m *Manager) Orphans() []OrphanedProcess {
+	if m.session == nil {
+		return nil
+	}
+	m.session.mu.Lock()
+	defer m.session.mu.Unlock()
+
+	orphans := make([]OrphanedProcess, 0, len(m.session.orphans))
+	for _, rec := range m.session.orphans {
+		orphans = append(orphans, OrphanedProcess{ID: rec.ID, Name: rec.Name, PID: rec.PID, StartTime: rec.StartTime})
+	}
+	return orphans
+}
+
+// AdoptOrphans reattaches to the processes from an earlier session, resuming their
+// logs where the earlier brummer stopped reading
+func (m *Manager) AdoptOrphans() ([]*Process, error) {
+	if m.session == nil {
+		return nil, fmt.Errorf("process persistence is not enabled")
+	}
+	m.session.mu.Lock()
+	records := m.session.orphans
+	m.session.orphans = nil
+	m.session.mu.Unlock()
+
+	var adopted []*Process
+	var errs []string
+	for _, rec := range records {
+		if !processAlive(rec.PID, rec.ProcStart) {
+			removeLogs(rec.StdoutLog, rec.StderrLog)
+			continue
+		}
+		p, err := m.adopt(rec)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s (PID %d): %v", rec.Name, rec.PID, err))
+			continue
+		}
+		adopted = append(adopted, p)
+	}
+	m.saveSession()
+
+	if len(errs) > 0 {
+		return adopted, fmt.Errorf("failed to adopt %s", strings.Join(errs, "; "))
+	}
+	return adopted, nil
+}
+
+// KillOrphans stops the processes from an earlier session instead of adopting them
+func (m *Manager) KillOrphans() []OrphanedProcess {
+	if m.session == nil {
+		return nil
+	}
+	orphans := m.Orphans()
+
+	m.session.mu.Lock()
+	records := m.session.orphans
+	m.session.orphans = nil
+	m.session.mu.Unlock()
+
+	for _, rec := range records {
+		if processAlive(rec.PID, rec.ProcStart) {
+			m.killProcessTree(rec.PID, fmt.Sprintf("'%s' left running by a previous session", rec.Name))
+		}
+		removeLogs(rec.StdoutLog, rec.StderrLog)
+	}
+	m.saveSession()
+	return orphans
+}
+
+// adopt tracks a running process from an earlier session as if this manager had started it
+func (m *Manager) adopt(rec persistedProcess) (*Process, error) {
+	proc, err := os.FindProcess(rec.PID)
+	if err != nil {
+		return nil, err
+	}
+	output, err := tailOutput(rec.StdoutLog, rec.StdoutOffset, rec.StderrLog, rec.StderrOffset, m.config.GetLogs().GetSegmentSize())
+	if err != nil {
+		return nil, fmt.Errorf("cannot read its logs: %w", err)
+	}
+
+	dir := rec.Dir
+	if dir == "" {
+		dir = m.workDir
+	}
+	p := &Process{
+		ID:        rec.ID,
+		Name:      rec.Name,
+		Script:    rec.Script,
+		Cmd:       &exec.Cmd{Args: rec.Argv, Dir: dir, Process: proc},
+		Status:    StatusRunning,
+		StartTime: rec.StartTime,
+		spec:      startSpec{name: rec.Name, command: rec.Command, args: rec.Args, dir: rec.Dir, fromScript: rec.FromScript},
+		readyCh:   make(chan struct{}),
+		done:      make(chan struct{}),
+		output:    output,
+		procStart: rec.ProcStart,
+	}
+	m.claimPort(rec.Name, rec.Port)
+	p.assignedPort = m.AssignedPort(rec.Name)
+	m.processes.Store(p.ID, p)
+
+	data := map[string]interface{}{
+		"name":    p.Name,
+		"script":  p.Script,
+		"cmd":     p.Cmd.Args,
+		"adopted": true,
+	}
+	if p.assignedPort > 0 {
+		data["port"] = p.assignedPort
+	}
+	m.eventBus.Publish(events.Event{
+		Type:      events.ProcessStarted,
+		ProcessID: p.ID,
+		Data:      data,
+	})
+	m.setReady(p, "adopted")
+
+	go output.stream(m, p)
+	m.emitSystemLog(p.Name, fmt.Sprintf("♻️ Adopted '%s' (PID %d) from a previous session", p.Name, rec.PID), false)
+	m.startMetrics(p)
+	m.startWatch(p, m.config.GetScriptConfig(p.Name).GetWatch())
+	go m.monitorAdopted(p)
+
+	return p, nil
+}
+
+// monitorAdopted waits for an adopted process to exit. Only its parent can collect
+// the exit code, so the process is marked stopped once it is gone.
+func (m *Manager) monitorAdopted(p *Process) {
+	ticker := time.NewTicker(adoptedPollInterval)
+	for range ticker.C {
+		if !processAlive(p.Cmd.Process.Pid, p.procStart) {
+			break
+		}
+	}
+	ticker.Stop()
+
+	p.output.close()
+	removeOutputLogs(p.output)
+
+	p.mu.Lock()
+	exitedOnItsOwn := p.Status == StatusRunning
+	if exitedOnItsOwn {
+		now := time.Now()
+		p.Status = StatusStopped
+		p.EndTime = &now
+	}
+	p.mu.Unlock()
+	close(p.done)
+
+	if exitedOnItsOwn {
+		m.emitSystemLog(p.Name, fmt.Sprintf("ℹ️ Adopted process '%s' exited; its exit code is unknown", p.Name), false)
+		m.eventBus.Publish(events.Event{
+			Type:      events.ProcessExited,
+			ProcessID: p.ID,
+			Data: map[string]interface{}{
+				"name":    p.Name,
+				"status":  p.Status,
+				"adopted": true,
+			},
+		})
+	}
+	m.saveSession()
+}
+
+// logPaths returns fresh log file paths for a process in the session directory
+func (s *session) logPaths(p *Process) (stdout, stderr string) {
+	base := fmt.Sprintf("%s-%d", sanitizeFileName(p.ID), time.Now().UnixNano())
+	return filepath.Join(s.dir, base+".stdout.log"), filepath.Join(s.dir, base+".stderr.log")
+}
+
+// removeOutputLogs deletes the log files behind a finished process's output
+func removeOutputLogs(o *outputPipes) {
+	if stdoutPath, _, stderrPath, _, ok := o.logFiles(); ok {
+		removeLogs(stdoutPath, stderrPath)
+	}
+}
+
+// saveSession writes the state of running processes whose output is persisted
+func (m *Manager) saveSession() {
+	s := m.session
+	if s == nil {
+		return
+	}
+
+	var records []persistedProcess
+	m.processes.Range(func(key, value interface{}) bool {
+		p, ok := value.(*Process)
+		if !ok {
+			return true
+		}
+		if rec, ok := p.persistedState(); ok {
+			records = append(records, rec)
+		}
+		return true
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	records = append(records, s.orphans...)
+	if err := s.write(records); err != nil {
+		go m.emitSystemLog("system", fmt.Sprintf("⚠️ Failed to save process state: %v", err), true)
+	}
+}
+
+// closeSession saves only the orphans nobody adopted, so they are offered again
+// next time, once this session's processes have been stopped
+func (m *Manager) closeSession() {
+	s := m.session
+	if s == nil {
+		return
+	}
+	close(s.stop)
+
+	m.processes.Range(func(key, value interface{}) bool {
+		if p, ok := value.(*Process); ok {
+			p.mu.RLock()
+			output := p.output
+			p.mu.RUnlock()
+			removeOutputLogs(output)
+		}
+		return true
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	if len(s.orphans) == 0 {
+		os.Remove(filepath.Join(s.dir, sessionFileName))
+		return
+	}
+	s.write(s.orphans)
+}
+
+// persistedState returns the record to save for a running process with log file output
+func (p *Process) persistedState() (persistedProcess, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.Status != StatusRunning || p.Cmd == nil || p.Cmd.Process == nil {
+		return persistedProcess{}, false
+	}
+	stdoutPath, stdoutOffset, stderrPath, stderrOffset, ok := p.output.logFiles()
+	if !ok {
+		return persistedProcess{}, false
+	}
+
+	return persistedProcess{
+		ID:           p.ID,
+		Name:         p.Name,
+		Script:       p.Script,
+		Argv:         p.Cmd.Args,
+		FromScript:   p.spec.fromScript,
+		Command:      p.spec.command,
+		Args:         p.spec.args,
+		Dir:          p.spec.dir,
+		PID:          p.Cmd.Process.Pid,
+		ProcStart:    p.procStart,
+		StartTime:    p.StartTime,
+		StdoutLog:    stdoutPath,
+		StdoutOffset: stdoutOffset,
+		StderrLog:    stderrPath,
+		StderrOffset: stderrOffset,
+		Port:         p.assignedPort,
+	}, true
+}
+
+// write replaces the session file if the records changed. Callers hold s.mu.
+func (s *session) write(records []persistedProcess) error {
+	if records == nil {
+		records = []persistedProcess{}
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(data, s.lastSaved) {
+		return nil
+	}
+
+	path := filepath.Join(s.dir, sessionFileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	s.lastSaved = data
+	return nil
+}
+
+// readSession loads the records saved by an earlier brummer, if any
+func readSession(dir string) ([]persistedProcess, error) {
+	data, err := os.ReadFile(filepath.Join(dir, sessionFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var records []persistedProcess
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", sessionFileName, err)
+	}
+	return records, nil
+}
+
+// processAlive reports whether pid still refers to the process that started at
+// the given kernel start time
+func processAlive(pid int, procStart uint64) bool {
+	if pid <= 0 {
+		return false
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	if err := proc.Signal(syscall.Signal(0)); err != nil {
+		return false
+	}
+	if procStart == 0 {
+		return true
+	}
+	started, ok := procStartTime(pid)
+	return !ok || started == procStart
+}
+
+// removeLogs deletes log files and their rotated copies
+func removeLogs(paths ...string) {
+	for _, path := range paths {
+		if path != "" {
+			os.Remove(path)
+			os.Remove(rotatedLogPath(path))
+		}
+	}
+}
+
+// sanitizeFileName replaces characters that are awkward in file names
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
@@ -0,0 +1,193 @@
+//go:build !windows
+
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/standardbeagle/brummer/internal/config"
+	"github.com/standardbeagle/brummer/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lineRecorder collects log lines delivered to a manager's callbacks
+type lineRecorder struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (r *lineRecorder) record(processID, line string, isError bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, line)
+}
+
+func (r *lineRecorder) contains(text string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, line := range r.lines {
+		if strings.Contains(line, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func newPersistingManager(t *testing.T, workDir string) *Manager {
+	mgr, err := NewManager(workDir, events.NewEventBus(), false)
+	require.NoError(t, err)
+	persist := true
+	mgr.config = &config.Config{PersistProcesses: &persist}
+	return mgr
+}
+
+// TestAdoptProcessFromPreviousSession tests that a second manager finds a process
+// the first one left running and resumes its logs from the saved offset
+func TestAdoptProcessFromPreviousSession(t *testing.T) {
+	workDir := t.TempDir()
+	sessionDir := filepath.Join(t.TempDir(), "session")
+
+	first := newPersistingManager(t, workDir)
+	firstLines := &lineRecorder{}
+	first.RegisterLogCallback(firstLines.record)
+	orphans, err := first.OpenSession(sessionDir)
+	require.NoError(t, err)
+	assert.Empty(t, orphans)
+
+	proc, err := first.StartCommand("server", "sh", []string{"-c", "echo before; sleep 2; echo after; sleep 30"})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return firstLines.contains("before") }, 5*time.Second, 20*time.Millisecond)
+
+	// Simulate the first brummer dying: its state stays on disk, its process keeps running
+	first.saveSession()
+	first.session.mu.Lock()
+	first.session.closed = true
+	first.session.mu.Unlock()
+	defer first.Cleanup()
+
+	second := newPersistingManager(t, workDir)
+	secondLines := &lineRecorder{}
+	second.RegisterLogCallback(secondLines.record)
+	orphans, err = second.OpenSession(sessionDir)
+	require.NoError(t, err)
+	require.Len(t, orphans, 1)
+	assert.Equal(t, "server", orphans[0].Name)
+	assert.Equal(t, proc.Cmd.Process.Pid, orphans[0].PID)
+
+	adopted, err := second.AdoptOrphans()
+	require.NoError(t, err)
+	require.Len(t, adopted, 1)
+	assert.Equal(t, StatusRunning, adopted[0].GetStatus())
+	assert.Empty(t, second.Orphans())
+
+	require.Eventually(t, func() bool { return secondLines.contains("after") }, 5*time.Second, 20*time.Millisecond)
+	assert.False(t, secondLines.contains("before"), "output read by the first session should not be repeated")
+
+	require.NoError(t, second.StopProcessAndWait(adopted[0].ID, 5*time.Second))
+	require.NoError(t, second.Cleanup())
+	_, err = os.Stat(filepath.Join(sessionDir, sessionFileName))
+	assert.True(t, os.IsNotExist(err), "a clean shutdown should leave nothing to adopt")
+}
+
+// TestKillOrphans tests that orphans can be stopped instead of adopted
+func TestKillOrphans(t *testing.T) {
+	workDir := t.TempDir()
+	sessionDir := filepath.Join(t.TempDir(), "session")
+
+	first := newPersistingManager(t, workDir)
+	_, err := first.OpenSession(sessionDir)
+	require.NoError(t, err)
+	proc, err := first.StartCommand("worker", "sleep", []string{"30"})
+	require.NoError(t, err)
+	first.saveSession()
+	first.session.mu.Lock()
+	first.session.closed = true
+	first.session.mu.Unlock()
+	defer first.Cleanup()
+
+	second := newPersistingManager(t, workDir)
+	orphans, err := second.OpenSession(sessionDir)
+	require.NoError(t, err)
+	require.Len(t, orphans, 1)
+
+	killed := second.KillOrphans()
+	require.Len(t, killed, 1)
+	assert.Equal(t, proc.Cmd.Process.Pid, killed[0].PID)
+	assert.Empty(t, second.Orphans())
+
+	select {
+	case <-proc.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("orphan was not killed")
+	}
+	require.NoError(t, second.Cleanup())
+}
+
+// TestProcessAliveChecksStartTime tests that a reused PID is not mistaken for the
+// process that was saved
+func TestProcessAliveChecksStartTime(t *testing.T) {
+	assert.True(t, processAlive(os.Getpid(), 0))
+	assert.False(t, processAlive(0, 0))
+
+	started, ok := procStartTime(os.Getpid())
+	if !ok {
+		t.Skip("process start times are not available on this platform")
+	}
+	assert.True(t, processAlive(os.Getpid(), started))
+	assert.False(t, processAlive(os.Getpid(), started+1))
+}
+
+// TestLogTailRotates tests that a session log file is rotated once it has been read
+// past its size limit, and that output written afterwards is still read
+func TestLogTailRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.stdout.log")
+	writer, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	require.NoError(t, err)
+	defer writer.Close()
+
+	tail, err := openTail(path, 0, 16)
+	require.NoError(t, err)
+	defer tail.Close()
+
+	first := strings.Repeat("x", 19) + "\n"
+	_, err = writer.WriteString(first)
+	require.NoError(t, err)
+
+	buf := make([]byte, 64)
+	n, err := tail.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, first, string(buf[:n]))
+
+	read := make(chan string, 1)
+	go func() {
+		n, _ := tail.Read(buf)
+		read <- string(buf[:n])
+	}()
+
+	require.Eventually(t, func() bool {
+		info, err := os.Stat(path)
+		return err == nil && info.Size() == 0
+	}, 2*time.Second, 20*time.Millisecond)
+	rotated, err := os.ReadFile(rotatedLogPath(path))
+	require.NoError(t, err)
+	assert.Equal(t, first, string(rotated))
+
+	_, err = writer.WriteString("after\n")
+	require.NoError(t, err)
+	select {
+	case line := <-read:
+		assert.Equal(t, "after\n", line)
+	case <-time.After(2 * time.Second):
+		t.Fatal("output after rotation was not read")
+	}
+
+	removeLogs(path)
+	_, err = os.Stat(rotatedLogPath(path))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -4,6 +4,7 @@ package process
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/exec"
 	"strconv"
@@ -17,6 +18,51 @@ func setupProcessGroup(cmd *exec.Cmd) {
 	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 }
 
+// setupPTYSession replaces the process group setup for PTY processes. The process
+// becomes a session leader with the terminal as its controlling TTY, which also
+// makes it a process group leader, so process tree cleanup works unchanged.
+func setupPTYSession(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true}
+}
+
+// stopSignals are the signals a script's stop_signal may name
+var stopSignals = map[string]syscall.Signal{
+	"SIGTERM": syscall.SIGTERM,
+	"SIGINT":  syscall.SIGINT,
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGUSR1": syscall.SIGUSR1,
+	"SIGUSR2": syscall.SIGUSR2,
+	"SIGKILL": syscall.SIGKILL,
+}
+
+// signalProcessGroup sends a named signal such as SIGINT or INT to the process
+// group led by pid
+func signalProcessGroup(pid int, name string) error {
+	name = strings.ToUpper(name)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+	sig, ok := stopSignals[name]
+	if !ok {
+		return fmt.Errorf("unsupported stop signal %q", name)
+	}
+	return syscall.Kill(-pid, sig)
+}
+
+// processGroupAlive reports whether any process in the group led by pid is still running
+func processGroupAlive(pid int) bool {
+	// The signal check is cheap; only when the group still exists is /proc scanned,
+	// since the group also exists while its members are zombies
+	if syscall.Kill(-pid, 0) != nil {
+		return false
+	}
+	if members, ok := processGroupMembers(pid); ok {
+		return len(members) > 0
+	}
+	return true
+}
+
 // killProcessTree kills a process and all its children on Unix
 func killProcessTree(pid int) {
 	// First, try to kill all child processes recursively
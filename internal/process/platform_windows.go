@@ -21,6 +21,22 @@ func setupProcessGroup(cmd *exec.Cmd) {
 	cmd.SysProcAttr.CreationFlags = syscall.CREATE_NEW_PROCESS_GROUP
 }
 
+// setupPTYSession is unused on Windows, where PTY scripts fall back to pipes
+func setupPTYSession(cmd *exec.Cmd) {}
+
+// signalProcessGroup fails on Windows, which has no stop signals; stopping goes
+// straight to taskkill
+func signalProcessGroup(pid int, name string) error {
+	return fmt.Errorf("stop signals are not supported on Windows")
+}
+
+// processGroupAlive reports whether the process is still running
+func processGroupAlive(pid int) bool {
+	cmd := exec.Command("tasklist", "/FI", fmt.Sprintf("PID eq %d", pid))
+	output, err := cmd.Output()
+	return err == nil && strings.Contains(string(output), strconv.Itoa(pid))
+}
+
 // killProcessTree kills a process and all its children on Windows
 func killProcessTree(pid int) {
 	// Use taskkill with /T flag to kill the process tree
@@ -0,0 +1,422 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/standardbeagle/brummer/internal/discovery"
+	"github.com/standardbeagle/brummer/pkg/events"
+)
+
+// listenSocket is a TCP socket in LISTEN state
+type listenSocket struct {
+	port    int
+	address string
+	inode   uint64
+	pids    []int // processes holding the socket; empty when none of them can be read
+}
+
+// PortOwner describes a process listening on a TCP port
+type PortOwner struct {
+	Port        int           `json:"port"`
+	Address     string        `json:"address"`
+	PID         int           `json:"pid,omitempty"` // 0 when the socket belongs to a process of another user
+	Command     string        `json:"command,omitempty"`
+	ProcessID   string        `json:"processId,omitempty"` // set when this brummer started the process
+	ProcessName string        `json:"processName,omitempty"`
+	Instance    *PortInstance `json:"instance,omitempty"` // brummer instance that started the process, if any
+}
+
+// PortInstance identifies the brummer instance behind a listening process
+type PortInstance struct {
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Directory string `json:"directory"`
+	PID       int    `json:"pid"`
+	Self      bool   `json:"self"` // this instance
+}
+
+// Describe summarises the owner for a log line, e.g. PID 4242 (node server.js), 'api' in brummer at /src/shop
+func (o PortOwner) Describe() string {
+	if o.PID == 0 {
+		return "a process brummer cannot inspect"
+	}
+	desc := fmt.Sprintf("PID %d", o.PID)
+	if o.Command != "" {
+		desc += fmt.Sprintf(" (%s)", truncateCommand(o.Command, 60))
+	}
+	switch {
+	case o.Instance != nil && o.Instance.Self:
+		if o.ProcessName != "" {
+			desc += fmt.Sprintf(", '%s' in this brummer", o.ProcessName)
+		} else {
+			desc += ", started by this brummer"
+		}
+	case o.Instance != nil && o.ProcessName != "":
+		desc += fmt.Sprintf(", '%s' in brummer at %s", o.ProcessName, o.Instance.Directory)
+	case o.Instance != nil:
+		desc += fmt.Sprintf(", started by brummer at %s", o.Instance.Directory)
+	}
+	return desc
+}
+
+// ListeningPorts returns every listening TCP socket with its owner, sorted by port
+func (m *Manager) ListeningPorts() ([]PortOwner, error) {
+	return m.inspectPorts(0)
+}
+
+// InspectPort returns the processes listening on port. The result is empty when
+// nothing listens there.
+func (m *Manager) InspectPort(port int) ([]PortOwner, error) {
+	if port < 1 || port > 65535 {
+		return nil, fmt.Errorf("invalid port %d", port)
+	}
+	return m.inspectPorts(port)
+}
+
+// inspectPorts lists the owners of port, or of every port when port is 0
+func (m *Manager) inspectPorts(port int) ([]PortOwner, error) {
+	sockets, err := listeningSockets()
+	if err != nil {
+		return nil, err
+	}
+
+	// What ties a PID to brummer: processes this manager started, the discovery files
+	// of running instances, and the reservations behind assigned ports
+	managed := make(map[int]*Process)
+	m.processes.Range(func(key, value interface{}) bool {
+		if p, ok := value.(*Process); ok && p.GetStatus() == StatusRunning && p.Cmd != nil && p.Cmd.Process != nil {
+			managed[p.Cmd.Process.Pid] = p
+		}
+		return true
+	})
+	instances := m.brummerInstances()
+	reservations := make(map[int]discovery.PortReservation)
+	if list, err := discovery.ListPortReservations(m.ports.dir); err == nil {
+		for _, res := range list {
+			reservations[res.Port] = res
+		}
+	}
+
+	parents := processParents()
+
+	var owners []PortOwner
+	seen := make(map[string]bool)
+	for _, s := range sockets {
+		if port != 0 && s.port != port {
+			continue
+		}
+		pids := s.pids
+		if len(pids) == 0 {
+			pids = []int{0}
+		}
+		for _, pid := range pids {
+			key := fmt.Sprintf("%s|%d|%d", s.address, s.port, pid)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			owner := PortOwner{Port: s.port, Address: s.address, PID: pid}
+			if pid > 0 {
+				owner.Command = processCommand(pid)
+				m.resolveOwner(&owner, parents, managed, instances)
+			}
+			if res, ok := reservations[s.port]; ok && owner.Instance == nil {
+				owner.Instance = instanceFor(res.OwnerPID, res.WorkDir, instances)
+				if owner.ProcessName == "" {
+					owner.ProcessName = res.Name
+				}
+			}
+			owners = append(owners, owner)
+		}
+	}
+
+	sort.Slice(owners, func(i, j int) bool {
+		if owners[i].Port != owners[j].Port {
+			return owners[i].Port < owners[j].Port
+		}
+		return owners[i].PID < owners[j].PID
+	})
+	return owners, nil
+}
+
+// resolveOwner walks up from the owner's PID to the nearest process this manager
+// started and the brummer instance above it
+func (m *Manager) resolveOwner(owner *PortOwner, parents map[int]int, managed map[int]*Process, instances map[int]*PortInstance) {
+	for _, pid := range append([]int{owner.PID}, processAncestors(owner.PID, parents)...) {
+		if p, ok := managed[pid]; ok && owner.ProcessID == "" {
+			owner.ProcessID = p.ID
+			owner.ProcessName = p.Name
+		}
+		if inst, ok := instances[pid]; ok {
+			owner.Instance = inst
+			return
+		}
+	}
+	if owner.ProcessID != "" {
+		owner.Instance = instances[os.Getpid()]
+	}
+}
+
+// brummerInstances returns the running brummer instances keyed by PID, this one included
+func (m *Manager) brummerInstances() map[int]*PortInstance {
+	instances := map[int]*PortInstance{
+		os.Getpid(): {Directory: m.workDir, PID: os.Getpid(), Self: true},
+	}
+	ops := discovery.NewAtomicFileOperations(discovery.GetDefaultInstancesDir())
+	listed, err := ops.SafeListInstances()
+	if err != nil {
+		return instances
+	}
+	for _, inst := range listed {
+		pid := inst.ProcessInfo.PID
+		if pid <= 0 {
+			continue
+		}
+		if existing, ok := instances[pid]; ok {
+			existing.ID, existing.Name = inst.ID, inst.Name
+			continue
+		}
+		instances[pid] = &PortInstance{ID: inst.ID, Name: inst.Name, Directory: inst.Directory, PID: pid}
+	}
+	return instances
+}
+
+// instanceFor returns the instance with the given PID, or one built from a port
+// reservation when that instance is not registered for discovery
+func instanceFor(pid int, workDir string, instances map[int]*PortInstance) *PortInstance {
+	if inst, ok := instances[pid]; ok {
+		return inst
+	}
+	return &PortInstance{Directory: workDir, PID: pid}
+}
+
+// UnmanagedPortOwnerError is returned when a port is held by processes this brummer
+// did not start, which are only killed once the caller confirms with force
+type UnmanagedPortOwnerError struct {
+	Port   int
+	Owners []PortOwner
+}
+
+func (e *UnmanagedPortOwnerError) Error() string {
+	described := make([]string, len(e.Owners))
+	for i, owner := range e.Owners {
+		described[i] = owner.Describe()
+	}
+	return fmt.Sprintf("port %d is held by %s, which brummer did not start; killing it needs confirmation", e.Port, strings.Join(described, ", "))
+}
+
+// KillPortOwner stops whatever listens on port. Processes started by this brummer
+// are stopped as usual. Anything else has its process tree killed, but only with
+// force; without it an *UnmanagedPortOwnerError lists them and nothing is touched.
+// PID 1, brummer's own ancestors and processes of other users are never killed.
+// Processes that cannot be inspected are reported as an error.
+func (m *Manager) KillPortOwner(port int, force bool) ([]PortOwner, error) {
+	owners, err := m.InspectPort(port)
+	if err != nil {
+		return nil, err
+	}
+	if len(owners) == 0 {
+		return nil, fmt.Errorf("nothing is listening on port %d", port)
+	}
+
+	// Check every owner before touching any of them
+	ancestors := make(map[int]bool)
+	for _, pid := range processAncestors(os.Getpid(), processParents()) {
+		ancestors[pid] = true
+	}
+	var unmanaged []PortOwner
+	for _, owner := range owners {
+		if owner.PID <= 0 || owner.PID == os.Getpid() || owner.ProcessID != "" {
+			continue
+		}
+		if err := refuseKill(owner, ancestors); err != nil {
+			return nil, err
+		}
+		unmanaged = append(unmanaged, owner)
+	}
+	if len(unmanaged) > 0 && !force {
+		return nil, &UnmanagedPortOwnerError{Port: port, Owners: unmanaged}
+	}
+
+	var killed []PortOwner
+	var hidden bool
+	stopped := make(map[string]bool)
+	for _, owner := range owners {
+		switch {
+		case owner.PID == 0:
+			hidden = true
+			continue
+		case owner.PID == os.Getpid():
+			continue // brummer's own servers are never killed
+		case owner.ProcessID != "":
+			if !stopped[owner.ProcessID] {
+				stopped[owner.ProcessID] = true
+				if err := m.StopProcess(owner.ProcessID); err != nil {
+					return killed, err
+				}
+			}
+		default:
+			m.killProcessTree(owner.PID, fmt.Sprintf("holding port %d", port))
+		}
+		killed = append(killed, owner)
+	}
+	if len(killed) == 0 && hidden {
+		return nil, fmt.Errorf("port %d is held by a process brummer cannot inspect; it may belong to another user", port)
+	}
+	return killed, nil
+}
+
+// refuseKill explains why a process brummer did not start must not be killed, if it must not
+func refuseKill(owner PortOwner, ancestors map[int]bool) error {
+	switch {
+	case owner.PID == 1:
+		return fmt.Errorf("port %d is held by PID 1 (%s); brummer will not kill the init process", owner.Port, owner.Command)
+	case ancestors[owner.PID]:
+		return fmt.Errorf("port %d is held by %s, which brummer runs under; brummer will not kill it", owner.Port, owner.Describe())
+	}
+	uid, ok := processUID(owner.PID)
+	if !ok {
+		return fmt.Errorf("port %d is held by %s, whose owner cannot be read; brummer will not kill it", owner.Port, owner.Describe())
+	}
+	if uid != os.Getuid() {
+		return fmt.Errorf("port %d is held by %s, which belongs to another user; brummer will not kill it", owner.Port, owner.Describe())
+	}
+	return nil
+}
+
+// UseAnotherPort moves the named script or service to a new assigned port and starts
+// it again. The process must read its port from the environment, PORT unless the
+// script sets port_env.
+func (m *Manager) UseAnotherPort(name string) (int, error) {
+	var latest *Process
+	m.processes.Range(func(key, value interface{}) bool {
+		if p, ok := value.(*Process); ok && p.Name == name {
+			if latest == nil || p.GetStartTime().After(latest.GetStartTime()) {
+				latest = p
+			}
+		}
+		return true
+	})
+	_, isScript := m.GetScripts()[name]
+	if latest == nil && !isScript {
+		return 0, fmt.Errorf("no script, service or process named '%s'", name)
+	}
+
+	port, err := m.reassignPort(name)
+	if err != nil {
+		return 0, err
+	}
+
+	if latest != nil && latest.GetStatus() == StatusRunning {
+		if err := m.StopProcessAndWait(latest.ID, 5*time.Second); err != nil {
+			return 0, fmt.Errorf("failed to stop '%s': %w", name, err)
+		}
+	}
+	m.clearRestartState(name)
+	if latest != nil {
+		m.processes.Delete(latest.ID)
+	}
+
+	if latest == nil {
+		_, err = m.startScript(name)
+	} else {
+		_, err = m.relaunch(latest.spec)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return port, nil
+}
+
+// Port conflicts in process output: Node's EADDRINUSE, Go and Rust's "address already
+// in use", and the "port N is already in use" of many dev servers
+var (
+	portConflictPattern = regexp.MustCompile(`(?i)EADDRINUSE|address already in use|port \d+ is already in use|port is already allocated`)
+	conflictPortPattern = regexp.MustCompile(`(?i)\bport:? (\d{2,5})\b`)
+	conflictAddrPattern = regexp.MustCompile(`:(\d{2,5})\b`)
+)
+
+// detectPortConflict returns the port an output line reports as taken, or 0. Lines
+// that name no port fall back to the process's assigned port.
+func detectPortConflict(p *Process, line string) int {
+	line = stripANSI(line)
+	if !portConflictPattern.MatchString(line) {
+		return 0
+	}
+	if match := conflictPortPattern.FindStringSubmatch(line); match != nil {
+		port, _ := strconv.Atoi(match[1])
+		return port
+	}
+	// The last host:port on the line, so leading timestamps are skipped
+	if matches := conflictAddrPattern.FindAllStringSubmatch(line, -1); len(matches) > 0 {
+		port, _ := strconv.Atoi(matches[len(matches)-1][1])
+		return port
+	}
+	return p.AssignedPort()
+}
+
+// checkPortConflict reports the first port conflict a process prints
+func (m *Manager) checkPortConflict(p *Process, line string) {
+	port := detectPortConflict(p, line)
+	if port < 1 || port > 65535 {
+		return
+	}
+
+	p.mu.Lock()
+	reported := p.portConflict != 0
+	if !reported {
+		p.portConflict = port
+	}
+	p.mu.Unlock()
+	if !reported {
+		go m.reportPortConflict(p, port)
+	}
+}
+
+// reportPortConflict logs who holds the port and announces the conflict
+func (m *Manager) reportPortConflict(p *Process, port int) {
+	owners, err := m.InspectPort(port)
+	var holders []string
+	for _, owner := range owners {
+		if owner.ProcessID == p.ID {
+			continue
+		}
+		holders = append(holders, owner.Describe())
+	}
+
+	heldBy := strings.Join(holders, "; ")
+	switch {
+	case err != nil:
+		heldBy = fmt.Sprintf("an unknown process (%v)", err)
+	case len(holders) == 0:
+		heldBy = "a process that has since exited"
+	}
+	message := fmt.Sprintf("🔌 '%s' cannot listen on port %d: it is held by %s. Use /port kill %d to stop it, or /port reassign %s to move '%s' to another port",
+		p.Name, port, heldBy, port, p.Name, p.Name)
+	m.emitSystemLog(p.Name, message, true)
+
+	m.eventBus.Publish(events.Event{
+		Type:      events.ProcessPortConflict,
+		ProcessID: p.ID,
+		Data: map[string]interface{}{
+			"name":    p.Name,
+			"port":    port,
+			"owners":  owners,
+			"message": message,
+		},
+	})
+}
+
+func truncateCommand(command string, max int) string {
+	if len(command) <= max {
+		return command
+	}
+	return command[:max-3] + "..."
+}
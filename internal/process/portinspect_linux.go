@@ -0,0 +1,172 @@
+//go:build linux
+
+package process
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// tcpListen is the socket state of a listening socket in /proc/net/tcp
+const tcpListen = "0A"
+
+// listeningSockets reads /proc/net/tcp and /proc/net/tcp6 and returns the sockets
+// in LISTEN state along with the processes holding them
+func listeningSockets() ([]listenSocket, error) {
+	var sockets []listenSocket
+	var firstErr error
+	for _, file := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			if firstErr == nil && !os.IsNotExist(err) {
+				firstErr = err
+			}
+			continue
+		}
+		sockets = append(sockets, parseProcNetTCP(string(data))...)
+	}
+	if len(sockets) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+
+	inodes := make(map[uint64]bool, len(sockets))
+	for _, s := range sockets {
+		inodes[s.inode] = true
+	}
+	holders := socketHolders(inodes)
+	for i := range sockets {
+		sockets[i].pids = holders[sockets[i].inode]
+	}
+	return sockets, nil
+}
+
+// parseProcNetTCP parses the contents of /proc/net/tcp or /proc/net/tcp6 and
+// returns the listening sockets
+func parseProcNetTCP(data string) []listenSocket {
+	var sockets []listenSocket
+	for i, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) < 10 || fields[3] != tcpListen {
+			continue
+		}
+		address, port, err := parseHexAddress(fields[1])
+		if err != nil {
+			continue
+		}
+		inode, err := strconv.ParseUint(fields[9], 10, 64)
+		if err != nil {
+			continue
+		}
+		sockets = append(sockets, listenSocket{port: port, address: address, inode: inode})
+	}
+	return sockets
+}
+
+// parseHexAddress decodes an address such as 0100007F:0BB8. The kernel prints the
+// IP as 32-bit words read in host byte order.
+func parseHexAddress(field string) (string, int, error) {
+	hexIP, hexPort, ok := strings.Cut(field, ":")
+	if !ok || (len(hexIP) != 8 && len(hexIP) != 32) {
+		return "", 0, fmt.Errorf("malformed address %q", field)
+	}
+	port, err := strconv.ParseUint(hexPort, 16, 16)
+	if err != nil {
+		return "", 0, err
+	}
+	ip := make(net.IP, len(hexIP)/2)
+	for word := 0; word < len(ip); word += 4 {
+		value, err := strconv.ParseUint(hexIP[word*2:word*2+8], 16, 32)
+		if err != nil {
+			return "", 0, fmt.Errorf("malformed address %q", field)
+		}
+		binary.NativeEndian.PutUint32(ip[word:], uint32(value))
+	}
+	return ip.String(), int(port), nil
+}
+
+// socketHolders maps socket inodes to the processes with the socket open, by
+// reading the /proc/<pid>/fd links. Processes of other users are skipped, since
+// their descriptors cannot be read.
+func socketHolders(inodes map[uint64]bool) map[uint64][]int {
+	holders := make(map[uint64][]int)
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return holders
+	}
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		fdDir := filepath.Join("/proc", entry.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			inode, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]"), 10, 64)
+			if err != nil || !inodes[inode] {
+				continue
+			}
+			holders[inode] = appendUnique(holders[inode], pid)
+		}
+	}
+	return holders
+}
+
+// processParents maps every PID to its parent, read from /proc in one pass
+func processParents() map[int]int {
+	stats := readAllProcStats()
+	parents := make(map[int]int, len(stats))
+	for pid, st := range stats {
+		parents[pid] = st.ppid
+	}
+	return parents
+}
+
+// processAncestors returns the parent, grandparent and so on of pid
+func processAncestors(pid int, parents map[int]int) []int {
+	var ancestors []int
+	seen := map[int]bool{pid: true}
+	for {
+		ppid, ok := parents[pid]
+		if !ok || ppid <= 0 || seen[ppid] {
+			return ancestors
+		}
+		pid = ppid
+		seen[pid] = true
+		ancestors = append(ancestors, pid)
+	}
+}
+
+// processUID returns the user a process runs as
+func processUID(pid int) (int, bool) {
+	info, err := os.Stat(filepath.Join("/proc", strconv.Itoa(pid)))
+	if err != nil {
+		return 0, false
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return int(stat.Uid), true
+}
+
+func appendUnique(pids []int, pid int) []int {
+	for _, existing := range pids {
+		if existing == pid {
+			return pids
+		}
+	}
+	return append(pids, pid)
+}
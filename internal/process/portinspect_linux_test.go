@@ -0,0 +1,184 @@
+//go:build linux
+
+package process
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/standardbeagle/brummer/internal/config"
+	"github.com/standardbeagle/brummer/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProcNetTCP(t *testing.T) {
+	tcp := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:0BB8 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 255049 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 1054 1 0000000000000000 100 0 0 10 0
+   2: 0100007F:D950 0100007F:1F90 01 00000000:00000000 02:00000C00 00000000  1000        0 219151 2 0000000000000000 20 4 0 14 12
+`
+	tcp6 := `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000000000000:1538 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 3001 1 0000000000000000 100 0 0 10 0
+   1: 00000000000000000000000001000000:0050 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 3002 1 0000000000000000 100 0 0 10 0
+`
+	assert.Equal(t, []listenSocket{
+		{port: 3000, address: "0.0.0.0", inode: 255049},
+		{port: 8080, address: "127.0.0.1", inode: 1054},
+	}, parseProcNetTCP(tcp))
+	assert.Equal(t, []listenSocket{
+		{port: 5432, address: "::", inode: 3001},
+		{port: 80, address: "::1", inode: 3002},
+	}, parseProcNetTCP(tcp6))
+}
+
+// TestPortConflictIsReported tests that an EADDRINUSE line names the process holding
+// the port and that the owner can be looked up
+func TestPortConflictIsReported(t *testing.T) {
+	eventBus := events.NewEventBus()
+	mgr, err := NewManager(t.TempDir(), eventBus, false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	owners, err := mgr.InspectPort(port)
+	require.NoError(t, err)
+	require.Len(t, owners, 1)
+	assert.Equal(t, os.Getpid(), owners[0].PID)
+	assert.Equal(t, "127.0.0.1", owners[0].Address)
+	require.NotNil(t, owners[0].Instance)
+	assert.True(t, owners[0].Instance.Self)
+
+	// brummer never kills itself
+	killed, err := mgr.KillPortOwner(port, false)
+	require.NoError(t, err)
+	assert.Empty(t, killed)
+
+	conflicts := make(chan events.Event, 1)
+	eventBus.Subscribe(events.ProcessPortConflict, func(e events.Event) { conflicts <- e })
+
+	line := fmt.Sprintf("Error: listen EADDRINUSE: address already in use 127.0.0.1:%d", port)
+	_, err = mgr.StartCommand("api", "echo", []string{line})
+	require.NoError(t, err)
+
+	select {
+	case e := <-conflicts:
+		assert.Equal(t, port, e.Data["port"])
+		assert.Contains(t, e.Data["message"], fmt.Sprintf("held by PID %d", os.Getpid()))
+		assert.Contains(t, e.Data["message"], "/port reassign api")
+	case <-time.After(5 * time.Second):
+		t.Fatal("no port conflict was reported")
+	}
+}
+
+// TestUseAnotherPort tests that a script is restarted on a new assigned port
+func TestUseAnotherPort(t *testing.T) {
+	mgr, err := NewManager(t.TempDir(), events.NewEventBus(), false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+	mgr.ports.dir = t.TempDir()
+
+	mgr.config = &config.Config{
+		Ports: &config.PortsConfig{RangeStart: intPtr(43200), RangeEnd: intPtr(43299)},
+		Services: map[string]*config.ServiceConfig{
+			"api": {Command: "echo api on ${PORT:-none}"},
+		},
+	}
+	lines := &lineRecorder{}
+	mgr.RegisterLogCallback(lines.record)
+
+	proc, err := mgr.StartScript("api")
+	require.NoError(t, err)
+	<-proc.done
+	assert.True(t, lines.contains("api on none"))
+
+	port, err := mgr.UseAnotherPort("api")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return lines.contains(fmt.Sprintf("api on %d", port))
+	}, 5*time.Second, 20*time.Millisecond)
+
+	// Moving again picks a different port
+	next, err := mgr.UseAnotherPort("api")
+	require.NoError(t, err)
+	assert.NotEqual(t, port, next)
+
+	_, err = mgr.UseAnotherPort("missing")
+	assert.Error(t, err)
+}
+
+// TestKillPortOwnerNeedsForce tests that a process brummer did not start is only
+// killed once the caller confirms
+func TestKillPortOwnerNeedsForce(t *testing.T) {
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 is not available")
+	}
+	mgr, err := NewManager(t.TempDir(), events.NewEventBus(), false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	server := exec.Command(python, "-c", fmt.Sprintf(`import socket, time
+s = socket.socket()
+s.setsockopt(socket.SOL_SOCKET, socket.SO_REUSEADDR, 1)
+s.bind(("127.0.0.1", %d))
+s.listen()
+time.sleep(30)`, port))
+	require.NoError(t, server.Start())
+	exited := make(chan struct{})
+	go func() {
+		server.Wait()
+		close(exited)
+	}()
+	defer server.Process.Kill()
+
+	require.Eventually(t, func() bool {
+		owners, err := mgr.InspectPort(port)
+		return err == nil && len(owners) == 1
+	}, 5*time.Second, 50*time.Millisecond)
+
+	_, err = mgr.KillPortOwner(port, false)
+	var unmanaged *UnmanagedPortOwnerError
+	require.ErrorAs(t, err, &unmanaged)
+	require.Len(t, unmanaged.Owners, 1)
+	assert.Equal(t, server.Process.Pid, unmanaged.Owners[0].PID)
+	select {
+	case <-exited:
+		t.Fatal("the owner was killed without confirmation")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	killed, err := mgr.KillPortOwner(port, true)
+	require.NoError(t, err)
+	assert.Len(t, killed, 1)
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the owner was not killed")
+	}
+}
+
+// TestRefuseKill tests that init and brummer's own parents are never killed
+func TestRefuseKill(t *testing.T) {
+	ancestors := map[int]bool{os.Getppid(): true}
+	assert.Error(t, refuseKill(PortOwner{Port: 80, PID: 1}, ancestors))
+	assert.Error(t, refuseKill(PortOwner{Port: 80, PID: os.Getppid()}, ancestors))
+
+	cmd := exec.Command("sleep", "5")
+	require.NoError(t, cmd.Start())
+	defer cmd.Process.Kill()
+	assert.NoError(t, refuseKill(PortOwner{Port: 80, PID: cmd.Process.Pid}, ancestors))
+}
@@ -0,0 +1,25 @@
+//go:build !linux
+
+package process
+
+import "fmt"
+
+// listeningSockets reads /proc and is only available on Linux
+func listeningSockets() ([]listenSocket, error) {
+	return nil, fmt.Errorf("port inspection is not supported on this platform")
+}
+
+// processParents is unavailable without /proc
+func processParents() map[int]int {
+	return nil
+}
+
+// processAncestors is unavailable without /proc
+func processAncestors(pid int, parents map[int]int) []int {
+	return nil
+}
+
+// processUID is unavailable without /proc
+func processUID(pid int) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,24 @@
+package process
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectPortConflict(t *testing.T) {
+	cases := []struct {
+		line string
+		want int
+	}{
+		{"Error: listen EADDRINUSE: address already in use :::3000", 3000},
+		{"12:04:05 listen tcp 127.0.0.1:8080: bind: address already in use", 8080},
+		{"\x1b[31mError: Port 5173 is already in use\x1b[0m", 5173},
+		{"OSError: [Errno 98] Address already in use", 4321}, // falls back to the assigned port
+		{"Server listening on :3000", 0},
+	}
+	p := &Process{assignedPort: 4321}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, detectPortConflict(p, tc.line), tc.line)
+	}
+}
@@ -0,0 +1,179 @@
+package process
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/standardbeagle/brummer/internal/discovery"
+	"github.com/standardbeagle/brummer/pkg/ports"
+)
+
+// portAllocator tracks the ports this manager has handed out. A name keeps its port
+// for the manager's lifetime, so a restarted service comes back on the same one.
+type portAllocator struct {
+	mu       sync.Mutex
+	dir      string          // reservation directory shared by every instance
+	assigned map[string]int  // process name -> port
+	forced   map[string]bool // names moved to an assigned port after a conflict
+}
+
+// AssignedPort returns the port handed to the process with the given name, or 0
+func (m *Manager) AssignedPort(name string) int {
+	m.ports.mu.Lock()
+	defer m.ports.mu.Unlock()
+	return m.ports.assigned[name]
+}
+
+// AssignedPort returns the port injected into the process environment, or 0
+func (p *Process) AssignedPort() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.assignedPort
+}
+
+// wantsPort reports whether a process with the given name gets a port. A name moved
+// by UseAnotherPort always does; then the script's assign_port setting wins, and
+// otherwise services follow ports.assign.
+func (m *Manager) wantsPort(name string) bool {
+	m.ports.mu.Lock()
+	forced := m.ports.forced[name]
+	m.ports.mu.Unlock()
+	if forced {
+		return true
+	}
+	if assign, set := m.config.GetScriptConfig(name).GetAssignPort(); set {
+		return assign
+	}
+	if _, ok := m.GetService(name); ok {
+		return m.config.GetPorts().GetAssign()
+	}
+	return false
+}
+
+// assignPort returns the port for name, reserving one on first use. The search starts
+// at a port derived from the project and name, so the same service tends to get the
+// same port every time brummer runs.
+func (m *Manager) assignPort(name string) (int, error) {
+	a := &m.ports
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if port, ok := a.assigned[name]; ok {
+		return port, nil
+	}
+	return m.assignPortLocked(name, 0)
+}
+
+// reassignPort gives name a different port than the one it holds; the old one is
+// released once the new one is reserved
+func (m *Manager) reassignPort(name string) (int, error) {
+	a := &m.ports
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	old := a.assigned[name]
+	delete(a.assigned, name)
+	port, err := m.assignPortLocked(name, old)
+	if err != nil {
+		if old > 0 {
+			a.assigned[name] = old
+		}
+		return 0, err
+	}
+	if old > 0 {
+		discovery.ReleasePort(a.dir, old, os.Getpid())
+	}
+	if a.forced == nil {
+		a.forced = make(map[string]bool)
+	}
+	a.forced[name] = true
+	return port, nil
+}
+
+// assignPortLocked reserves a port for name other than skip; the caller holds ports.mu
+func (m *Manager) assignPortLocked(name string, skip int) (int, error) {
+	a := &m.ports
+	cfg := m.config.GetPorts()
+	start, end := cfg.GetRangeStart(), cfg.GetRangeEnd()
+	if start < 1 || end > 65535 || end < start {
+		return 0, fmt.Errorf("invalid port range %d-%d", start, end)
+	}
+	size := end - start + 1
+
+	taken := map[int]bool{skip: true}
+	for _, port := range a.assigned {
+		taken[port] = true
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(m.workDir + "\x00" + name))
+	offset := int(h.Sum32() % uint32(size))
+
+	for i := 0; i < size; i++ {
+		port := start + (offset+i)%size
+		if taken[port] {
+			continue
+		}
+		reserved, err := discovery.ReservePort(a.dir, m.portReservation(name, port))
+		if err != nil {
+			return 0, fmt.Errorf("failed to reserve port %d: %w", port, err)
+		}
+		if !reserved {
+			continue // held by another instance
+		}
+		if !ports.IsPortAvailable(port) {
+			discovery.ReleasePort(a.dir, port, os.Getpid())
+			continue
+		}
+		if a.assigned == nil {
+			a.assigned = make(map[string]int)
+		}
+		a.assigned[name] = port
+		return port, nil
+	}
+	return 0, fmt.Errorf("no free port between %d and %d", start, end)
+}
+
+// claimPort takes over the reservation of a port already in use by a process adopted
+// from an earlier session
+func (m *Manager) claimPort(name string, port int) {
+	if port <= 0 {
+		return
+	}
+	a := &m.ports
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if reserved, err := discovery.ReservePort(a.dir, m.portReservation(name, port)); err != nil || !reserved {
+		return
+	}
+	if a.assigned == nil {
+		a.assigned = make(map[string]int)
+	}
+	a.assigned[name] = port
+}
+
+// releasePorts drops every reservation this manager holds
+func (m *Manager) releasePorts() {
+	a := &m.ports
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, port := range a.assigned {
+		discovery.ReleasePort(a.dir, port, os.Getpid())
+	}
+	a.assigned = nil
+}
+
+func (m *Manager) portReservation(name string, port int) discovery.PortReservation {
+	return discovery.PortReservation{
+		Port:       port,
+		OwnerPID:   os.Getpid(),
+		WorkDir:    m.workDir,
+		Name:       name,
+		ReservedAt: time.Now(),
+	}
+}
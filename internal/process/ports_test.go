@@ -0,0 +1,96 @@
+//go:build !windows
+
+package process
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/standardbeagle/brummer/internal/config"
+	"github.com/standardbeagle/brummer/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAssignedPortIsInjected tests that a service gets a port in its environment,
+// keeps it across restarts and reports it in the start event
+func TestAssignedPortIsInjected(t *testing.T) {
+	eventBus := events.NewEventBus()
+	mgr, err := NewManager(t.TempDir(), eventBus, false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+	mgr.ports.dir = t.TempDir()
+
+	assign := true
+	mgr.config = &config.Config{
+		Ports: &config.PortsConfig{Assign: &assign, RangeStart: intPtr(43100), RangeEnd: intPtr(43199)},
+		Services: map[string]*config.ServiceConfig{
+			"api": {Command: "echo api on $PORT"},
+			"web": {Command: "echo web on $WEB_PORT"},
+		},
+		Scripts: map[string]*config.ScriptConfig{
+			"web": {PortEnv: stringPtr("WEB_PORT")},
+		},
+	}
+	started := make(chan int, 4)
+	eventBus.Subscribe(events.ProcessStarted, func(e events.Event) {
+		if port, ok := e.Data["port"].(int); ok {
+			started <- port
+		}
+	})
+	lines := &lineRecorder{}
+	mgr.RegisterLogCallback(lines.record)
+
+	proc, err := mgr.StartScript("api")
+	require.NoError(t, err)
+	<-proc.done
+	port := proc.AssignedPort()
+	require.True(t, port >= 43100 && port <= 43199, "port %d outside the range", port)
+	assert.True(t, lines.contains(fmt.Sprintf("api on %d", port)))
+	assert.Equal(t, port, <-started)
+
+	// A restart keeps the port
+	proc, err = mgr.StartScript("api")
+	require.NoError(t, err)
+	<-proc.done
+	assert.Equal(t, port, proc.AssignedPort())
+
+	web, err := mgr.StartScript("web")
+	require.NoError(t, err)
+	<-web.done
+	assert.NotEqual(t, port, web.AssignedPort())
+	assert.True(t, lines.contains(fmt.Sprintf("web on %d", web.AssignedPort())))
+
+	// Scripts without assign_port are left alone
+	plain, err := mgr.StartCommand("plain", "true", nil)
+	require.NoError(t, err)
+	<-plain.done
+	assert.Zero(t, plain.AssignedPort())
+}
+
+// TestAssignPortSkipsPortsInUse tests that a port something already listens on is
+// passed over
+func TestAssignPortSkipsPortsInUse(t *testing.T) {
+	mgr, err := NewManager(t.TempDir(), events.NewEventBus(), false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+	mgr.ports.dir = t.TempDir()
+
+	listener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer listener.Close()
+	busy := listener.Addr().(*net.TCPAddr).Port
+
+	// A one-port range holding a busy port has nothing to hand out
+	mgr.config = &config.Config{Ports: &config.PortsConfig{RangeStart: &busy, RangeEnd: &busy}}
+	_, err = mgr.assignPort("api")
+	assert.ErrorContains(t, err, "no free port")
+
+	end := busy + 1
+	mgr.config.Ports.RangeEnd = &end
+	port, err := mgr.assignPort("api")
+	require.NoError(t, err)
+	assert.Equal(t, end, port)
+	assert.Equal(t, end, mgr.AssignedPort("api"))
+}
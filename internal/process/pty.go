@@ -0,0 +1,126 @@
+package process
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/creack/pty"
+	"github.com/hinshun/vt10x"
+	"github.com/standardbeagle/brummer/pkg/events"
+)
+
+// Default size of a process terminal until a viewer resizes it
+const (
+	defaultPTYCols = 120
+	defaultPTYRows = 30
+)
+
+// ptyDrainTimeout bounds how long exit handling waits for buffered PTY output
+const ptyDrainTimeout = time.Second
+
+// ptySupported reports whether scripts can be run under a pseudo-terminal.
+// On Windows, scripts configured with pty = true fall back to pipes.
+var ptySupported = runtime.GOOS != "windows"
+
+// IsPTY reports whether the process is running under a pseudo-terminal
+func (p *Process) IsPTY() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.pty != nil
+}
+
+// Terminal returns the emulated screen of a PTY process, or nil for piped processes.
+// Callers must Lock the terminal while reading cells.
+func (p *Process) Terminal() vt10x.Terminal {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.terminal
+}
+
+// ResizeTerminal changes the size of a PTY process's terminal
+func (p *Process) ResizeTerminal(cols, rows int) error {
+	if cols <= 0 || rows <= 0 {
+		return fmt.Errorf("invalid terminal size %dx%d", cols, rows)
+	}
+
+	p.mu.RLock()
+	ptmx, terminal := p.pty, p.terminal
+	p.mu.RUnlock()
+
+	if ptmx == nil {
+		return fmt.Errorf("process '%s' is not running in a terminal", p.Name)
+	}
+	if err := pty.Setsize(ptmx, &pty.Winsize{Cols: uint16(cols), Rows: uint16(rows)}); err != nil {
+		return err
+	}
+	terminal.Resize(cols, rows)
+	return nil
+}
+
+// startPTY starts the process attached to a new pseudo-terminal and terminal emulator.
+// The command must have been set up with setupPTYSession.
+func (m *Manager) startPTY(p *Process) error {
+	ptmx, err := pty.StartWithSize(p.Cmd, &pty.Winsize{Cols: defaultPTYCols, Rows: defaultPTYRows})
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.pty = ptmx
+	// Replies to terminal queries (such as cursor position) go back to the process
+	p.terminal = vt10x.New(vt10x.WithSize(defaultPTYCols, defaultPTYRows), vt10x.WithWriter(ptmx))
+	p.ptyDone = make(chan struct{})
+	p.mu.Unlock()
+	return nil
+}
+
+// streamPTY feeds PTY output to the terminal emulator and splits it into log lines
+func (m *Manager) streamPTY(p *Process) {
+	p.mu.RLock()
+	ptmx, terminal, ptyDone := p.pty, p.terminal, p.ptyDone
+	p.mu.RUnlock()
+	defer close(ptyDone)
+
+	m.readOutput(p, ptmx, false, true, func(chunk []byte) {
+		terminal.Write(chunk)
+		m.eventBus.Publish(events.Event{
+			Type:      events.ProcessTerminal,
+			ProcessID: p.ID,
+			Data: map[string]interface{}{
+				"bytes": len(chunk),
+			},
+		})
+	}, ptyLogLine)
+}
+
+// ptyLogLine turns a raw terminal line into a log line. Carriage returns redraw the
+// line in place (progress bars, spinners), so only the last drawn text is kept.
+func ptyLogLine(raw string) string {
+	raw = strings.TrimRight(raw, "\r")
+	if idx := strings.LastIndexByte(raw, '\r'); idx >= 0 {
+		if last := raw[idx+1:]; strings.TrimSpace(last) != "" {
+			return last
+		}
+	}
+	return raw
+}
+
+// closePTY waits briefly for buffered output to be read, then releases the terminal
+func (p *Process) closePTY() {
+	p.mu.RLock()
+	ptmx, ptyDone := p.pty, p.ptyDone
+	p.mu.RUnlock()
+
+	if ptmx == nil {
+		return
+	}
+
+	// Background children can keep the terminal open after the main process exits
+	select {
+	case <-ptyDone:
+	case <-time.After(ptyDrainTimeout):
+	}
+	ptmx.Close()
+}
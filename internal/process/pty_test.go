@@ -0,0 +1,71 @@
+//go:build !windows
+
+package process
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/standardbeagle/brummer/internal/config"
+	"github.com/standardbeagle/brummer/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPTYProcess tests that pty = true gives the script a TTY, an emulated screen and keyboard input
+func TestPTYProcess(t *testing.T) {
+	mgr, err := NewManager(t.TempDir(), events.NewEventBus(), false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	usePTY := true
+	mgr.config = &config.Config{
+		Scripts: map[string]*config.ScriptConfig{
+			"interactive": {PTY: &usePTY},
+		},
+	}
+
+	var mu sync.Mutex
+	var lines []string
+	mgr.RegisterLogCallback(func(processID, line string, isError bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		lines = append(lines, line)
+	})
+	hasLine := func(want string) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, line := range lines {
+			if strings.Contains(line, want) {
+				return true
+			}
+		}
+		return false
+	}
+
+	proc, err := mgr.StartCommand("interactive", "sh", []string{"-c", `if [ -t 1 ]; then echo tty-yes; fi; read answer; echo "got:$answer"`})
+	require.NoError(t, err)
+	assert.True(t, proc.IsPTY())
+
+	require.Eventually(t, func() bool { return hasLine("tty-yes") }, 5*time.Second, 20*time.Millisecond)
+	require.NoError(t, proc.WriteInput([]byte("hello\r")))
+	require.Eventually(t, func() bool { return hasLine("got:hello") }, 5*time.Second, 20*time.Millisecond)
+
+	terminal := proc.Terminal()
+	require.NotNil(t, terminal)
+	screen := terminal.String()
+	assert.Contains(t, screen, "tty-yes")
+	assert.Contains(t, screen, "got:hello")
+
+	require.Eventually(t, func() bool { return proc.GetStatus() == StatusSuccess }, 5*time.Second, 20*time.Millisecond)
+	assert.Error(t, proc.WriteInput([]byte("late")))
+}
+
+// TestPTYLogLine tests that carriage-return redraws collapse to the final text
+func TestPTYLogLine(t *testing.T) {
+	assert.Equal(t, "done", ptyLogLine("done\r"))
+	assert.Equal(t, "100%", ptyLogLine("10%\r50%\r100%\r"))
+	assert.Equal(t, "plain", ptyLogLine("plain"))
+}
@@ -0,0 +1,269 @@
+package process
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/standardbeagle/brummer/internal/config"
+	"github.com/standardbeagle/brummer/pkg/events"
+)
+
+// readinessPollInterval is how often port and HTTP readiness checks are retried
+const readinessPollInterval = 250 * time.Millisecond
+
+// IsReady reports whether the process has passed its readiness check and its
+// readiness probe, if any, is not currently failing
+func (p *Process) IsReady() bool {
+	p.mu.RLock()
+	readyCh, probeNotReady := p.readyCh, p.probeNotReady
+	p.mu.RUnlock()
+
+	if readyCh == nil || probeNotReady {
+		return false
+	}
+	select {
+	case <-readyCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// markReady records that the process is ready; it reports false if it already was
+func (p *Process) markReady() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.readyCh == nil {
+		return false
+	}
+	select {
+	case <-p.readyCh:
+		return false
+	default:
+		close(p.readyCh)
+		return true
+	}
+}
+
+// matchesReadyLine reports whether a log line satisfies the log readiness pattern
+func (p *Process) matchesReadyLine(line string) bool {
+	p.mu.RLock()
+	pattern := p.readyPattern
+	p.mu.RUnlock()
+	return pattern != nil && pattern.MatchString(line)
+}
+
+// prepareReadiness sets up readiness tracking before the process starts
+func (m *Manager) prepareReadiness(p *Process, rc *config.ReadinessConfig) error {
+	var pattern *regexp.Regexp
+	if expr := rc.GetLogPattern(); expr != "" {
+		var err error
+		pattern, err = regexp.Compile(expr)
+		if err != nil {
+			return fmt.Errorf("invalid readiness log pattern for '%s': %w", p.Name, err)
+		}
+	}
+
+	p.mu.Lock()
+	p.readyCh = make(chan struct{})
+	p.done = make(chan struct{})
+	p.readyPattern = pattern
+	p.readyOnExit = rc.GetExit()
+	p.mu.Unlock()
+	return nil
+}
+
+// watchReadiness runs the probe-based readiness checks once the process is running.
+// probeGated means a health readiness probe will mark the process ready instead.
+func (m *Manager) watchReadiness(p *Process, rc *config.ReadinessConfig, probeGated bool) {
+	port := rc.GetPort()
+	httpURL := rc.GetHTTP()
+
+	// Without any checks configured, running is ready
+	if rc.GetLogPattern() == "" && port == 0 && httpURL == "" && !rc.GetExit() {
+		if !probeGated {
+			m.setReady(p, "running")
+		}
+		return
+	}
+	if port == 0 && httpURL == "" {
+		return // log and exit checks are driven by output and process exit
+	}
+
+	client := &http.Client{Timeout: 2 * time.Second}
+	ticker := time.NewTicker(readinessPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+		}
+
+		if p.IsReady() {
+			return
+		}
+		if port > 0 {
+			conn, err := net.DialTimeout("tcp", net.JoinHostPort("localhost", strconv.Itoa(port)), time.Second)
+			if err == nil {
+				conn.Close()
+				m.setReady(p, fmt.Sprintf("port %d", port))
+				return
+			}
+		}
+		if httpURL != "" {
+			resp, err := client.Get(httpURL)
+			if err == nil {
+				resp.Body.Close()
+				if resp.StatusCode == http.StatusOK {
+					m.setReady(p, httpURL)
+					return
+				}
+			}
+		}
+	}
+}
+
+// setReady marks the process ready and announces it once
+func (m *Manager) setReady(p *Process, reason string) {
+	if p.markReady() {
+		m.publishReady(p, reason)
+	}
+}
+
+// publishReady announces that a process is ready
+func (m *Manager) publishReady(p *Process, reason string) {
+	m.eventBus.Publish(events.Event{
+		Type:      events.ProcessReady,
+		ProcessID: p.ID,
+		Data: map[string]interface{}{
+			"name":   p.Name,
+			"reason": reason,
+		},
+	})
+}
+
+// waitForReady blocks until the process is ready, exits, or the timeout passes
+func (m *Manager) waitForReady(p *Process, timeout time.Duration) error {
+	p.mu.RLock()
+	readyCh, done := p.readyCh, p.done
+	p.mu.RUnlock()
+
+	if readyCh == nil {
+		return fmt.Errorf("process '%s' has no readiness tracking", p.Name)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-readyCh:
+		return nil
+	case <-done:
+		// Exit readiness is marked just before done closes
+		if p.IsReady() {
+			return nil
+		}
+		return fmt.Errorf("dependency '%s' exited before becoming ready", p.Name)
+	case <-timer.C:
+		return fmt.Errorf("timed out after %s waiting for dependency '%s' to become ready", timeout, p.Name)
+	}
+}
+
+// findProcessByName returns the most recently started process with the given name
+func (m *Manager) findProcessByName(name string) *Process {
+	var latest *Process
+	m.processes.Range(func(key, value interface{}) bool {
+		if proc, ok := value.(*Process); ok && proc.Name == name {
+			if latest == nil || proc.GetStartTime().After(latest.GetStartTime()) {
+				latest = proc
+			}
+		}
+		return true
+	})
+	return latest
+}
+
+// startDependencies starts everything name depends on, in order, waiting for each to be ready
+func (m *Manager) startDependencies(name string) error {
+	order, err := NewDependencyGraph(m.config).StartOrder(name)
+	if err != nil {
+		return err
+	}
+
+	// The last entry is the target itself
+	for _, dep := range order[:len(order)-1] {
+		rc := m.config.GetScriptConfig(dep).GetReadiness()
+
+		proc, err := m.ensureDependency(name, dep)
+		if err != nil {
+			return err
+		}
+		if proc == nil {
+			continue // already satisfied
+		}
+		if err := m.waitForReady(proc, rc.GetTimeout()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureDependency starts dep unless it is running. Concurrent starts of the same
+// dependency are serialized, so only the first one starts it and the others wait for
+// that process. It returns nil once dep is running and ready.
+func (m *Manager) ensureDependency(name, dep string) (*Process, error) {
+	lock, _ := m.dependencyLocks.LoadOrStore(dep, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	proc := m.findProcessByName(dep)
+	if proc != nil && (proc.IsReady() && (proc.GetStatus() == StatusRunning || proc.GetStatus() == StatusSuccess)) {
+		return nil, nil
+	}
+	if proc != nil && proc.GetStatus() == StatusRunning {
+		return proc, nil // started, not ready yet
+	}
+
+	m.emitSystemLog(name, fmt.Sprintf("⏳ Starting dependency '%s' for '%s'", dep, name), false)
+	if _, exists := m.GetScripts()[dep]; !exists {
+		return nil, fmt.Errorf("dependency '%s' of '%s' is not a known script or service", dep, name)
+	}
+	proc, err := m.startScript(dep)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start dependency '%s': %w", dep, err)
+	}
+	return proc, nil
+}
+
+// StopProcessWithDependents stops a process after first stopping every running script
+// that depends on it, furthest dependents first. It returns the names that were stopped.
+func (m *Manager) StopProcessWithDependents(processID string) ([]string, error) {
+	target, exists := m.GetProcess(processID)
+	if !exists {
+		return nil, fmt.Errorf("process %s not found", processID)
+	}
+
+	var stopped []string
+	for _, dependent := range NewDependencyGraph(m.config).Dependents(target.Name) {
+		for _, proc := range m.GetAllProcesses() {
+			if proc.Name == dependent && proc.GetStatus() == StatusRunning {
+				if err := m.StopProcess(proc.ID); err == nil {
+					stopped = append(stopped, dependent)
+				}
+			}
+		}
+	}
+
+	if err := m.StopProcess(processID); err != nil {
+		return stopped, err
+	}
+	return append(stopped, target.Name), nil
+}
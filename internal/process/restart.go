@@ -0,0 +1,229 @@
+package process
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/standardbeagle/brummer/internal/config"
+	"github.com/standardbeagle/brummer/pkg/events"
+)
+
+// RestartState describes the restart supervisor's view of a script
+type RestartState struct {
+	Policy      config.RestartPolicy
+	Restarts    int
+	MaxRetries  int
+	CrashLoop   bool
+	NextRestart *time.Time
+	LastExit    time.Time
+}
+
+// restartTracker holds mutable restart bookkeeping for a single script name
+type restartTracker struct {
+	state RestartState
+	timer *time.Timer
+}
+
+// startSpec records how a process was launched so it can be launched again
+type startSpec struct {
+	name       string
+	command    string
+	args       []string
+	dir        string // working directory of a command; empty for the project directory
+	fromScript bool
+}
+
+// relaunch starts a process again the way it was first started
+func (m *Manager) relaunch(spec startSpec) (*Process, error) {
+	if spec.fromScript {
+		return m.startScript(spec.name)
+	}
+	return m.startCommandIn(spec.name, spec.dir, spec.command, spec.args)
+}
+
+// GetRestartState returns the restart state for a script, if a restart policy is active
+func (m *Manager) GetRestartState(name string) (RestartState, bool) {
+	m.restartMu.Lock()
+	defer m.restartMu.Unlock()
+
+	tracker, exists := m.restarts[name]
+	if !exists {
+		return RestartState{}, false
+	}
+	return tracker.state, true
+}
+
+// shouldRestart reports whether a policy calls for a restart given the exit status
+func shouldRestart(policy config.RestartPolicy, status ProcessStatus) bool {
+	switch policy {
+	case config.RestartAlways:
+		return true
+	case config.RestartOnFailure:
+		return status == StatusFailed
+	default:
+		return false
+	}
+}
+
+// restartBackoff returns the delay before the given restart attempt (0-based)
+func restartBackoff(attempt int, initial, max time.Duration) time.Duration {
+	delay := initial
+	for i := 0; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
+
+// handleProcessExit applies the script's restart policy after the process has exited
+func (m *Manager) handleProcessExit(p *Process, spec startSpec, status ProcessStatus, uptime time.Duration) {
+	if p.isStopRequested() {
+		return
+	}
+
+	sc := m.config.GetScriptConfig(spec.name)
+	policy := sc.GetRestartPolicy()
+	if !shouldRestart(policy, status) {
+		return
+	}
+
+	// A process that stayed up long enough earns back its full retry budget
+	m.scheduleRestart(spec, sc, uptime >= sc.GetStableAfter())
+}
+
+// scheduleRestart arms a backoff timer that relaunches the process, or enters crash loop
+func (m *Manager) scheduleRestart(spec startSpec, sc *config.ScriptConfig, resetBudget bool) {
+	m.restartMu.Lock()
+
+	tracker, exists := m.restarts[spec.name]
+	if !exists {
+		tracker = &restartTracker{}
+		m.restarts[spec.name] = tracker
+	}
+	if resetBudget {
+		tracker.state.Restarts = 0
+	}
+
+	tracker.state.Policy = sc.GetRestartPolicy()
+	tracker.state.MaxRetries = sc.GetMaxRetries()
+	tracker.state.LastExit = time.Now()
+	tracker.state.NextRestart = nil
+
+	if tracker.state.Restarts >= tracker.state.MaxRetries {
+		tracker.state.CrashLoop = true
+		restarts := tracker.state.Restarts
+		m.restartMu.Unlock()
+
+		m.emitSystemLog(spec.name, fmt.Sprintf("🔁 Process '%s' is in a crash loop after %d restarts; not restarting", spec.name, restarts), true)
+		m.eventBus.Publish(events.Event{
+			Type: events.ProcessCrashLoop,
+			Data: map[string]interface{}{
+				"name":     spec.name,
+				"restarts": restarts,
+			},
+		})
+		return
+	}
+
+	delay := restartBackoff(tracker.state.Restarts, sc.GetRestartDelay(), sc.GetRestartMaxDelay())
+	tracker.state.Restarts++
+	tracker.state.CrashLoop = false
+	next := time.Now().Add(delay)
+	tracker.state.NextRestart = &next
+	attempt := tracker.state.Restarts
+	maxRetries := tracker.state.MaxRetries
+
+	if tracker.timer != nil {
+		tracker.timer.Stop()
+	}
+	tracker.timer = time.AfterFunc(delay, func() {
+		m.runScheduledRestart(spec, sc, tracker)
+	})
+	m.restartMu.Unlock()
+
+	m.emitSystemLog(spec.name, fmt.Sprintf("🔁 Restarting '%s' in %s (attempt %d/%d)", spec.name, delay, attempt, maxRetries), false)
+	m.eventBus.Publish(events.Event{
+		Type: events.ProcessRestarting,
+		Data: map[string]interface{}{
+			"name":    spec.name,
+			"attempt": attempt,
+			"delay":   delay.String(),
+		},
+	})
+}
+
+// runScheduledRestart relaunches a process once its backoff timer fires
+func (m *Manager) runScheduledRestart(spec startSpec, sc *config.ScriptConfig, tracker *restartTracker) {
+	m.restartMu.Lock()
+	// The restart was cancelled or superseded while we were waiting
+	if m.restarts[spec.name] != tracker || tracker.state.NextRestart == nil {
+		m.restartMu.Unlock()
+		return
+	}
+	tracker.state.NextRestart = nil
+	tracker.timer = nil
+	m.restartMu.Unlock()
+
+	// Drop the previous finished instance so the view shows one entry per script
+	m.processes.Range(func(key, value interface{}) bool {
+		if proc, ok := value.(*Process); ok && proc.Name == spec.name && proc.GetStatus() != StatusRunning {
+			m.processes.Delete(key)
+		}
+		return true
+	})
+
+	if _, err := m.relaunch(spec); err != nil {
+		m.emitSystemLog(spec.name, fmt.Sprintf("❌ Failed to restart '%s': %v", spec.name, err), true)
+		m.scheduleRestart(spec, sc, false)
+	}
+}
+
+// clearRestartState cancels any pending restart and forgets the retry history for a script
+func (m *Manager) clearRestartState(name string) {
+	m.restartMu.Lock()
+	defer m.restartMu.Unlock()
+
+	if tracker, exists := m.restarts[name]; exists {
+		if tracker.timer != nil {
+			tracker.timer.Stop()
+		}
+		delete(m.restarts, name)
+	}
+}
+
+// cancelAllRestarts stops every pending restart timer
+func (m *Manager) cancelAllRestarts() {
+	m.restartMu.Lock()
+	defer m.restartMu.Unlock()
+
+	for name, tracker := range m.restarts {
+		if tracker.timer != nil {
+			tracker.timer.Stop()
+		}
+		delete(m.restarts, name)
+	}
+}
+
+// emitSystemLog sends a supervisor message through the log callbacks, attributed to the named script
+func (m *Manager) emitSystemLog(name, message string, isError bool) {
+	var processID string
+	m.processes.Range(func(key, value interface{}) bool {
+		if proc, ok := value.(*Process); ok && proc.Name == name {
+			processID = proc.ID
+		}
+		return true
+	})
+	if processID == "" {
+		processID = name
+	}
+
+	m.mu.RLock()
+	callbacks := m.logCallbacks
+	m.mu.RUnlock()
+
+	for _, cb := range callbacks {
+		cb(processID, message, isError)
+	}
+}
@@ -0,0 +1,101 @@
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/standardbeagle/brummer/internal/config"
+	"github.com/standardbeagle/brummer/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stringPtr(s string) *string { return &s }
+
+// TestRestartBackoff tests exponential backoff with a ceiling
+func TestRestartBackoff(t *testing.T) {
+	initial := 100 * time.Millisecond
+	max := time.Second
+
+	assert.Equal(t, 100*time.Millisecond, restartBackoff(0, initial, max))
+	assert.Equal(t, 200*time.Millisecond, restartBackoff(1, initial, max))
+	assert.Equal(t, 800*time.Millisecond, restartBackoff(3, initial, max))
+	assert.Equal(t, time.Second, restartBackoff(4, initial, max))
+	assert.Equal(t, time.Second, restartBackoff(20, initial, max))
+}
+
+// TestShouldRestart tests policy decisions for each exit status
+func TestShouldRestart(t *testing.T) {
+	assert.False(t, shouldRestart(config.RestartNever, StatusFailed))
+	assert.True(t, shouldRestart(config.RestartOnFailure, StatusFailed))
+	assert.False(t, shouldRestart(config.RestartOnFailure, StatusSuccess))
+	assert.True(t, shouldRestart(config.RestartAlways, StatusSuccess))
+}
+
+// TestRestartPolicyCrashLoop tests that a failing command is restarted until its budget is spent
+func TestRestartPolicyCrashLoop(t *testing.T) {
+	eventBus := events.NewEventBus()
+	mgr, err := NewManager(t.TempDir(), eventBus, false)
+	require.NoError(t, err)
+
+	mgr.config = &config.Config{
+		Scripts: map[string]*config.ScriptConfig{
+			"crasher": {
+				Restart:        stringPtr("on-failure"),
+				MaxRetries:     intPtr(2),
+				RestartDelayMs: intPtr(10),
+			},
+		},
+	}
+
+	crashLoop := make(chan struct{}, 1)
+	eventBus.Subscribe(events.ProcessCrashLoop, func(e events.Event) {
+		crashLoop <- struct{}{}
+	})
+
+	_, err = mgr.StartCommand("crasher", "sh", []string{"-c", "exit 3"})
+	require.NoError(t, err)
+
+	select {
+	case <-crashLoop:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for crash loop")
+	}
+
+	state, ok := mgr.GetRestartState("crasher")
+	require.True(t, ok)
+	assert.True(t, state.CrashLoop)
+	assert.Equal(t, 2, state.Restarts)
+	assert.Equal(t, config.RestartOnFailure, state.Policy)
+
+	// A manual start clears the crash loop
+	_, err = mgr.StartCommand("crasher", "sh", []string{"-c", "exit 0"})
+	require.NoError(t, err)
+	_, ok = mgr.GetRestartState("crasher")
+	assert.False(t, ok)
+}
+
+// TestRestartSkippedAfterStop tests that a deliberate stop never triggers a restart
+func TestRestartSkippedAfterStop(t *testing.T) {
+	eventBus := events.NewEventBus()
+	mgr, err := NewManager(t.TempDir(), eventBus, false)
+	require.NoError(t, err)
+
+	mgr.config = &config.Config{
+		Scripts: map[string]*config.ScriptConfig{
+			"sleeper": {
+				Restart:        stringPtr("always"),
+				RestartDelayMs: intPtr(10),
+			},
+		},
+	}
+
+	proc, err := mgr.StartCommand("sleeper", "sleep", []string{"5"})
+	require.NoError(t, err)
+	require.NoError(t, mgr.StopProcess(proc.ID))
+
+	time.Sleep(300 * time.Millisecond)
+
+	_, ok := mgr.GetRestartState("sleeper")
+	assert.False(t, ok)
+}
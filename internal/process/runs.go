@@ -0,0 +1,222 @@
+package process
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/standardbeagle/brummer/pkg/events"
+)
+
+// defaultRunConcurrency is how many processes a matrix or task run starts at once
+const defaultRunConcurrency = 4
+
+// TaskSkipped is the status of a task that never ran because an upstream task failed
+const TaskSkipped ProcessStatus = "skipped"
+
+// RunResult is the outcome of one process of a matrix or task run
+type RunResult struct {
+	Name      string        `json:"name"` // the package, or package#task
+	Package   string        `json:"package"`
+	Dir       string        `json:"dir"`
+	DependsOn []string      `json:"dependsOn,omitempty"` // tasks that have to succeed first
+	ProcessID string        `json:"processId,omitempty"`
+	Status    ProcessStatus `json:"status"` // pending until the process has started
+	ExitCode  *int          `json:"exitCode,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	Error     string        `json:"error,omitempty"` // why the process did not run
+}
+
+// RunSummary aggregates the results of a matrix or task run
+type RunSummary struct {
+	ID       string        `json:"id"`
+	Target   string        `json:"target"` // the script, or package#task
+	Total    int           `json:"total"`
+	Passed   int           `json:"passed"`
+	Failed   int           `json:"failed"`
+	Skipped  int           `json:"skipped"`
+	Finished bool          `json:"finished"`
+	Duration time.Duration `json:"duration"`
+	Results  []RunResult   `json:"results"`
+
+	unit string // what the results are, "packages" or "tasks"
+}
+
+// Run is a script running in several workspace packages, or a Turborepo or Nx task
+// running after its upstream tasks, each result as its own process
+type Run struct {
+	ID        string
+	Target    string
+	StartTime time.Time
+
+	unit    string
+	mu      sync.Mutex
+	results []RunResult
+	endTime *time.Time
+	done    chan struct{} // closed once every process has finished or been skipped
+}
+
+func newRun(kind, target, unit string, results []RunResult) *Run {
+	return &Run{
+		ID:        fmt.Sprintf("%s-%s-%d", kind, target, time.Now().UnixNano()),
+		Target:    target,
+		StartTime: time.Now(),
+		unit:      unit,
+		results:   results,
+		done:      make(chan struct{}),
+	}
+}
+
+// Done is closed once every process has finished or been skipped
+func (r *Run) Done() <-chan struct{} {
+	return r.done
+}
+
+// Summary returns the results so far
+func (r *Run) Summary() RunSummary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	summary := RunSummary{
+		ID:       r.ID,
+		Target:   r.Target,
+		Total:    len(r.results),
+		Finished: r.endTime != nil,
+		Results:  append([]RunResult(nil), r.results...),
+		unit:     r.unit,
+	}
+	for _, res := range r.results {
+		switch res.Status {
+		case StatusSuccess:
+			summary.Passed++
+		case StatusFailed, StatusStopped:
+			summary.Failed++
+		case TaskSkipped:
+			summary.Skipped++
+		}
+	}
+	if r.endTime != nil {
+		summary.Duration = r.endTime.Sub(r.StartTime)
+	} else {
+		summary.Duration = time.Since(r.StartTime)
+	}
+	return summary
+}
+
+// String reports the summary on one line, naming what failed or was skipped
+func (s RunSummary) String() string {
+	if !s.Finished {
+		return fmt.Sprintf("'%s': %d of %d %s done", s.Target, s.Passed+s.Failed+s.Skipped, s.Total, s.unit)
+	}
+	text := fmt.Sprintf("'%s': %d of %d %s passed in %s", s.Target, s.Passed, s.Total, s.unit, s.Duration.Round(100*time.Millisecond))
+	var failed, skipped []string
+	for _, res := range s.Results {
+		switch {
+		case res.Status == TaskSkipped:
+			skipped = append(skipped, res.Name)
+		case res.Status != StatusSuccess && res.ExitCode != nil:
+			failed = append(failed, fmt.Sprintf("%s (exit %d)", res.Name, *res.ExitCode))
+		case res.Status != StatusSuccess:
+			failed = append(failed, fmt.Sprintf("%s (%s)", res.Name, res.Status))
+		}
+	}
+	if len(failed) > 0 {
+		text += "; failed: " + strings.Join(failed, ", ")
+	}
+	if len(skipped) > 0 {
+		text += "; skipped: " + strings.Join(skipped, ", ")
+	}
+	return text
+}
+
+// runCommand names the process for one result of a run and the command it runs
+type runCommand func(i int) (name, command string, args []string)
+
+// executeRun starts pending results, up to concurrency at once, once ready lets them,
+// and reports the aggregate result when none are left. ready is called with run.mu
+// held; it may mark a result skipped. A nil ready starts results in order.
+func (m *Manager) executeRun(run *Run, concurrency int, ready func(i int) bool, command runCommand, finished events.EventType) {
+	exited := make(chan struct{})
+	running := 0
+	for {
+		run.mu.Lock()
+		for i := range run.results {
+			if running >= concurrency {
+				break
+			}
+			if run.results[i].Status != StatusPending || (ready != nil && !ready(i)) {
+				continue
+			}
+			run.results[i].Status = StatusRunning
+			running++
+			go func(i int) {
+				m.runResult(run, i, command)
+				exited <- struct{}{}
+			}(i)
+		}
+		run.mu.Unlock()
+
+		if running == 0 {
+			break
+		}
+		<-exited
+		running--
+	}
+
+	now := time.Now()
+	run.mu.Lock()
+	run.endTime = &now
+	run.mu.Unlock()
+	close(run.done)
+
+	summary := run.Summary()
+	icon := "✅"
+	if summary.Failed > 0 {
+		icon = "❌"
+	}
+	m.emitSystemLog("system", fmt.Sprintf("%s %s", icon, summary), summary.Failed > 0)
+	m.eventBus.Publish(events.Event{
+		Type: finished,
+		Data: map[string]interface{}{
+			"id":      run.ID,
+			"target":  run.Target,
+			"passed":  summary.Passed,
+			"failed":  summary.Failed,
+			"skipped": summary.Skipped,
+			"message": summary.String(),
+		},
+	})
+}
+
+// runResult starts the process for one result and waits for it to exit
+func (m *Manager) runResult(run *Run, i int, command runCommand) {
+	run.mu.Lock()
+	dir := run.results[i].Dir
+	run.mu.Unlock()
+
+	name, cmd, args := command(i)
+	m.clearRestartState(name)
+	started := time.Now()
+	proc, err := m.startCommandIn(name, dir, cmd, args)
+	if err != nil {
+		run.mu.Lock()
+		run.results[i].Status = StatusFailed
+		run.results[i].Error = err.Error()
+		run.mu.Unlock()
+		m.emitSystemLog("system", fmt.Sprintf("❌ Failed to start '%s': %v", name, err), true)
+		return
+	}
+
+	run.mu.Lock()
+	run.results[i].ProcessID = proc.ID
+	run.mu.Unlock()
+
+	<-proc.done
+
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	run.results[i].Status = proc.GetStatus()
+	run.results[i].ExitCode = proc.GetExitCode()
+	run.results[i].Duration = time.Since(started)
+}
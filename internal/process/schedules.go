@@ -0,0 +1,200 @@
+package process
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/standardbeagle/brummer/internal/config"
+	"github.com/standardbeagle/brummer/internal/schedule"
+)
+
+// ScheduleState describes a recurring task declared under [schedules.<name>]
+type ScheduleState struct {
+	Name          string        `json:"name"`
+	Schedule      string        `json:"schedule"` // cron expression or "every <interval>"
+	Command       string        `json:"command"`
+	NextRun       *time.Time    `json:"nextRun,omitempty"`
+	LastRun       *time.Time    `json:"lastRun,omitempty"`
+	LastStatus    ProcessStatus `json:"lastStatus,omitempty"`
+	LastProcessID string        `json:"lastProcessId,omitempty"`
+	Runs          int           `json:"runs"`
+	Skipped       int           `json:"skipped"` // runs skipped because the previous one was still going
+	Disabled      bool          `json:"disabled,omitempty"`
+	Error         string        `json:"error,omitempty"` // why the schedule cannot run
+}
+
+// scheduledTask is the timer and history behind one schedule
+type scheduledTask struct {
+	name    string
+	sched   schedule.Schedule
+	command string
+	args    []string
+	timer   *time.Timer
+	last    *Process
+	state   ScheduleState
+}
+
+// StartSchedules arms a timer for every enabled schedule in the config. Schedules
+// that cannot be parsed are reported and listed with their error.
+func (m *Manager) StartSchedules() {
+	m.StopSchedules()
+
+	names := make([]string, 0)
+	if m.config != nil {
+		for name := range m.config.Schedules {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	m.scheduleMu.Lock()
+	defer m.scheduleMu.Unlock()
+
+	m.schedules = make(map[string]*scheduledTask, len(names))
+	for _, name := range names {
+		sc := m.config.Schedules[name]
+		task := &scheduledTask{
+			name:    name,
+			command: sc.Command,
+			args:    sc.Args,
+			state:   ScheduleState{Name: name, Command: strings.TrimSpace(sc.Command + " " + strings.Join(sc.Args, " "))},
+		}
+		m.schedules[name] = task
+
+		sched, err := parseSchedule(sc)
+		if err != nil {
+			task.state.Error = err.Error()
+			go m.emitSystemLog("system", fmt.Sprintf("❌ Schedule '%s' is invalid: %v", name, err), true)
+			continue
+		}
+		task.sched = sched
+		task.state.Schedule = sched.String()
+		if !sc.GetEnabled() {
+			task.state.Disabled = true
+			continue
+		}
+		m.armSchedule(task, time.Now())
+	}
+}
+
+// StopSchedules cancels every pending scheduled run; running processes are left alone
+func (m *Manager) StopSchedules() {
+	m.scheduleMu.Lock()
+	defer m.scheduleMu.Unlock()
+
+	for _, task := range m.schedules {
+		if task.timer != nil {
+			task.timer.Stop()
+		}
+	}
+	m.schedules = nil
+}
+
+// Schedules returns the state of every schedule, sorted by name
+func (m *Manager) Schedules() []ScheduleState {
+	m.scheduleMu.Lock()
+	lasts := make([]*Process, 0, len(m.schedules))
+	states := make([]ScheduleState, 0, len(m.schedules))
+	for _, task := range m.schedules {
+		lasts = append(lasts, task.last)
+		states = append(states, task.state)
+	}
+	m.scheduleMu.Unlock()
+
+	for i, last := range lasts {
+		if last != nil {
+			states[i].LastStatus = last.GetStatus()
+		}
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Name < states[j].Name })
+	return states
+}
+
+// parseSchedule reads the cron expression or interval of a schedule
+func parseSchedule(sc *config.ScheduleConfig) (schedule.Schedule, error) {
+	cronExpr, every := sc.GetCron(), sc.GetEvery()
+	switch {
+	case sc.Command == "":
+		return nil, fmt.Errorf("no command")
+	case cronExpr != "" && every != "":
+		return nil, fmt.Errorf("set either cron or every, not both")
+	case cronExpr != "":
+		return schedule.Parse(cronExpr)
+	case every != "":
+		d, err := time.ParseDuration(every)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval %q: %w", every, err)
+		}
+		return schedule.Every(d)
+	default:
+		return nil, fmt.Errorf("set cron or every")
+	}
+}
+
+// armSchedule sets the timer for the run after from; the caller holds scheduleMu
+func (m *Manager) armSchedule(task *scheduledTask, from time.Time) {
+	next := task.sched.Next(from)
+	if next.IsZero() {
+		task.state.NextRun = nil
+		return
+	}
+	task.state.NextRun = &next
+	task.timer = time.AfterFunc(time.Until(next), func() { m.runSchedule(task) })
+}
+
+// runSchedule starts a scheduled run unless the previous one is still going, then
+// arms the next run
+func (m *Manager) runSchedule(task *scheduledTask) {
+	m.scheduleMu.Lock()
+	if m.schedules[task.name] != task {
+		m.scheduleMu.Unlock()
+		return // stopped or replaced
+	}
+	previous := task.last
+	m.scheduleMu.Unlock()
+
+	now := time.Now()
+	var proc *Process
+	var err error
+	skipped := previous != nil && previous.GetStatus() == StatusRunning
+	if skipped {
+		m.emitSystemLog(task.name, fmt.Sprintf("⏭️ Skipped scheduled run of '%s': the previous run is still going", task.name), false)
+	} else {
+		// Keep one entry per schedule in the process list
+		m.processes.Range(func(key, value interface{}) bool {
+			if p, ok := value.(*Process); ok && p.Name == task.name && p.GetStatus() != StatusRunning {
+				m.processes.Delete(key)
+			}
+			return true
+		})
+
+		command, args := task.command, task.args
+		if len(args) == 0 {
+			command, args = shellCommand(task.command)
+		}
+		proc, err = m.StartCommand(task.name, command, args)
+		if err != nil {
+			m.emitSystemLog("system", fmt.Sprintf("❌ Scheduled run of '%s' failed to start: %v", task.name, err), true)
+		}
+	}
+
+	m.scheduleMu.Lock()
+	defer m.scheduleMu.Unlock()
+	if m.schedules[task.name] != task {
+		return
+	}
+	if skipped {
+		task.state.Skipped++
+	} else {
+		task.state.Runs++
+		task.state.LastRun = &now
+		task.last = proc
+		task.state.LastProcessID = ""
+		if proc != nil {
+			task.state.LastProcessID = proc.ID
+		}
+	}
+	m.armSchedule(task, now)
+}
@@ -0,0 +1,84 @@
+//go:build !windows
+
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/standardbeagle/brummer/internal/config"
+	"github.com/standardbeagle/brummer/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestScheduleSkipsOverlappingRuns tests that an interval schedule runs its command
+// and skips a tick while the previous run is still going
+func TestScheduleSkipsOverlappingRuns(t *testing.T) {
+	mgr, err := NewManager(t.TempDir(), events.NewEventBus(), false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	mgr.config = &config.Config{
+		Schedules: map[string]*config.ScheduleConfig{
+			"codegen": {Command: "echo generating; sleep 1.5", Every: stringPtr("1s")},
+		},
+	}
+	lines := &lineRecorder{}
+	mgr.RegisterLogCallback(lines.record)
+
+	mgr.StartSchedules()
+	schedules := mgr.Schedules()
+	require.Len(t, schedules, 1)
+	assert.Equal(t, "every 1s", schedules[0].Schedule)
+	require.NotNil(t, schedules[0].NextRun)
+	assert.Nil(t, schedules[0].LastRun)
+
+	require.Eventually(t, func() bool {
+		s := mgr.Schedules()[0]
+		return s.Runs >= 2 && s.Skipped >= 1
+	}, 10*time.Second, 50*time.Millisecond)
+
+	state := mgr.Schedules()[0]
+	require.NotNil(t, state.LastRun)
+	assert.NotEmpty(t, state.LastProcessID)
+	assert.True(t, lines.contains("generating"))
+	assert.True(t, lines.contains("Skipped scheduled run of 'codegen'"))
+
+	// Finished runs are replaced rather than piling up in the process list
+	var entries int
+	for _, proc := range mgr.GetAllProcesses() {
+		if proc.Name == "codegen" {
+			entries++
+		}
+	}
+	assert.Equal(t, 1, entries)
+
+	mgr.StopSchedules()
+	assert.Empty(t, mgr.Schedules())
+}
+
+// TestScheduleErrors tests that invalid and disabled schedules are listed but never run
+func TestScheduleErrors(t *testing.T) {
+	mgr, err := NewManager(t.TempDir(), events.NewEventBus(), false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	disabled := false
+	mgr.config = &config.Config{
+		Schedules: map[string]*config.ScheduleConfig{
+			"both":    {Command: "true", Cron: stringPtr("@daily"), Every: stringPtr("1h")},
+			"bad":     {Command: "true", Cron: stringPtr("61 * * * *")},
+			"cleanup": {Command: "true", Cron: stringPtr("0 6 * * *"), Enabled: &disabled},
+		},
+	}
+	mgr.StartSchedules()
+
+	schedules := mgr.Schedules()
+	require.Len(t, schedules, 3)
+	assert.Contains(t, schedules[0].Error, "outside 0-59")
+	assert.Contains(t, schedules[1].Error, "not both")
+	assert.True(t, schedules[2].Disabled)
+	assert.Equal(t, "0 6 * * *", schedules[2].Schedule)
+	assert.Nil(t, schedules[2].NextRun)
+}
@@ -0,0 +1,241 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/standardbeagle/brummer/internal/parser"
+)
+
+// procfileGroup is the group every Procfile entry belongs to, so `--group procfile`
+// starts everything the way foreman would
+const procfileGroup = "procfile"
+
+// Service is a named command declared under [services.<name>] in .brum.toml or in
+// the project's Procfile. Services start like package.json scripts, by name.
+type Service struct {
+	Name    string            `json:"name"`
+	Command string            `json:"command"`
+	Args    []string          `json:"args,omitempty"`
+	Cwd     string            `json:"cwd,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	Group   string            `json:"group,omitempty"`
+	Source  string            `json:"source"` // ".brum.toml" or "Procfile"
+}
+
+// CommandLine returns the service's command as it would be typed in a shell
+func (s Service) CommandLine() string {
+	if len(s.Args) == 0 {
+		return s.Command
+	}
+	return s.Command + " " + strings.Join(s.Args, " ")
+}
+
+// loadProcfile reads the Procfile in dir, if there is one
+func loadProcfile(dir string) ([]parser.ProcfileEntry, error) {
+	path := filepath.Join(dir, "Procfile")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+	return parser.ParseProcfile(path)
+}
+
+// GetService returns the service with the given name. A service declared in
+// .brum.toml replaces a Procfile entry with the same name.
+func (m *Manager) GetService(name string) (Service, bool) {
+	if sc := m.config.GetServiceConfig(name); sc != nil {
+		return Service{
+			Name:    name,
+			Command: sc.Command,
+			Args:    sc.Args,
+			Cwd:     sc.GetCwd(),
+			Env:     sc.GetEnv(),
+			Group:   sc.GetGroup(),
+			Source:  ".brum.toml",
+		}, true
+	}
+	for _, entry := range m.procfile {
+		if entry.Name == name {
+			return Service{Name: name, Command: entry.Command, Group: procfileGroup, Source: "Procfile"}, true
+		}
+	}
+	return Service{}, false
+}
+
+// Services returns every declared service, sorted by name
+func (m *Manager) Services() []Service {
+	names := make(map[string]bool)
+	for _, entry := range m.procfile {
+		names[entry.Name] = true
+	}
+	if m.config != nil {
+		for name := range m.config.Services {
+			names[name] = true
+		}
+	}
+
+	services := make([]Service, 0, len(names))
+	for name := range names {
+		if svc, ok := m.GetService(name); ok {
+			services = append(services, svc)
+		}
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	return services
+}
+
+// Groups returns the services in each group, sorted by name
+func (m *Manager) Groups() map[string][]string {
+	groups := make(map[string][]string)
+	for _, svc := range m.Services() {
+		if svc.Group != "" {
+			groups[svc.Group] = append(groups[svc.Group], svc.Name)
+		}
+	}
+	return groups
+}
+
+// StartGroup starts every service in a group that is not already running. Each one
+// starts as StartScript would, so depends_on is honoured across the group.
+func (m *Manager) StartGroup(group string) ([]*Process, error) {
+	members := m.Groups()[group]
+	if len(members) == 0 {
+		return nil, fmt.Errorf("group '%s' has no services", group)
+	}
+
+	var started []*Process
+	var errs []string
+	for _, name := range members {
+		if proc := m.findProcessByName(name); proc != nil && proc.GetStatus() == StatusRunning {
+			continue
+		}
+		proc, err := m.StartScript(name)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		started = append(started, proc)
+	}
+
+	if len(errs) > 0 {
+		return started, fmt.Errorf("failed to start %s", strings.Join(errs, "; "))
+	}
+	return started, nil
+}
+
+// StopGroup stops the running processes of every service in a group, in parallel.
+// It returns the names that were stopped.
+func (m *Manager) StopGroup(group string) ([]string, error) {
+	members := m.Groups()[group]
+	if len(members) == 0 {
+		return nil, fmt.Errorf("group '%s' has no services", group)
+	}
+	inGroup := make(map[string]bool, len(members))
+	for _, name := range members {
+		inGroup[name] = true
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		stopped []string
+		errs    []string
+	)
+	for _, proc := range m.GetAllProcesses() {
+		if !inGroup[proc.Name] || proc.GetStatus() != StatusRunning {
+			continue
+		}
+		wg.Add(1)
+		go func(p *Process) {
+			defer wg.Done()
+			err := m.StopProcess(p.ID)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", p.Name, err))
+			} else {
+				stopped = append(stopped, p.Name)
+			}
+		}(proc)
+	}
+	wg.Wait()
+
+	sort.Strings(stopped)
+	if len(errs) > 0 {
+		return stopped, fmt.Errorf("failed to stop %s", strings.Join(errs, "; "))
+	}
+	return stopped, nil
+}
+
+// startService launches a service; the caller resolves it by name so that a restart
+// picks up an edited definition
+func (m *Manager) startService(svc Service) (*Process, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	processID := fmt.Sprintf("%s-%d", svc.Name, time.Now().Unix())
+
+	// Without args the command is a shell command line, as in a Procfile
+	command, args := svc.Command, svc.Args
+	if len(args) == 0 {
+		command, args = shellCommand(svc.Command)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Dir = m.workDir
+	if svc.Cwd != "" {
+		if filepath.IsAbs(svc.Cwd) {
+			cmd.Dir = svc.Cwd
+		} else {
+			cmd.Dir = filepath.Join(m.workDir, svc.Cwd)
+		}
+	}
+
+	// Set up environment from the system, .env files, the service and script overrides
+	environ, err := m.buildProcessEnv(svc.Name)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to load environment for '%s': %w", svc.Name, err)
+	}
+	cmd.Env = environ
+
+	// Set process group for easier cleanup (platform-specific)
+	setupProcessGroup(cmd)
+
+	process := &Process{
+		ID:        processID,
+		Name:      svc.Name,
+		Script:    svc.CommandLine(),
+		Cmd:       cmd,
+		Status:    StatusPending,
+		StartTime: time.Now(),
+		cancel:    cancel,
+		spec:      startSpec{name: svc.Name, fromScript: true},
+	}
+
+	m.processes.Store(processID, process)
+
+	if err := m.runProcess(process); err != nil {
+		process.SetStatus(StatusFailed)
+		return nil, err
+	}
+
+	return process, nil
+}
+
+// shellCommand returns the command and arguments that run line in the platform shell
+func shellCommand(line string) (string, []string) {
+	if runtime.GOOS == "windows" {
+		return "cmd", []string{"/C", line}
+	}
+	return "sh", []string{"-c", line}
+}
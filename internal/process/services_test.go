@@ -0,0 +1,129 @@
+//go:build !windows
+
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/standardbeagle/brummer/internal/config"
+	"github.com/standardbeagle/brummer/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProcfileServices tests that Procfile entries become services in the procfile
+// group and that a service in .brum.toml replaces one with the same name
+func TestProcfileServices(t *testing.T) {
+	workDir := t.TempDir()
+	procfile := "# processes\nweb: echo web $PORT\n\nworker: echo from procfile\n"
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "Procfile"), []byte(procfile), 0644))
+
+	mgr, err := NewManager(workDir, events.NewEventBus(), false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	mgr.config = &config.Config{
+		Services: map[string]*config.ServiceConfig{
+			"worker": {Command: "echo", Args: []string{"from", "config"}, Group: stringPtr("backend")},
+		},
+	}
+
+	services := mgr.Services()
+	require.Len(t, services, 2)
+	assert.Equal(t, Service{Name: "web", Command: "echo web $PORT", Group: "procfile", Source: "Procfile"}, services[0])
+	assert.Equal(t, "config", services[1].Args[1])
+	assert.Equal(t, ".brum.toml", services[1].Source)
+
+	assert.Equal(t, map[string][]string{"procfile": {"web"}, "backend": {"worker"}}, mgr.Groups())
+	assert.Equal(t, "echo from config", mgr.GetScripts()["worker"])
+}
+
+// TestMalformedProcfileIsSkipped tests that a Procfile that cannot be parsed is
+// ignored rather than failing the manager
+func TestMalformedProcfileIsSkipped(t *testing.T) {
+	workDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "Procfile"), []byte("web: echo web\nnot a process line\n"), 0644))
+
+	mgr, err := NewManager(workDir, events.NewEventBus(), false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	mgr.config = &config.Config{}
+	assert.Empty(t, mgr.Services())
+}
+
+// TestStartServiceAppliesCwdAndEnv tests that a shell-line service runs in its cwd
+// with its env, under the script env overrides
+func TestStartServiceAppliesCwdAndEnv(t *testing.T) {
+	workDir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(workDir, "api"), 0755))
+
+	mgr, err := NewManager(workDir, events.NewEventBus(), false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	mgr.config = &config.Config{
+		Services: map[string]*config.ServiceConfig{
+			"api": {
+				Command: `echo "$GREETING $TARGET from $(basename "$PWD")"`,
+				Cwd:     stringPtr("api"),
+				Env:     map[string]string{"GREETING": "hello", "TARGET": "service"},
+			},
+		},
+		Scripts: map[string]*config.ScriptConfig{
+			"api": {Env: map[string]string{"TARGET": "script"}},
+		},
+	}
+	lines := &lineRecorder{}
+	mgr.RegisterLogCallback(lines.record)
+
+	proc, err := mgr.StartScript("api")
+	require.NoError(t, err)
+	<-proc.done
+
+	assert.Equal(t, StatusSuccess, proc.GetStatus())
+	assert.True(t, lines.contains("hello script from api"))
+}
+
+// TestStartAndStopGroup tests that a group starts its services once and stops them together
+func TestStartAndStopGroup(t *testing.T) {
+	mgr, err := NewManager(t.TempDir(), events.NewEventBus(), false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	mgr.config = &config.Config{
+		Services: map[string]*config.ServiceConfig{
+			"api":    {Command: "sleep", Args: []string{"30"}, Group: stringPtr("backend")},
+			"worker": {Command: "sleep 30", Group: stringPtr("backend")},
+			"web":    {Command: "sleep", Args: []string{"30"}, Group: stringPtr("frontend")},
+		},
+	}
+
+	started, err := mgr.StartGroup("backend")
+	require.NoError(t, err)
+	require.Len(t, started, 2)
+	assert.Equal(t, "api", started[0].Name)
+	assert.Equal(t, "worker", started[1].Name)
+
+	// Running services are not started twice
+	again, err := mgr.StartGroup("backend")
+	require.NoError(t, err)
+	assert.Empty(t, again)
+
+	stopped, err := mgr.StopGroup("backend")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api", "worker"}, stopped)
+	for _, proc := range started {
+		select {
+		case <-proc.done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s was not stopped", proc.Name)
+		}
+	}
+
+	_, err = mgr.StartGroup("missing")
+	assert.Error(t, err)
+}
@@ -0,0 +1,257 @@
+package process
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/standardbeagle/brummer/pkg/events"
+)
+
+// inputBufferSize is how many pending writes a process's stdin channel holds
+const inputBufferSize = 64
+
+// promptFlushDelay is how long an unterminated line must sit idle before it is
+// logged on its own, which is how prompts waiting for an answer become visible
+const promptFlushDelay = 300 * time.Millisecond
+
+// promptSuffixes mark an idle unterminated line as a question for the user
+var promptSuffixes = []string{"?", ":", ">", "›", "(y/n)", "[y/n]", "(yes/no)"}
+
+// looksLikePrompt reports whether an unterminated line is asking for input
+func looksLikePrompt(line string) bool {
+	trimmed := strings.ToLower(strings.TrimSpace(stripANSI(line)))
+	if trimmed == "" {
+		return false
+	}
+	for _, suffix := range promptSuffixes {
+		if strings.HasSuffix(trimmed, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// stripANSI removes terminal escape sequences so prompts can be matched on their text
+func stripANSI(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == 0x1b && i+1 < len(s) && s[i+1] == '[' {
+			// Skip to the final byte of the CSI sequence
+			j := i + 2
+			for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+				j++
+			}
+			i = j
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// WriteInput sends data to a running process: keystrokes to its terminal for PTY
+// processes, or bytes to its stdin otherwise. It does not block on a full stdin; an
+// error is returned if the process is not running, has no writable stdin, or too
+// much input is pending.
+func (p *Process) WriteInput(data []byte) error {
+	p.mu.Lock()
+	if p.Status != StatusRunning {
+		p.mu.Unlock()
+		return fmt.Errorf("process '%s' is not running", p.Name)
+	}
+	p.awaitingInput = ""
+	ptmx, inputCh, interactive := p.pty, p.inputCh, p.interactive
+	p.mu.Unlock()
+
+	// Write outside the lock: a full terminal buffer must not stall status readers
+	if ptmx != nil {
+		_, err := ptmx.Write(data)
+		return err
+	}
+	if !interactive {
+		return fmt.Errorf("process '%s' has no stdin; set stdin = true for the script to send it input", p.Name)
+	}
+	if inputCh == nil {
+		return fmt.Errorf("stdin of process '%s' is closed", p.Name)
+	}
+
+	// Copy so the caller may reuse its buffer
+	select {
+	case inputCh <- append([]byte(nil), data...):
+		return nil
+	default:
+		return fmt.Errorf("stdin of process '%s' is full; the process is not reading input", p.Name)
+	}
+}
+
+// CloseInput closes the process's stdin so it reads end-of-file. For PTY processes
+// it sends the terminal EOF character instead.
+func (p *Process) CloseInput() error {
+	p.mu.Lock()
+	if p.Status != StatusRunning {
+		p.mu.Unlock()
+		return fmt.Errorf("process '%s' is not running", p.Name)
+	}
+	p.awaitingInput = ""
+
+	if ptmx := p.pty; ptmx != nil {
+		p.mu.Unlock()
+		_, err := ptmx.Write([]byte{0x04})
+		return err
+	}
+	defer p.mu.Unlock()
+	if !p.interactive {
+		return fmt.Errorf("process '%s' has no stdin; set stdin = true for the script to send it input", p.Name)
+	}
+	if p.inputCh == nil {
+		return fmt.Errorf("stdin of process '%s' is already closed", p.Name)
+	}
+	close(p.inputCh)
+	p.inputCh = nil
+	return nil
+}
+
+// AwaitingInput returns the prompt a process is waiting on, or "" if it is not
+// known to be waiting for input
+func (p *Process) AwaitingInput() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.awaitingInput
+}
+
+// openInput connects a stdin pipe to a buffered channel before the process starts
+func (m *Manager) openInput(p *Process) (io.WriteCloser, chan []byte, error) {
+	stdin, err := p.Cmd.StdinPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	inputCh := make(chan []byte, inputBufferSize)
+	p.mu.Lock()
+	p.inputCh = inputCh
+	p.mu.Unlock()
+	return stdin, inputCh, nil
+}
+
+// forwardInput copies queued input to the process's stdin until it is closed or the process exits
+func (m *Manager) forwardInput(p *Process, stdin io.WriteCloser, inputCh <-chan []byte) {
+	defer stdin.Close()
+
+	p.mu.RLock()
+	done := p.done
+	p.mu.RUnlock()
+
+	for {
+		select {
+		case data, ok := <-inputCh:
+			if !ok {
+				return // CloseInput sends EOF
+			}
+			if _, err := stdin.Write(data); err != nil {
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// setAwaitingInput records that a process printed a prompt and announces it
+func (m *Manager) setAwaitingInput(p *Process, prompt string) {
+	prompt = strings.TrimSpace(stripANSI(prompt))
+
+	p.mu.Lock()
+	p.awaitingInput = prompt
+	p.mu.Unlock()
+
+	m.eventBus.Publish(events.Event{
+		Type:      events.ProcessAwaitingInput,
+		ProcessID: p.ID,
+		Data: map[string]interface{}{
+			"name":   p.Name,
+			"prompt": prompt,
+		},
+	})
+}
+
+// clearAwaitingInput forgets a pending prompt once the process prints more output
+func (p *Process) clearAwaitingInput() {
+	p.mu.Lock()
+	p.awaitingInput = ""
+	p.mu.Unlock()
+}
+
+// readOutput reads process output in chunks and emits complete lines. With
+// flushPartial, a trailing partial line that stays idle for promptFlushDelay is
+// emitted on its own, and marks the process as awaiting input if it looks like a
+// prompt; only processes that can be answered need this. onChunk, if set, sees the
+// raw bytes first.
+func (m *Manager) readOutput(p *Process, reader io.Reader, isError, flushPartial bool, onChunk func([]byte), toLine func(string) string) {
+	chunks := make(chan []byte)
+	go func() {
+		defer close(chunks)
+		buf := make([]byte, 4096)
+		for {
+			n, err := reader.Read(buf)
+			if n > 0 {
+				chunks <- append([]byte(nil), buf[:n]...)
+			}
+			if err != nil {
+				// Linux reports EIO on a PTY once the last writer has gone
+				return
+			}
+		}
+	}()
+
+	var pending strings.Builder
+	flush := time.NewTimer(promptFlushDelay)
+	flush.Stop()
+	defer flush.Stop()
+
+	for {
+		select {
+		case chunk, ok := <-chunks:
+			if !ok {
+				if pending.Len() > 0 {
+					m.emitLogLine(p, toLine(pending.String()), isError)
+				}
+				return
+			}
+			if onChunk != nil {
+				onChunk(chunk)
+			}
+			p.clearAwaitingInput()
+
+			pending.Write(chunk)
+			text := pending.String()
+			for {
+				idx := strings.IndexByte(text, '\n')
+				if idx < 0 {
+					break
+				}
+				m.emitLogLine(p, toLine(text[:idx]), isError)
+				text = text[idx+1:]
+			}
+			pending.Reset()
+			pending.WriteString(text)
+
+			flush.Stop()
+			if flushPartial && pending.Len() > 0 {
+				flush.Reset(promptFlushDelay)
+			}
+
+		case <-flush.C:
+			line := toLine(pending.String())
+			pending.Reset()
+			m.emitLogLine(p, line, isError)
+			if looksLikePrompt(line) {
+				m.setAwaitingInput(p, line)
+			}
+		}
+	}
+}
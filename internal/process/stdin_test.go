@@ -0,0 +1,111 @@
+//go:build !windows
+
+package process
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/standardbeagle/brummer/internal/config"
+	"github.com/standardbeagle/brummer/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStdinPromptAndAnswer tests that an unterminated prompt is surfaced and can be answered
+func TestStdinPromptAndAnswer(t *testing.T) {
+	eventBus := events.NewEventBus()
+	mgr, err := NewManager(t.TempDir(), eventBus, false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	useStdin := true
+	mgr.config = &config.Config{
+		Scripts: map[string]*config.ScriptConfig{
+			"migrate": {Stdin: &useStdin},
+		},
+	}
+
+	prompts := make(chan string, 1)
+	eventBus.Subscribe(events.ProcessAwaitingInput, func(e events.Event) {
+		prompt, _ := e.Data["prompt"].(string)
+		prompts <- prompt
+	})
+
+	var mu sync.Mutex
+	var lines []string
+	mgr.RegisterLogCallback(func(processID, line string, isError bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		lines = append(lines, line)
+	})
+
+	proc, err := mgr.StartCommand("migrate", "sh", []string{"-c", `printf "Apply 2 migrations? (y/n) "; read answer; echo "answer:$answer"`})
+	require.NoError(t, err)
+
+	select {
+	case prompt := <-prompts:
+		assert.Equal(t, "Apply 2 migrations? (y/n)", prompt)
+	case <-time.After(5 * time.Second):
+		t.Fatal("prompt was not detected")
+	}
+	assert.Equal(t, "Apply 2 migrations? (y/n)", proc.AwaitingInput())
+
+	require.NoError(t, proc.WriteInput([]byte("y\n")))
+	assert.Empty(t, proc.AwaitingInput())
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return strings.Contains(strings.Join(lines, "\n"), "answer:y")
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+// TestStdinCloseInput tests that closing stdin delivers end-of-file
+func TestStdinCloseInput(t *testing.T) {
+	mgr, err := NewManager(t.TempDir(), events.NewEventBus(), false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	useStdin := true
+	mgr.config = &config.Config{
+		Scripts: map[string]*config.ScriptConfig{
+			"cat": {Stdin: &useStdin},
+		},
+	}
+
+	proc, err := mgr.StartCommand("cat", "cat", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, proc.WriteInput([]byte("hello\n")))
+	require.NoError(t, proc.CloseInput())
+	assert.Error(t, proc.CloseInput())
+
+	require.Eventually(t, func() bool { return proc.GetStatus() == StatusSuccess }, 5*time.Second, 20*time.Millisecond)
+	assert.Error(t, proc.WriteInput([]byte("late\n")))
+}
+
+// TestStdinDefaultsToDevNull tests that scripts without stdin = true read end-of-file
+func TestStdinDefaultsToDevNull(t *testing.T) {
+	mgr, err := NewManager(t.TempDir(), events.NewEventBus(), false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	proc, err := mgr.StartCommand("cat", "cat", nil)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return proc.GetStatus() == StatusSuccess }, 5*time.Second, 20*time.Millisecond)
+	assert.Error(t, proc.WriteInput([]byte("hello\n")))
+}
+
+// TestLooksLikePrompt tests prompt detection on idle partial lines
+func TestLooksLikePrompt(t *testing.T) {
+	assert.True(t, looksLikePrompt("Port 3000 is in use, use 3001? "))
+	assert.True(t, looksLikePrompt("Password:"))
+	assert.True(t, looksLikePrompt("Continue [Y/n]"))
+	assert.True(t, looksLikePrompt("\x1b[1m? Pick a framework ›\x1b[0m"))
+	assert.False(t, looksLikePrompt("Compiling 42%"))
+	assert.False(t, looksLikePrompt("   "))
+}
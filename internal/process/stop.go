@@ -0,0 +1,152 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// terminationsCapacity is how many terminations are kept for the report
+const terminationsCapacity = 200
+
+// stopPollInterval is how often a stopping process group is checked for exit
+const stopPollInterval = 25 * time.Millisecond
+
+// Termination records a process that brummer signalled or killed, and why
+type Termination struct {
+	PID     int       `json:"pid"`
+	Command string    `json:"command,omitempty"`
+	Signal  string    `json:"signal,omitempty"` // empty when SIGTERM was followed by SIGKILL as needed
+	Reason  string    `json:"reason"`
+	Time    time.Time `json:"time"`
+}
+
+// terminationLog keeps the most recent terminations
+type terminationLog struct {
+	mu      sync.Mutex
+	entries []Termination
+}
+
+// Terminations returns the processes brummer has signalled or killed, oldest first
+func (m *Manager) Terminations() []Termination {
+	m.killLog.mu.Lock()
+	defer m.killLog.mu.Unlock()
+	return append([]Termination(nil), m.killLog.entries...)
+}
+
+// KillsDevProcesses reports whether stopping scripts also kills processes on dev
+// ports and with dev-server command lines
+func (m *Manager) KillsDevProcesses() bool {
+	return m.config.GetKillDevProcesses()
+}
+
+// recordTerminations notes that pids are about to be signalled. With announce set,
+// each one is also written to the logs; that is done for processes brummer did not
+// start, which would otherwise disappear without explanation.
+func (m *Manager) recordTerminations(pids []int, signal, reason string, announce bool) {
+	now := time.Now()
+	records := make([]Termination, 0, len(pids))
+	for _, pid := range pids {
+		records = append(records, Termination{
+			PID:     pid,
+			Command: processCommand(pid),
+			Signal:  signal,
+			Reason:  reason,
+			Time:    now,
+		})
+	}
+
+	m.killLog.mu.Lock()
+	m.killLog.entries = append(m.killLog.entries, records...)
+	if excess := len(m.killLog.entries) - terminationsCapacity; excess > 0 {
+		m.killLog.entries = m.killLog.entries[excess:]
+	}
+	m.killLog.mu.Unlock()
+
+	if announce {
+		for _, t := range records {
+			command := t.Command
+			if command == "" {
+				command = "unknown command"
+			}
+			m.emitSystemLog("system", fmt.Sprintf("🔪 Terminated PID %d (%s): %s", t.PID, command, t.Reason), false)
+		}
+	}
+}
+
+// stopProcessTree shuts a process down the way its script asks: the pre_stop hook
+// runs first, then the stop signal goes to the process group, and whatever is left
+// when the stop timeout runs out is killed
+func (m *Manager) stopProcessTree(p *Process, pid int) {
+	cfg := m.config.GetScriptConfig(p.Name)
+	timeout := cfg.GetStopTimeout()
+	deadline := time.Now().Add(timeout)
+
+	if hook := cfg.GetPreStop(); hook != "" {
+		m.runPreStop(p, hook, timeout)
+	}
+
+	signal := cfg.GetStopSignal()
+	members := groupMembers(pid)
+	reason := fmt.Sprintf("'%s' did not exit within %s of %s", p.Name, timeout, signal)
+	if err := signalProcessGroup(pid, signal); err != nil {
+		reason = fmt.Sprintf("stopping '%s': %v", p.Name, err)
+	} else {
+		m.recordTerminations(members, signal, fmt.Sprintf("stopping '%s'", p.Name), false)
+		for processGroupAlive(pid) && time.Now().Before(deadline) {
+			time.Sleep(stopPollInterval)
+		}
+		if !processGroupAlive(pid) {
+			return
+		}
+		m.emitSystemLog(p.Name, fmt.Sprintf("⚠️ '%s' did not exit within %s of %s; killing it", p.Name, timeout, signal), true)
+	}
+
+	// Escalate: the context kill covers the main process, the tree kill the rest
+	if p.cancel != nil {
+		p.cancel()
+	}
+	m.recordTerminations(groupMembers(pid), "SIGKILL", reason, false)
+	killProcessTree(pid)
+}
+
+// groupMembers lists the processes in the group led by pid, or pid alone where
+// groups cannot be inspected
+func groupMembers(pid int) []int {
+	if members, ok := processGroupMembers(pid); ok {
+		return members
+	}
+	return []int{pid}
+}
+
+// runPreStop runs a script's pre_stop hook in its environment and logs its output
+func (m *Manager) runPreStop(p *Process, hook string, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	shell, args := shellCommand(hook)
+	cmd := exec.CommandContext(ctx, shell, args...)
+	cmd.Dir = m.workDir
+	if environ, err := m.buildProcessEnv(p.Name); err == nil {
+		cmd.Env = environ
+	} else {
+		cmd.Env = os.Environ()
+	}
+
+	m.emitSystemLog(p.Name, fmt.Sprintf("🪝 Running pre_stop for '%s': %s", p.Name, hook), false)
+	output, err := cmd.CombinedOutput()
+	for _, line := range strings.Split(strings.TrimRight(string(output), "\n"), "\n") {
+		if line != "" {
+			m.emitSystemLog(p.Name, "pre_stop: "+line, false)
+		}
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		m.emitSystemLog(p.Name, fmt.Sprintf("⚠️ pre_stop for '%s' did not finish within %s", p.Name, timeout), true)
+	} else if err != nil {
+		m.emitSystemLog(p.Name, fmt.Sprintf("⚠️ pre_stop for '%s' failed: %v", p.Name, err), true)
+	}
+}
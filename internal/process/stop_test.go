@@ -0,0 +1,143 @@
+//go:build !windows
+
+package process
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/standardbeagle/brummer/internal/config"
+	"github.com/standardbeagle/brummer/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStopSignalAndPreStop tests that the pre_stop hook runs before the configured
+// stop signal, and that a process exiting cleanly on it is not killed
+func TestStopSignalAndPreStop(t *testing.T) {
+	workDir := t.TempDir()
+	mgr, err := NewManager(workDir, events.NewEventBus(), false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	mgr.config = &config.Config{
+		Scripts: map[string]*config.ScriptConfig{
+			"worker": {
+				StopSignal: stringPtr("SIGINT"),
+				PreStop:    stringPtr("echo flushed > flushed.txt"),
+			},
+		},
+	}
+	lines := &lineRecorder{}
+	mgr.RegisterLogCallback(lines.record)
+
+	proc, err := mgr.StartCommand("worker", "sh", []string{"-c", `trap 'echo interrupted; exit 0' INT; echo started; while true; do sleep 0.1; done`})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return lines.contains("started") }, 5*time.Second, 20*time.Millisecond)
+
+	require.NoError(t, mgr.StopProcess(proc.ID))
+	<-proc.done
+
+	data, err := os.ReadFile(filepath.Join(workDir, "flushed.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "flushed\n", string(data))
+	assert.True(t, lines.contains("interrupted"), "the process should see SIGINT")
+	assert.Equal(t, StatusStopped, proc.GetStatus())
+
+	var signals []string
+	for _, term := range mgr.Terminations() {
+		if term.PID == proc.Cmd.Process.Pid {
+			signals = append(signals, term.Signal)
+		}
+	}
+	assert.Equal(t, []string{"SIGINT"}, signals)
+}
+
+// TestStopTimeoutEscalates tests that a process ignoring its stop signal is killed
+// once the stop timeout runs out
+func TestStopTimeoutEscalates(t *testing.T) {
+	mgr, err := NewManager(t.TempDir(), events.NewEventBus(), false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	mgr.config = &config.Config{
+		Scripts: map[string]*config.ScriptConfig{
+			"stubborn": {StopTimeoutSeconds: intPtr(1)},
+		},
+	}
+	lines := &lineRecorder{}
+	mgr.RegisterLogCallback(lines.record)
+
+	proc, err := mgr.StartCommand("stubborn", "sh", []string{"-c", `trap '' TERM; echo started; while true; do sleep 0.1; done`})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return lines.contains("started") }, 5*time.Second, 20*time.Millisecond)
+
+	start := time.Now()
+	require.NoError(t, mgr.StopProcess(proc.ID))
+	elapsed := time.Since(start)
+	assert.GreaterOrEqual(t, elapsed, time.Second)
+	assert.Less(t, elapsed, 5*time.Second)
+
+	select {
+	case <-proc.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("process was not killed after the stop timeout")
+	}
+
+	var killed bool
+	for _, term := range mgr.Terminations() {
+		if term.PID == proc.Cmd.Process.Pid && term.Signal == "SIGKILL" {
+			killed = true
+			assert.Contains(t, term.Reason, "did not exit within 1s")
+		}
+	}
+	assert.True(t, killed, "the escalation should be reported")
+}
+
+// TestKillDevProcessesOptOut tests that turning off kill_dev_processes leaves
+// processes matching the dev-server patterns alone
+func TestKillDevProcessesOptOut(t *testing.T) {
+	mgr, err := NewManager(t.TempDir(), events.NewEventBus(), false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	off := false
+	mgr.config = &config.Config{KillDevProcesses: &off}
+
+	bystander := exec.Command("sh", "-c", "sleep 30 # webpack-dev-server")
+	require.NoError(t, bystander.Start())
+	defer func() {
+		bystander.Process.Kill()
+		bystander.Wait()
+	}()
+
+	mgr.KillProcessesByPort()
+	assert.True(t, processAlive(bystander.Process.Pid, 0))
+	assert.Empty(t, mgr.Terminations())
+}
+
+// TestKillProcessTreeReportsPIDs tests that killed processes are recorded with the reason
+func TestKillProcessTreeReportsPIDs(t *testing.T) {
+	mgr, err := NewManager(t.TempDir(), events.NewEventBus(), false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	target := exec.Command("sleep", "30")
+	require.NoError(t, target.Start())
+	go target.Wait()
+
+	mgr.killProcessTree(target.Process.Pid, "listening on dev port 3000")
+
+	terms := mgr.Terminations()
+	require.Len(t, terms, 1)
+	assert.Equal(t, target.Process.Pid, terms[0].PID)
+	assert.Equal(t, "listening on dev port 3000", terms[0].Reason)
+	assert.Eventually(t, func() bool { return !processAlive(target.Process.Pid, 0) }, 5*time.Second, 20*time.Millisecond)
+
+	// brummer never kills itself
+	mgr.killProcessTree(os.Getpid(), "command line matches \"go\"")
+	assert.Len(t, mgr.Terminations(), 1)
+}
@@ -0,0 +1,98 @@
+package process
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/standardbeagle/brummer/internal/parser"
+	"github.com/standardbeagle/brummer/pkg/events"
+)
+
+// IsTaskTarget reports whether name is written package#task
+func IsTaskTarget(name string) bool {
+	pkg, task, ok := strings.Cut(name, "#")
+	return ok && pkg != "" && task != ""
+}
+
+// GetTaskGraph returns the task pipeline from turbo.json or nx.json
+func (m *Manager) GetTaskGraph() (*parser.TaskGraph, error) {
+	return parser.LoadTaskGraph(m.workDir)
+}
+
+// PlanTask returns the tasks that running target, written package#task, takes
+// according to turbo.json or the Nx project graph, upstream tasks first
+func (m *Manager) PlanTask(target string) ([]parser.TaskNode, error) {
+	pkg, task, ok := strings.Cut(target, "#")
+	if !ok || pkg == "" || task == "" {
+		return nil, fmt.Errorf("task '%s' is not written package#task", target)
+	}
+	graph, err := m.GetTaskGraph()
+	if err != nil {
+		return nil, err
+	}
+	return graph.Plan(pkg, task)
+}
+
+// RunTask runs target, written package#task, after the tasks it depends on. Each
+// task runs as its own process named "<package>#<task>". Tasks whose dependencies
+// have succeeded run in parallel, up to concurrency at once; a failure skips
+// everything downstream of it. It returns once the run is set up.
+func (m *Manager) RunTask(target string, concurrency int) (*Run, error) {
+	nodes, err := m.PlanTask(target)
+	if err != nil {
+		return nil, err
+	}
+	if concurrency <= 0 {
+		concurrency = defaultRunConcurrency
+	}
+
+	results := make([]RunResult, len(nodes))
+	index := make(map[string]int, len(nodes))
+	for i, node := range nodes {
+		results[i] = RunResult{Name: node.ID, Package: node.Package, Dir: node.Dir, DependsOn: node.DependsOn, Status: StatusPending}
+		index[node.ID] = i
+	}
+	for _, node := range nodes {
+		for _, dep := range node.DependsOn {
+			if _, ok := index[dep]; !ok {
+				return nil, fmt.Errorf("task '%s' depends on '%s', which is not part of the plan", node.ID, dep)
+			}
+		}
+	}
+	run := newRun("task", target, "tasks", results)
+
+	if len(nodes) > 1 {
+		m.emitSystemLog("system", fmt.Sprintf("🔗 Running '%s' after %d upstream tasks", target, len(nodes)-1), false)
+	}
+	command := func(i int) (string, string, []string) {
+		node := nodes[i]
+		if node.Script != "" {
+			cmdArgs := m.GetCurrentPackageManager().RunScriptCommand(node.Script)
+			return node.ID, cmdArgs[0], cmdArgs[1:]
+		}
+		cmd, args := shellCommand(node.Command)
+		return node.ID, cmd, args
+	}
+	go m.executeRun(run, concurrency, func(i int) bool { return m.taskReady(run, index, i) }, command, events.TaskRunFinished)
+	return run, nil
+}
+
+// taskReady reports whether everything task i depends on has succeeded, and skips
+// it once anything it depends on has failed. Results are in dependency order, so
+// one pass over them settles every task. Called with run.mu held.
+func (m *Manager) taskReady(run *Run, index map[string]int, i int) bool {
+	res := &run.results[i]
+	for _, dep := range res.DependsOn {
+		switch run.results[index[dep]].Status {
+		case StatusSuccess:
+		case StatusFailed, StatusStopped, TaskSkipped:
+			res.Status = TaskSkipped
+			res.Error = fmt.Sprintf("'%s' did not succeed", dep)
+			go m.emitSystemLog("system", fmt.Sprintf("⏭️ Skipping '%s': %s", res.Name, res.Error), false)
+			return false
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,240 @@
+//go:build !windows
+
+package process
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standardbeagle/brummer/internal/parser"
+	"github.com/standardbeagle/brummer/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTurboWorkspace creates a Turborepo where web depends on ui and lib, and ui
+// on lib. Each build appends the package name to order.txt at the root.
+func writeTurboWorkspace(t *testing.T, uiBuild string) string {
+	dir := t.TempDir()
+	files := map[string]string{
+		"package.json": `{"name": "root", "workspaces": ["packages/*"]}`,
+		"turbo.json": `{
+			// builds wait for the builds of their dependencies
+			"pipeline": {
+				"build": {"dependsOn": ["^build"]},
+				"web#build": {"dependsOn": ["^build", "lint"]}
+			}
+		}`,
+		"packages/lib/package.json": `{"name": "lib", "scripts": {"build": "echo lib >> ../../order.txt"}}`,
+		"packages/ui/package.json":  fmt.Sprintf(`{"name": "ui", "dependencies": {"lib": "*", "react": "*"}, "scripts": {"build": %q}}`, uiBuild),
+		"packages/web/package.json": `{"name": "web", "dependencies": {"ui": "*"}, "devDependencies": {"lib": "*"},
+			"scripts": {"build": "echo web >> ../../order.txt", "lint": "echo lint >> ../../order.txt"}}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func waitForTaskRun(t *testing.T, run *Run) RunSummary {
+	select {
+	case <-run.Done():
+	case <-time.After(60 * time.Second):
+		t.Fatal("task run did not finish")
+	}
+	return run.Summary()
+}
+
+// TestRunTaskRunsUpstreamFirst tests that a turbo task runs after the tasks of the
+// packages it depends on, each as its own process
+func TestRunTaskRunsUpstreamFirst(t *testing.T) {
+	if _, err := exec.LookPath("npm"); err != nil {
+		t.Skip("npm is not installed")
+	}
+	dir := writeTurboWorkspace(t, "echo ui >> ../../order.txt")
+	mgr, err := NewManager(dir, events.NewEventBus(), false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	nodes, err := mgr.PlanTask("web#build")
+	require.NoError(t, err)
+	var ids []string
+	for _, node := range nodes {
+		ids = append(ids, node.ID)
+	}
+	require.Equal(t, []string{"lib#build", "ui#build", "web#lint", "web#build"}, ids)
+	assert.ElementsMatch(t, []string{"ui#build", "lib#build", "web#lint"}, nodes[3].DependsOn)
+
+	run, err := mgr.RunTask("web#build", 4)
+	require.NoError(t, err)
+	summary := waitForTaskRun(t, run)
+	assert.Equal(t, 4, summary.Passed)
+	assert.Equal(t, 0, summary.Failed)
+
+	order, err := os.ReadFile(filepath.Join(dir, "order.txt"))
+	require.NoError(t, err)
+	lines := strings.Fields(string(order))
+	require.Len(t, lines, 4)
+	// web#lint has no upstream tasks, so only the order along each edge is fixed
+	position := func(name string) int {
+		i := slices.Index(lines, name)
+		require.GreaterOrEqual(t, i, 0, name)
+		return i
+	}
+	assert.Less(t, position("lib"), position("ui"))
+	assert.Less(t, position("ui"), position("web"))
+	assert.Less(t, position("lint"), position("web"))
+
+	proc, ok := mgr.GetProcess(summary.Results[0].ProcessID)
+	require.True(t, ok)
+	assert.Equal(t, "lib#build", proc.Name)
+}
+
+// TestRunTaskSkipsDownstreamOfFailure tests that a failed task stops the tasks that
+// depend on it while unrelated tasks still run
+func TestRunTaskSkipsDownstreamOfFailure(t *testing.T) {
+	if _, err := exec.LookPath("npm"); err != nil {
+		t.Skip("npm is not installed")
+	}
+	dir := writeTurboWorkspace(t, "exit 2")
+	eventBus := events.NewEventBus()
+	mgr, err := NewManager(dir, eventBus, false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	finished := make(chan events.Event, 1)
+	eventBus.Subscribe(events.TaskRunFinished, func(e events.Event) { finished <- e })
+
+	run, err := mgr.RunTask("web#build", 1)
+	require.NoError(t, err)
+	summary := waitForTaskRun(t, run)
+
+	statuses := make(map[string]ProcessStatus)
+	for _, task := range summary.Results {
+		statuses[task.Name] = task.Status
+	}
+	assert.Equal(t, StatusSuccess, statuses["lib#build"])
+	assert.Equal(t, StatusFailed, statuses["ui#build"])
+	assert.Equal(t, StatusSuccess, statuses["web#lint"])
+	assert.Equal(t, TaskSkipped, statuses["web#build"])
+	assert.Contains(t, summary.String(), "failed: ui#build (exit 2); skipped: web#build")
+
+	select {
+	case e := <-finished:
+		assert.Equal(t, 1, e.Data["skipped"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("no task.finished event")
+	}
+}
+
+// TestNxTaskGraph tests that Nx targets come from project.json and nx.json target
+// defaults, and that run-commands targets run their command
+func TestNxTaskGraph(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"nx.json": `{"targetDefaults": {"build": {"dependsOn": ["^build"]}}}`,
+		"libs/core/project.json": `{"name": "core", "targets": {
+			"build": {"executor": "nx:run-commands", "options": {"commands": ["echo core >> ../../order.txt"]}}
+		}}`,
+		"apps/api/project.json": `{"name": "api", "implicitDependencies": ["core"], "targets": {
+			"build": {"command": "echo api >> ../../order.txt", "dependsOn": ["^build", {"target": "check", "projects": "self"}]},
+			"check": {"command": "echo check >> ../../order.txt"}
+		}}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	graph, err := parser.LoadTaskGraph(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "nx", graph.Tool)
+	assert.Equal(t, []string{"api", "core"}, graph.Projects())
+
+	mgr, err := NewManager(dir, events.NewEventBus(), false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	_, err = mgr.PlanTask("api#deploy")
+	assert.Error(t, err)
+
+	run, err := mgr.RunTask("api#build", 0)
+	require.NoError(t, err)
+	summary := waitForTaskRun(t, run)
+	assert.Equal(t, 3, summary.Passed)
+
+	order, err := os.ReadFile(filepath.Join(dir, "order.txt"))
+	require.NoError(t, err)
+	lines := strings.Fields(string(order))
+	require.Len(t, lines, 3)
+	assert.Equal(t, "api", lines[2])
+}
+
+// TestTaskGraphRejectsUnknownDependency tests that a dependsOn entry naming a
+// package or task that does not exist fails when the graph is loaded
+func TestTaskGraphRejectsUnknownDependency(t *testing.T) {
+	for name, dependsOn := range map[string]string{
+		"unknown package": `"docs#build"`,
+		"unknown task":    `"lib#codegen"`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			files := map[string]string{
+				"package.json":              `{"name": "root", "workspaces": ["packages/*"]}`,
+				"turbo.json":                fmt.Sprintf(`{"tasks": {"web#build": {"dependsOn": [%s]}}}`, dependsOn),
+				"packages/lib/package.json": `{"name": "lib", "scripts": {"build": "true"}}`,
+				"packages/web/package.json": `{"name": "web", "scripts": {"build": "true"}}`,
+			}
+			for name, content := range files {
+				path := filepath.Join(dir, name)
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+				require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+			}
+
+			mgr, err := NewManager(dir, events.NewEventBus(), false)
+			require.NoError(t, err)
+			defer mgr.Cleanup()
+
+			_, err = mgr.RunTask("web#build", 0)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), strings.Trim(dependsOn, `"`))
+		})
+	}
+}
+
+func TestStartScriptOrTaskRunsMakeTarget(t *testing.T) {
+	if _, err := exec.LookPath("make"); err != nil {
+		t.Skip("make is not installed")
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"name": "app", "scripts": {"dev": "echo dev"}}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Makefile"), []byte(".PHONY: build dev\nbuild: ## Build it\n\ttouch built.txt\ndev:\n\techo make dev\n"), 0644))
+	mgr, err := NewManager(dir, events.NewEventBus(), true)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	// The package.json script keeps its name
+	var names []string
+	for _, task := range mgr.ProjectTasks() {
+		names = append(names, task.Name)
+		assert.Equal(t, parser.CategoryMakefile, task.Category)
+	}
+	assert.Equal(t, []string{"build"}, names)
+
+	proc, err := mgr.StartScriptOrTask("build")
+	require.NoError(t, err)
+	assert.Equal(t, "build", proc.Name)
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(filepath.Join(dir, "built.txt"))
+		return err == nil
+	}, 5*time.Second, 50*time.Millisecond)
+}
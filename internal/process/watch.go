@@ -0,0 +1,296 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/standardbeagle/brummer/internal/config"
+	"github.com/standardbeagle/brummer/pkg/events"
+)
+
+// fileWatcher restarts a process when files under its watched paths change. One
+// watcher exists per script name; starting a new instance replaces it.
+type fileWatcher struct {
+	proc    *Process
+	cfg     *config.WatchConfig
+	watcher *fsnotify.Watcher
+	stop    chan struct{}
+	restart *watchRestart // shared with the watchers of later instances
+}
+
+// watchRestart keeps one change-triggered restart of a script in flight at a time.
+// Changes that arrive during a restart are merged into one follow-up restart.
+type watchRestart struct {
+	mu      sync.Mutex
+	running bool
+	pending string // last file changed while a restart was running
+}
+
+// matchGlob reports whether a slash-separated relative path matches pattern. A
+// pattern without a slash matches the file name in any directory, and ** matches
+// any number of directories.
+func matchGlob(pattern, name string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(name))
+		return ok
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// watchesFile reports whether a change to the file should trigger a restart
+func watchesFile(cfg *config.WatchConfig, rel string) bool {
+	for _, pattern := range cfg.GetExclude() {
+		if matchGlob(pattern, rel) {
+			return false
+		}
+	}
+	include := cfg.GetInclude()
+	if len(include) == 0 {
+		return true
+	}
+	for _, pattern := range include {
+		if matchGlob(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// excludesDir reports whether a directory is excluded as a whole
+func excludesDir(cfg *config.WatchConfig, rel string) bool {
+	for _, pattern := range cfg.GetExclude() {
+		if dirPattern, ok := strings.CutSuffix(pattern, "/**"); ok && matchGlob(dirPattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// startWatch begins watching files for a newly started process, replacing the
+// watcher of any earlier instance with the same name
+func (m *Manager) startWatch(p *Process, cfg *config.WatchConfig) {
+	m.watchMu.Lock()
+	restart := &watchRestart{}
+	if old, exists := m.watchers[p.Name]; exists {
+		close(old.stop)
+		delete(m.watchers, p.Name)
+		restart = old.restart
+	}
+	if cfg == nil {
+		m.watchMu.Unlock()
+		return
+	}
+	w := &fileWatcher{proc: p, cfg: cfg, stop: make(chan struct{}), restart: restart}
+	m.watchers[p.Name] = w
+	m.watchMu.Unlock()
+
+	go m.runWatch(w)
+}
+
+// stopAllWatches stops every file watcher
+func (m *Manager) stopAllWatches() {
+	m.watchMu.Lock()
+	defer m.watchMu.Unlock()
+
+	for name, w := range m.watchers {
+		close(w.stop)
+		delete(m.watchers, name)
+	}
+}
+
+// runWatch watches the configured paths and restarts the process after a quiet
+// period following a change. It keeps watching after the process crashes, so that
+// a fix restarts it, and exits once the process is stopped on purpose.
+func (m *Manager) runWatch(w *fileWatcher) {
+	p := w.proc
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		m.emitSystemLog(p.Name, fmt.Sprintf("❌ Failed to watch files for '%s': %v", p.Name, err), true)
+		return
+	}
+	defer watcher.Close()
+	w.watcher = watcher
+
+	for _, dir := range w.cfg.GetPaths() {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(m.workDir, dir)
+		}
+		m.addWatchTree(w, dir)
+	}
+
+	var timer *time.Timer
+	var fire <-chan time.Time
+	var changed string
+	done := p.done
+
+	for {
+		select {
+		case <-w.stop:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+
+		case <-done:
+			if p.isStopRequested() {
+				return
+			}
+			done = nil // crashed or exited; keep watching for a fix
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			rel, err := filepath.Rel(m.workDir, event.Name)
+			if err != nil {
+				continue
+			}
+			rel = filepath.ToSlash(rel)
+
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if !excludesDir(w.cfg, rel) {
+						m.addWatchTree(w, event.Name)
+					}
+					continue
+				}
+			}
+			if event.Op == fsnotify.Chmod || !watchesFile(w.cfg, rel) {
+				continue
+			}
+
+			changed = rel
+			if timer == nil {
+				timer = time.NewTimer(w.cfg.GetDebounce())
+			} else {
+				timer.Reset(w.cfg.GetDebounce())
+			}
+			fire = timer.C
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			m.emitSystemLog(p.Name, fmt.Sprintf("⚠️ File watcher for '%s': %v", p.Name, err), true)
+
+		case <-fire:
+			fire = nil
+			// The new instance starts its own watcher, which replaces this one
+			m.requestWatchRestart(w, changed)
+		}
+	}
+}
+
+// addWatchTree watches dir and every directory below it that is not excluded
+func (m *Manager) addWatchTree(w *fileWatcher, dir string) {
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if rel, err := filepath.Rel(m.workDir, path); err == nil && rel != "." && excludesDir(w.cfg, filepath.ToSlash(rel)) {
+			return filepath.SkipDir
+		}
+		if err := w.watcher.Add(path); err != nil {
+			m.emitSystemLog(w.proc.Name, fmt.Sprintf("⚠️ Cannot watch %s: %v", path, err), true)
+		}
+		return nil
+	})
+}
+
+// requestWatchRestart restarts the watcher's process for a changed file unless a
+// restart of the script is already running, in which case the change is queued
+// for a single follow-up restart of whichever instance is current by then
+func (m *Manager) requestWatchRestart(w *fileWatcher, file string) {
+	r := w.restart
+	r.mu.Lock()
+	if r.running {
+		r.pending = file
+		r.mu.Unlock()
+		return
+	}
+	r.running = true
+	r.mu.Unlock()
+
+	go func() {
+		p := w.proc
+		for {
+			m.restartForChange(p, file)
+
+			r.mu.Lock()
+			file, r.pending = r.pending, ""
+			if file == "" {
+				r.running = false
+				r.mu.Unlock()
+				return
+			}
+			r.mu.Unlock()
+
+			m.watchMu.Lock()
+			current, exists := m.watchers[p.Name]
+			m.watchMu.Unlock()
+			if !exists {
+				// The script is no longer watched
+				r.mu.Lock()
+				r.running = false
+				r.mu.Unlock()
+				return
+			}
+			p = current.proc
+		}
+	}()
+}
+
+// restartForChange stops the process if it is still running and starts it again
+func (m *Manager) restartForChange(p *Process, file string) {
+	m.emitSystemLog(p.Name, fmt.Sprintf("👀 %s changed; restarting '%s'", file, p.Name), false)
+	m.eventBus.Publish(events.Event{
+		Type:      events.ProcessFileChanged,
+		ProcessID: p.ID,
+		Data: map[string]interface{}{
+			"name": p.Name,
+			"file": file,
+		},
+	})
+
+	if p.GetStatus() == StatusRunning {
+		if err := m.StopProcessAndWait(p.ID, 5*time.Second); err != nil {
+			m.emitSystemLog(p.Name, fmt.Sprintf("❌ Failed to stop '%s' for restart: %v", p.Name, err), true)
+			return
+		}
+	}
+
+	// A change is a fresh start: forget crash-loop state and drop the old entry
+	m.clearRestartState(p.Name)
+	m.processes.Delete(p.ID)
+
+	if _, err := m.relaunch(p.spec); err != nil {
+		m.emitSystemLog(p.Name, fmt.Sprintf("❌ Failed to restart '%s' after %s changed: %v", p.Name, file, err), true)
+	}
+}
@@ -0,0 +1,138 @@
+//go:build !windows
+
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/standardbeagle/brummer/internal/config"
+	"github.com/standardbeagle/brummer/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMatchGlob tests glob matching against slash-separated relative paths
+func TestMatchGlob(t *testing.T) {
+	assert.True(t, matchGlob("*.go", "main.go"))
+	assert.True(t, matchGlob("*.go", "cmd/server/main.go"))
+	assert.False(t, matchGlob("*.go", "main.go.orig"))
+	assert.True(t, matchGlob("internal/**/*.go", "internal/a/b/c.go"))
+	assert.True(t, matchGlob("internal/**/*.go", "internal/c.go"))
+	assert.False(t, matchGlob("internal/**/*.go", "cmd/c.go"))
+	assert.True(t, matchGlob("**/testdata/**", "pkg/testdata/x.json"))
+
+	cfg := &config.WatchConfig{Include: []string{"*.go"}, Exclude: []string{"*_test.go"}}
+	assert.True(t, watchesFile(cfg, "main.go"))
+	assert.False(t, watchesFile(cfg, "main_test.go"))
+	assert.False(t, watchesFile(cfg, "README.md"))
+	assert.False(t, watchesFile(cfg, "node_modules/x/index.go"))
+	assert.True(t, excludesDir(cfg, "web/node_modules"))
+	assert.False(t, excludesDir(cfg, "web"))
+}
+
+// TestWatchRestartsOnChange tests that a matching change restarts the command and
+// that a non-matching one is ignored
+func TestWatchRestartsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644))
+
+	eventBus := events.NewEventBus()
+	mgr, err := NewManager(dir, eventBus, false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	mgr.config = &config.Config{
+		Scripts: map[string]*config.ScriptConfig{
+			"server": {
+				Watch: &config.WatchConfig{
+					Include:    []string{"*.go"},
+					DebounceMs: intPtr(50),
+				},
+			},
+		},
+	}
+
+	changes := make(chan string, 4)
+	eventBus.Subscribe(events.ProcessFileChanged, func(e events.Event) {
+		file, _ := e.Data["file"].(string)
+		changes <- file
+	})
+
+	first, err := mgr.StartCommand("server", "sleep", []string{"30"})
+	require.NoError(t, err)
+	time.Sleep(200 * time.Millisecond) // let the watcher register
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644))
+
+	select {
+	case file := <-changes:
+		assert.Equal(t, "main.go", file)
+	case <-time.After(5 * time.Second):
+		t.Fatal("change was not detected")
+	}
+
+	require.Eventually(t, func() bool {
+		for _, p := range mgr.GetAllProcesses() {
+			if p.Name == "server" && p != first && p.GetStatus() == StatusRunning {
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.NotEqual(t, StatusRunning, first.GetStatus())
+}
+
+// TestWatchRestartMergesChanges tests that changes arriving while a restart is
+// running lead to one follow-up restart rather than duplicate instances
+func TestWatchRestartMergesChanges(t *testing.T) {
+	eventBus := events.NewEventBus()
+	mgr, err := NewManager(t.TempDir(), eventBus, false)
+	require.NoError(t, err)
+	defer mgr.Cleanup()
+
+	mgr.config = &config.Config{
+		Scripts: map[string]*config.ScriptConfig{
+			"server": {Watch: &config.WatchConfig{Include: []string{"*.go"}}},
+		},
+	}
+
+	var starts atomic.Int32
+	eventBus.Subscribe(events.ProcessStarted, func(e events.Event) {
+		if name, _ := e.Data["name"].(string); name == "server" {
+			starts.Add(1)
+		}
+	})
+
+	// Stopping takes a while, leaving time for more changes to arrive
+	_, err = mgr.StartCommand("server", "sh", []string{"-c", `trap "sleep 0.5; exit 0" TERM; sleep 30 & wait`})
+	require.NoError(t, err)
+	mgr.watchMu.Lock()
+	w := mgr.watchers["server"]
+	mgr.watchMu.Unlock()
+	require.NotNil(t, w)
+
+	mgr.requestWatchRestart(w, "a.go")
+	mgr.requestWatchRestart(w, "b.go")
+	mgr.requestWatchRestart(w, "c.go")
+
+	require.Eventually(t, func() bool {
+		w.restart.mu.Lock()
+		defer w.restart.mu.Unlock()
+		return !w.restart.running
+	}, 10*time.Second, 50*time.Millisecond)
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int32(3), starts.Load(), "the first start, the restart and one follow-up")
+
+	running := 0
+	for _, p := range mgr.GetAllProcesses() {
+		if p.Name == "server" && p.GetStatus() == StatusRunning {
+			running++
+		}
+	}
+	assert.Equal(t, 1, running)
+}
@@ -0,0 +1,70 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func at(text string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", text, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCronNext(t *testing.T) {
+	cases := []struct {
+		expr, from, want string
+	}{
+		{"*/10 * * * *", "2026-03-02 09:03", "2026-03-02 09:10"},
+		{"*/10 * * * *", "2026-03-02 09:50", "2026-03-02 10:00"},
+		{"30 6 * * *", "2026-03-02 06:30", "2026-03-03 06:30"},
+		{"0 9 * * mon-fri", "2026-03-06 10:00", "2026-03-09 09:00"}, // Friday to Monday
+		{"0 0 1 jan,jul *", "2026-03-02 00:00", "2026-07-01 00:00"},
+		{"0 12 * * 7", "2026-03-02 00:00", "2026-03-08 12:00"},   // 7 is Sunday
+		{"0 0 13 * fri", "2026-03-02 00:00", "2026-03-06 00:00"}, // either day field may match
+		{"@daily", "2026-12-31 23:59", "2027-01-01 00:00"},
+		{"5/20 * * * *", "2026-03-02 09:26", "2026-03-02 09:45"},
+	}
+	for _, tc := range cases {
+		s, err := Parse(tc.expr)
+		require.NoError(t, err, tc.expr)
+		assert.Equal(t, at(tc.want), s.Next(at(tc.from)), tc.expr)
+		assert.Equal(t, tc.expr, s.String())
+	}
+}
+
+func TestCronNeverMatches(t *testing.T) {
+	s, err := Parse("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, s.Next(at("2026-01-01 00:00")).IsZero())
+}
+
+func TestEvery(t *testing.T) {
+	s, err := Parse("@every 10m")
+	require.NoError(t, err)
+	assert.Equal(t, at("2026-03-02 09:13"), s.Next(at("2026-03-02 09:03")))
+	assert.Equal(t, "every 10m0s", s.String())
+
+	_, err = Every(10 * time.Millisecond)
+	assert.Error(t, err)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		"* * * *",
+		"60 * * * *",
+		"* * * foo *",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"@sometimes",
+		"@every soon",
+	} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
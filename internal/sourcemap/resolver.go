@@ -0,0 +1,347 @@
+package sourcemap
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	maxFileSize     = 64 << 20 // generated files and maps larger than this are skipped
+	cacheSize       = 256
+	recheckInterval = time.Second      // how often a local file is checked for changes
+	remoteTTL       = 10 * time.Second // dev servers rebuild in place, so remote maps expire
+)
+
+// frameLocation matches the file:line:column of V8, Firefox and Safari stack frames
+// whose file is a URL or an absolute path
+var frameLocation = regexp.MustCompile(`((?:https?|file)://[^\s()'"]+?|(?:[A-Za-z]:)?[/\\][^\s()'":]+?):(\d+):(\d+)`)
+
+// webpackPrefix is the scheme and namespace webpack puts in front of sources
+var webpackPrefix = regexp.MustCompile(`^webpack://[^/]*/`)
+
+// Resolver finds the source maps of generated JavaScript files and maps stack
+// frames back to their sources. Maps may be inline data URLs, sidecar .map files
+// or fetched from a dev server on this machine; they are cached until the
+// generated file changes.
+type Resolver struct {
+	workDir string
+	client  *http.Client
+
+	mu    sync.Mutex
+	cache map[string]*cachedMap
+}
+
+type cachedMap struct {
+	m       *Map
+	base    string // where the map's sources are relative to
+	err     error
+	remote  bool
+	modTime time.Time // of a local generated file
+	checked time.Time
+}
+
+// NewResolver creates a resolver. Relative paths and sources are taken from workDir.
+func NewResolver(workDir string) *Resolver {
+	return &Resolver{
+		workDir: workDir,
+		client:  &http.Client{Timeout: 5 * time.Second},
+		cache:   make(map[string]*cachedMap),
+	}
+}
+
+// ResolveStack rewrites the frames of each line that a source map covers. The
+// result has one line per input line; lines without mapped frames are unchanged.
+func (r *Resolver) ResolveStack(lines []string) []string {
+	resolved := make([]string, len(lines))
+	for i, line := range lines {
+		resolved[i] = r.ResolveLine(line)
+	}
+	return resolved
+}
+
+// ResolveLine rewrites the file:line:column locations in one line of a stack trace
+func (r *Resolver) ResolveLine(line string) string {
+	matches := frameLocation.FindAllStringSubmatchIndex(line, -1)
+	if matches == nil {
+		return line
+	}
+	var out strings.Builder
+	last := 0
+	for _, match := range matches {
+		location := line[match[2]:match[3]]
+		lineNum, _ := strconv.Atoi(line[match[4]:match[5]])
+		column, _ := strconv.Atoi(line[match[6]:match[7]])
+		pos, ok := r.Resolve(location, lineNum, column)
+		if !ok {
+			continue
+		}
+		out.WriteString(line[last:match[0]])
+		out.WriteString(fmt.Sprintf("%s:%d:%d", pos.Source, pos.Line, pos.Column))
+		last = match[1]
+	}
+	out.WriteString(line[last:])
+	return out.String()
+}
+
+// Resolve maps a position in a generated file or URL to its original source. The
+// source is relative to the working directory when it lies inside it.
+func (r *Resolver) Resolve(location string, line, column int) (Position, bool) {
+	if strings.HasPrefix(location, "file://") {
+		u, err := url.Parse(location)
+		if err != nil {
+			return Position{}, false
+		}
+		location = filepath.FromSlash(strings.TrimPrefix(u.Path, "/"))
+		if !filepath.IsAbs(location) {
+			location = filepath.FromSlash(u.Path)
+		}
+	}
+	if !isRemote(location) && !isScript(location) {
+		return Position{}, false
+	}
+
+	entry := r.load(location)
+	if entry.m == nil {
+		return Position{}, false
+	}
+	pos, ok := entry.m.Find(line, column)
+	if !ok {
+		return Position{}, false
+	}
+	pos.Source = r.displaySource(entry, pos.Source)
+	return pos, true
+}
+
+// load returns the cached map of a generated file, loading it when it is missing
+// or stale
+func (r *Resolver) load(location string) *cachedMap {
+	r.mu.Lock()
+	entry := r.cache[location]
+	if entry != nil && r.fresh(location, entry) {
+		r.mu.Unlock()
+		return entry
+	}
+	r.mu.Unlock()
+
+	entry = r.loadMap(location)
+
+	r.mu.Lock()
+	if len(r.cache) >= cacheSize {
+		r.cache = make(map[string]*cachedMap)
+	}
+	r.cache[location] = entry
+	r.mu.Unlock()
+	return entry
+}
+
+// fresh reports whether a cached map still matches its generated file. Called with mu held.
+func (r *Resolver) fresh(location string, entry *cachedMap) bool {
+	age := time.Since(entry.checked)
+	if entry.remote {
+		return age < remoteTTL
+	}
+	if age < recheckInterval {
+		return true
+	}
+	info, err := os.Stat(r.localPath(location))
+	if err != nil || !info.ModTime().Equal(entry.modTime) {
+		return false
+	}
+	entry.checked = time.Now()
+	return true
+}
+
+func (r *Resolver) loadMap(location string) *cachedMap {
+	entry := &cachedMap{checked: time.Now(), remote: isRemote(location), base: location}
+	data, header, modTime, err := r.read(location)
+	entry.modTime = modTime
+	if err != nil {
+		entry.err = err
+		return entry
+	}
+
+	ref := header
+	if ref == "" {
+		ref = sourceMappingURL(data)
+	}
+	var mapData []byte
+	switch {
+	case strings.HasPrefix(ref, "data:"):
+		mapData, err = decodeDataURL(ref)
+	case ref != "":
+		entry.base = resolveReference(location, ref)
+		mapData, _, _, err = r.read(entry.base)
+	default:
+		entry.base = location + ".map"
+		mapData, _, _, err = r.read(entry.base)
+	}
+	if err == nil {
+		entry.m, err = Parse(mapData)
+	}
+	entry.err = err
+	return entry
+}
+
+// read loads a local file or a URL on this machine. For URLs it also returns the
+// SourceMap header.
+func (r *Resolver) read(location string) ([]byte, string, time.Time, error) {
+	if !isRemote(location) {
+		path := r.localPath(location)
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, "", time.Time{}, err
+		}
+		if info.Size() > maxFileSize {
+			return nil, "", info.ModTime(), fmt.Errorf("%s is too large", path)
+		}
+		data, err := os.ReadFile(path)
+		return data, "", info.ModTime(), err
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+	if !isLoopback(u.Hostname()) {
+		return nil, "", time.Time{}, fmt.Errorf("not fetching source maps from %s", u.Host)
+	}
+	resp, err := r.client.Get(location)
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", time.Time{}, fmt.Errorf("GET %s: %s", location, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFileSize))
+	header := resp.Header.Get("SourceMap")
+	if header == "" {
+		header = resp.Header.Get("X-SourceMap")
+	}
+	return data, header, time.Time{}, err
+}
+
+func (r *Resolver) localPath(location string) string {
+	if filepath.IsAbs(location) || r.workDir == "" {
+		return location
+	}
+	return filepath.Join(r.workDir, location)
+}
+
+// displaySource turns a source named in a map into a path a developer can open
+func (r *Resolver) displaySource(entry *cachedMap, source string) string {
+	if webpackPrefix.MatchString(source) {
+		return strings.TrimPrefix(webpackPrefix.ReplaceAllString(source, ""), "./")
+	}
+	if entry.remote || isRemote(source) {
+		base, err := url.Parse(entry.base)
+		if err != nil {
+			return source
+		}
+		resolved, err := base.Parse(source)
+		if err != nil || (resolved.Scheme != "http" && resolved.Scheme != "https") {
+			return source
+		}
+		return strings.TrimPrefix(resolved.Path, "/")
+	}
+
+	path := filepath.FromSlash(source)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(r.localPath(entry.base)), path)
+	}
+	if r.workDir != "" {
+		if rel, err := filepath.Rel(r.workDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return path
+}
+
+// sourceMappingURL returns the last //# sourceMappingURL= comment of a generated file
+func sourceMappingURL(data []byte) string {
+	marker := []byte("sourceMappingURL=")
+	i := bytes.LastIndex(data, marker)
+	if i < 0 {
+		return ""
+	}
+	prefix := bytes.TrimRight(data[max(0, i-8):i], " \t")
+	if !bytes.HasSuffix(prefix, []byte("//#")) && !bytes.HasSuffix(prefix, []byte("//@")) {
+		return ""
+	}
+	ref := data[i+len(marker):]
+	if end := bytes.IndexAny(ref, " \t\r\n'\""); end >= 0 {
+		ref = ref[:end]
+	}
+	return string(ref)
+}
+
+// decodeDataURL decodes an inline source map
+func decodeDataURL(ref string) ([]byte, error) {
+	meta, payload, ok := strings.Cut(strings.TrimPrefix(ref, "data:"), ",")
+	if !ok {
+		return nil, fmt.Errorf("invalid data URL")
+	}
+	if strings.HasSuffix(meta, ";base64") {
+		return base64.StdEncoding.DecodeString(payload)
+	}
+	text, err := url.PathUnescape(payload)
+	return []byte(text), err
+}
+
+// resolveReference resolves a sourceMappingURL against the generated file it is in
+func resolveReference(location, ref string) string {
+	if isRemote(location) {
+		base, err := url.Parse(location)
+		if err != nil {
+			return ref
+		}
+		resolved, err := base.Parse(ref)
+		if err != nil {
+			return ref
+		}
+		return resolved.String()
+	}
+	if isRemote(ref) || filepath.IsAbs(ref) {
+		return ref
+	}
+	if unescaped, err := url.PathUnescape(ref); err == nil {
+		ref = unescaped
+	}
+	return filepath.Join(filepath.Dir(location), filepath.FromSlash(ref))
+}
+
+func isRemote(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// isScript reports whether a local file can be a generated script, so that frames
+// of other languages don't read their source files
+func isScript(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".js", ".mjs", ".cjs", ".jsx":
+		return true
+	}
+	return false
+}
+
+// isLoopback reports whether a host is this machine. Only dev servers and the
+// proxy are asked for source maps.
+func isLoopback(host string) bool {
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,186 @@
+// Package sourcemap reads JavaScript source maps and rewrites stack frames of
+// bundled or minified code to the original source positions.
+package sourcemap
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Map is a parsed version 3 source map
+type Map struct {
+	File    string
+	Sources []string // with the sourceRoot applied
+	Names   []string
+
+	lines [][]segment // segments of each generated line, by generated column
+}
+
+// Position is a place in an original source. Line and Column are 1-based.
+type Position struct {
+	Source string
+	Line   int
+	Column int
+	Name   string
+}
+
+type segment struct {
+	column       int // generated column, 0-based
+	source       int // index into Sources, -1 when the segment maps nowhere
+	sourceLine   int
+	sourceColumn int
+	name         int // index into Names, -1 when absent
+}
+
+type rawMap struct {
+	Version    int             `json:"version"`
+	File       string          `json:"file"`
+	SourceRoot string          `json:"sourceRoot"`
+	Sources    []string        `json:"sources"`
+	Names      []string        `json:"names"`
+	Mappings   string          `json:"mappings"`
+	Sections   []rawMapSection `json:"sections"`
+}
+
+type rawMapSection struct {
+	Offset struct {
+		Line   int `json:"line"`
+		Column int `json:"column"`
+	} `json:"offset"`
+	Map *rawMap `json:"map"`
+}
+
+// Parse reads a source map. Index maps with sections are flattened.
+func Parse(data []byte) (*Map, error) {
+	// Maps served to browsers may start with )]}' to prevent XSSI
+	text := strings.TrimPrefix(strings.TrimSpace(string(data)), ")]}'")
+	var raw rawMap
+	if err := json.Unmarshal([]byte(text), &raw); err != nil {
+		return nil, fmt.Errorf("invalid source map: %w", err)
+	}
+	if raw.Version != 3 {
+		return nil, fmt.Errorf("unsupported source map version %d", raw.Version)
+	}
+
+	m := &Map{File: raw.File}
+	if len(raw.Sections) == 0 {
+		if err := m.addMappings(&raw, 0, 0); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+	for _, section := range raw.Sections {
+		if section.Map == nil {
+			return nil, fmt.Errorf("source map section at line %d has no map", section.Offset.Line)
+		}
+		if err := m.addMappings(section.Map, section.Offset.Line, section.Offset.Column); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// addMappings decodes the mappings of raw into m, shifted by a section offset
+func (m *Map) addMappings(raw *rawMap, lineOffset, columnOffset int) error {
+	sourceBase, nameBase := len(m.Sources), len(m.Names)
+	for _, source := range raw.Sources {
+		if raw.SourceRoot != "" {
+			source = strings.TrimSuffix(raw.SourceRoot, "/") + "/" + source
+		}
+		m.Sources = append(m.Sources, source)
+	}
+	m.Names = append(m.Names, raw.Names...)
+
+	var source, sourceLine, sourceColumn, name int
+	for lineIndex, line := range strings.Split(raw.Mappings, ";") {
+		generatedLine := lineIndex + lineOffset
+		for len(m.lines) <= generatedLine {
+			m.lines = append(m.lines, nil)
+		}
+		column := 0
+		for _, field := range strings.Split(line, ",") {
+			if field == "" {
+				continue
+			}
+			values, err := decodeVLQ(field)
+			if err != nil {
+				return fmt.Errorf("invalid mapping %q on line %d: %w", field, generatedLine+1, err)
+			}
+			column += values[0]
+			seg := segment{column: column, source: -1, name: -1}
+			if lineIndex == 0 {
+				seg.column += columnOffset
+			}
+			if len(values) >= 4 {
+				source += values[1]
+				sourceLine += values[2]
+				sourceColumn += values[3]
+				seg.source, seg.sourceLine, seg.sourceColumn = sourceBase+source, sourceLine, sourceColumn
+			}
+			if len(values) >= 5 {
+				name += values[4]
+				seg.name = nameBase + name
+			}
+			m.lines[generatedLine] = append(m.lines[generatedLine], seg)
+		}
+		sort.SliceStable(m.lines[generatedLine], func(i, j int) bool {
+			return m.lines[generatedLine][i].column < m.lines[generatedLine][j].column
+		})
+	}
+	return nil
+}
+
+// Find maps a 1-based generated line and column to its original position
+func (m *Map) Find(line, column int) (Position, bool) {
+	if line < 1 || line > len(m.lines) {
+		return Position{}, false
+	}
+	segments := m.lines[line-1]
+	i := sort.Search(len(segments), func(i int) bool { return segments[i].column > column-1 }) - 1
+	if i < 0 || segments[i].source < 0 || segments[i].source >= len(m.Sources) {
+		return Position{}, false
+	}
+	seg := segments[i]
+	pos := Position{Source: m.Sources[seg.source], Line: seg.sourceLine + 1, Column: seg.sourceColumn + 1}
+	if seg.name >= 0 && seg.name < len(m.Names) {
+		pos.Name = m.Names[seg.name]
+	}
+	return pos, true
+}
+
+const base64Digits = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// decodeVLQ decodes a segment of base64 VLQ values
+func decodeVLQ(field string) ([]int, error) {
+	var values []int
+	value, shift := 0, 0
+	for i := 0; i < len(field); i++ {
+		digit := strings.IndexByte(base64Digits, field[i])
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid base64 digit %q", field[i])
+		}
+		value += (digit & 31) << shift
+		if digit&32 != 0 {
+			shift += 5
+			if shift > 30 {
+				return nil, fmt.Errorf("value too large")
+			}
+			continue
+		}
+		if value&1 != 0 {
+			values = append(values, -(value >> 1))
+		} else {
+			values = append(values, value>>1)
+		}
+		value, shift = 0, 0
+	}
+	if shift != 0 {
+		return nil, fmt.Errorf("truncated value")
+	}
+	if n := len(values); n != 1 && n != 4 && n != 5 {
+		return nil, fmt.Errorf("segment has %d values", n)
+	}
+	return values, nil
+}
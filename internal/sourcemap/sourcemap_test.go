@@ -0,0 +1,141 @@
+package sourcemap
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// vlq encodes one mapping segment
+func vlq(values ...int) string {
+	var out strings.Builder
+	for _, value := range values {
+		v := value << 1
+		if value < 0 {
+			v = (-value << 1) | 1
+		}
+		for {
+			digit := v & 31
+			v >>= 5
+			if v > 0 {
+				digit |= 32
+			}
+			out.WriteByte(base64Digits[digit])
+			if v == 0 {
+				break
+			}
+		}
+	}
+	return out.String()
+}
+
+// testMap maps generated line 1 column 11 to line 5 column 3 of the first source,
+// column 21 to line 10 of the second, and line 2 column 5 to line 12 column 7
+func testMap(sources ...string) string {
+	mappings := strings.Join([]string{
+		vlq(0, 0, 0, 0),
+		vlq(10, 0, 4, 2, 0),
+		vlq(10, 1, 5, -2),
+	}, ",") + ";" + vlq(4, 0, 2, 6)
+	return fmt.Sprintf(`{"version":3,"file":"app.js","sources":["%s"],"names":["handler"],"mappings":"%s"}`,
+		strings.Join(sources, `","`), mappings)
+}
+
+func TestMapFind(t *testing.T) {
+	m, err := Parse([]byte(testMap("a.ts", "b.ts")))
+	require.NoError(t, err)
+
+	tests := []struct {
+		line, column int
+		want         Position
+		ok           bool
+	}{
+		{1, 1, Position{Source: "a.ts", Line: 1, Column: 1}, true},
+		{1, 15, Position{Source: "a.ts", Line: 5, Column: 3, Name: "handler"}, true},
+		{1, 21, Position{Source: "b.ts", Line: 10, Column: 1}, true},
+		{2, 5, Position{Source: "b.ts", Line: 12, Column: 7}, true},
+		{2, 1, Position{}, false},
+		{3, 1, Position{}, false},
+	}
+	for _, tt := range tests {
+		pos, ok := m.Find(tt.line, tt.column)
+		assert.Equal(t, tt.ok, ok, "%d:%d", tt.line, tt.column)
+		assert.Equal(t, tt.want, pos, "%d:%d", tt.line, tt.column)
+	}
+
+	_, err = Parse([]byte(`{"version":3,"sources":["a.ts"],"mappings":"AA"}`))
+	assert.Error(t, err)
+}
+
+func TestResolverLocalMaps(t *testing.T) {
+	workDir := t.TempDir()
+	dist := filepath.Join(workDir, "dist")
+	require.NoError(t, os.MkdirAll(dist, 0755))
+
+	// A sidecar map named by a comment, an inline map, and a sidecar found by name
+	writeFile(t, filepath.Join(dist, "server.js"), "run()\n//# sourceMappingURL=maps/server.js.map\n")
+	writeFile(t, filepath.Join(dist, "maps", "server.js.map"), testMap("../../src/server.ts", "../../src/db.ts"))
+	inline := base64.StdEncoding.EncodeToString([]byte(testMap("../src/worker.ts", "../src/queue.ts")))
+	writeFile(t, filepath.Join(dist, "worker.js"), "run()\n//# sourceMappingURL=data:application/json;charset=utf-8;base64,"+inline+"\n")
+	writeFile(t, filepath.Join(dist, "cli.js"), "run()\n")
+	writeFile(t, filepath.Join(dist, "cli.js.map"), testMap("webpack://cli/./src/cli.ts"))
+
+	r := NewResolver(workDir)
+	stack := []string{
+		"TypeError: boom",
+		"    at handler (" + filepath.Join(dist, "server.js") + ":1:15)",
+		"    at Object.<anonymous> (" + filepath.Join(dist, "server.js") + ":1:22)",
+		"    at " + filepath.Join(dist, "worker.js") + ":2:9",
+		"    at main (" + filepath.Join(dist, "cli.js") + ":1:11)",
+		"    at node:internal/main:12:3",
+		"    at other (" + filepath.Join(dist, "server.js") + ":9:1)",
+	}
+	assert.Equal(t, []string{
+		"TypeError: boom",
+		"    at handler (src/server.ts:5:3)",
+		"    at Object.<anonymous> (src/db.ts:10:1)",
+		"    at src/queue.ts:12:7",
+		"    at main (src/cli.ts:5:3)",
+		"    at node:internal/main:12:3",
+		"    at other (" + filepath.Join(dist, "server.js") + ":9:1)",
+	}, r.ResolveStack(stack))
+}
+
+func TestResolverFetchesFromDevServer(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		switch req.URL.Path {
+		case "/static/js/main.abc123.js":
+			fmt.Fprint(w, "run()\n//# sourceMappingURL=main.abc123.js.map")
+		case "/static/js/main.abc123.js.map":
+			fmt.Fprint(w, testMap("webpack://app/./src/App.tsx"))
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer server.Close()
+
+	r := NewResolver(t.TempDir())
+	frame := "render@" + server.URL + "/static/js/main.abc123.js:1:15"
+	assert.Equal(t, "render@src/App.tsx:5:3", r.ResolveLine(frame))
+	assert.Equal(t, "render@src/App.tsx:5:3", r.ResolveLine(frame))
+	assert.Equal(t, 2, requests, "the map is cached")
+
+	missing := "at " + server.URL + "/static/js/missing.js:1:1"
+	assert.Equal(t, missing, r.ResolveLine(missing))
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
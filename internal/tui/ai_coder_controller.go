@@ -52,6 +52,7 @@ func (c *configAdapter) GetAICoderConfig() aicoder.AICoderConfig {
 		WorkspaceBaseDir: stringVal(aiCfg.WorkspaceBaseDir, filepath.Join(os.Getenv("HOME"), ".brummer", "ai-coders")),
 		DefaultProvider:  stringVal(aiCfg.DefaultProvider, "claude"),
 		TimeoutMinutes:   intVal(aiCfg.TimeoutMinutes, 30),
+		ResourceLimits:   aiCfg.ResourceLimits.CgroupLimits(),
 	}
 }
 
@@ -490,6 +491,9 @@ func (c *AICoderController) HandleAICommand(providerName string) {
 						c.updateChan <- logUpdateMsg{}
 						return
 					}
+					if warning := session.LimitsWarning(); warning != nil {
+						c.logStore.Add("system", "System", fmt.Sprintf("⚠️ Resource limits for %s: %v", sessionName, warning), true)
+					}
 
 					// Set the current session in the PTY view
 					if c.aiCoderPTYView != nil {
@@ -626,6 +630,9 @@ func (c *AICoderController) monitorPTYOutput(session *aicoder.PTYSession) {
 			case aicoder.PTYEventResize:
 				// Send event to PTY view
 				c.updateChan <- PTYEventMsg{Event: event}
+			case aicoder.PTYEventOOM:
+				c.logStore.Add("system", "System", fmt.Sprintf("💥 AI coder session %s: %v", session.Name, event.Data), true)
+				c.updateChan <- logUpdateMsg{}
 			}
 			continue
 		}
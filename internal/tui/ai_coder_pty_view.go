@@ -9,7 +9,6 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/hinshun/vt10x"
 
 	"github.com/standardbeagle/brummer/internal/aicoder"
 )
@@ -472,46 +471,7 @@ func (v *AICoderPTYView) getTerminalSize() (int, int) {
 
 // keyMsgToBytes converts a tea.KeyMsg to bytes for PTY input
 func (v *AICoderPTYView) keyMsgToBytes(msg tea.KeyMsg) []byte {
-	switch msg.Type {
-	case tea.KeyRunes:
-		return []byte(msg.String())
-	case tea.KeySpace:
-		return []byte(" ")
-	case tea.KeyEnter:
-		return []byte("\r")
-	case tea.KeyBackspace:
-		return []byte("\b")
-	case tea.KeyTab:
-		return []byte("\t")
-	case tea.KeyEsc:
-		return []byte("\x1b")
-	case tea.KeyUp:
-		return []byte("\x1b[A")
-	case tea.KeyDown:
-		return []byte("\x1b[B")
-	case tea.KeyRight:
-		return []byte("\x1b[C")
-	case tea.KeyLeft:
-		return []byte("\x1b[D")
-	case tea.KeyHome:
-		return []byte("\x1b[H")
-	case tea.KeyEnd:
-		return []byte("\x1b[F")
-	case tea.KeyPgUp:
-		return []byte("\x1b[5~")
-	case tea.KeyPgDown:
-		return []byte("\x1b[6~")
-	case tea.KeyDelete:
-		return []byte("\x1b[3~")
-	case tea.KeyCtrlC:
-		return []byte("\x03")
-	case tea.KeyCtrlD:
-		return []byte("\x04")
-	case tea.KeyCtrlZ:
-		return []byte("\x1a")
-	default:
-		return []byte{}
-	}
+	return keyMsgToTerminalInput(msg)
 }
 
 // showDataInjectionFeedback shows brief feedback for data injection
@@ -743,171 +703,14 @@ func (v *AICoderPTYView) renderTerminalContent() string {
 			v.currentSession.ID, v.currentSession.IsActive, len(v.currentSession.GetOutputHistory()) > 0)
 	}
 
-	// Build the visible content from the terminal buffer
-	var content strings.Builder
+	// Get our available rendering dimensions
+	termWidth, termHeight := v.getTerminalSize()
 
 	// Lock terminal while reading to ensure consistency
 	terminal.Lock()
 	defer terminal.Unlock()
 
-	// Get our available rendering dimensions
-	termWidth, termHeight := v.getTerminalSize()
-
-	// Get the actual terminal buffer dimensions
-	// The terminal may be larger than our display area
-	width, height := terminal.Size()
-
-	// Limit to our available display space
-	if height > termHeight {
-		height = termHeight
-	}
-	// Ensure content fits within our calculated display constraints
-	// termWidth already accounts for border and padding, so use it directly
-	if width > termWidth {
-		width = termWidth
-	}
-
-	// Track the current text style as we scan across cells
-	// This allows us to minimize ANSI code generation by only
-	// emitting codes when the style changes
-	currentFG := vt10x.DefaultFG
-	currentBG := vt10x.DefaultBG
-	var currentMode int16
-	styleActive := false
-
-	for y := 0; y < height; y++ {
-		lineBuffer := strings.Builder{}
-
-		for x := 0; x < width; x++ {
-			// Get the cell at this position
-			// Each cell contains: character, foreground color, background color, and text attributes
-			cell := terminal.Cell(x, y)
-
-			// Check if this cell's style differs from the current style
-			// This optimization prevents generating redundant ANSI codes
-			if cell.FG != currentFG || cell.BG != currentBG || cell.Mode != currentMode {
-				// Reset previous style if one was active
-				// This ensures a clean slate before applying new attributes
-				if styleActive {
-					lineBuffer.WriteString("\033[0m")
-					styleActive = false
-				}
-
-				// Check if this cell needs any styling
-				// DefaultFG/DefaultBG are special values meaning "use terminal default"
-				if cell.FG != vt10x.DefaultFG || cell.BG != vt10x.DefaultBG || cell.Mode != 0 {
-					// Build ANSI escape sequence codes
-					codes := []string{}
-
-					// Text attribute codes (Mode is a bitmask)
-					// These must come before color codes in the ANSI sequence
-					if cell.Mode&(1<<2) != 0 { // Bold (bit 2)
-						codes = append(codes, "1")
-					}
-					if cell.Mode&(1<<1) != 0 { // Underline (bit 1)
-						codes = append(codes, "4")
-					}
-					if cell.Mode&(1<<0) != 0 { // Reverse video (bit 0)
-						codes = append(codes, "7")
-					}
-					if cell.Mode&(1<<5) != 0 { // Blink (bit 5)
-						codes = append(codes, "5")
-					}
-					if cell.Mode&(1<<4) != 0 { // Italic (bit 4)
-						codes = append(codes, "3")
-					}
-
-					// Foreground color handling
-					// vt10x uses a clever Color encoding scheme:
-					// - 0-7: Standard ANSI colors
-					// - 8-15: Bright ANSI colors
-					// - 16-255: 256-color palette
-					// - 256-16777215: 24-bit true color (RGB packed)
-					// - 16777216+: Special values (DefaultFG, DefaultBG)
-					if cell.FG != vt10x.DefaultFG {
-						if cell.FG < 8 {
-							// Standard ANSI colors (black, red, green, yellow, blue, magenta, cyan, white)
-							// Use codes 30-37
-							codes = append(codes, fmt.Sprintf("3%d", cell.FG))
-						} else if cell.FG < 16 {
-							// Bright ANSI colors
-							// Use codes 90-97 (bright black through bright white)
-							codes = append(codes, fmt.Sprintf("9%d", cell.FG-8))
-						} else if cell.FG < 256 {
-							// 256-color palette
-							// Use ESC[38;5;{n}m format
-							codes = append(codes, fmt.Sprintf("38;5;%d", cell.FG))
-						} else if cell.FG < vt10x.DefaultFG {
-							// 24-bit true color (RGB)
-							// vt10x packs RGB values into a single uint32:
-							// Color = (R << 16) | (G << 8) | B
-							// We need to extract and use ESC[38;2;{r};{g};{b}m format
-							r := (cell.FG >> 16) & 0xFF
-							g := (cell.FG >> 8) & 0xFF
-							b := cell.FG & 0xFF
-							codes = append(codes, fmt.Sprintf("38;2;%d;%d;%d", r, g, b))
-						}
-					}
-
-					// Background color handling (same scheme as foreground)
-					if cell.BG != vt10x.DefaultBG {
-						if cell.BG < 8 {
-							// Standard ANSI background colors
-							// Use codes 40-47
-							codes = append(codes, fmt.Sprintf("4%d", cell.BG))
-						} else if cell.BG < 16 {
-							// Bright ANSI background colors
-							// Use codes 100-107
-							codes = append(codes, fmt.Sprintf("10%d", cell.BG-8))
-						} else if cell.BG < 256 {
-							// 256-color palette background
-							// Use ESC[48;5;{n}m format
-							codes = append(codes, fmt.Sprintf("48;5;%d", cell.BG))
-						} else if cell.BG < vt10x.DefaultBG {
-							// 24-bit true color background (RGB)
-							// Use ESC[48;2;{r};{g};{b}m format
-							r := (cell.BG >> 16) & 0xFF
-							g := (cell.BG >> 8) & 0xFF
-							b := cell.BG & 0xFF
-							codes = append(codes, fmt.Sprintf("48;2;%d;%d;%d", r, g, b))
-						}
-					}
-
-					// Generate the complete ANSI escape sequence
-					if len(codes) > 0 {
-						// ESC[{code1};{code2};...m format
-						ansiCode := fmt.Sprintf("\033[%sm", strings.Join(codes, ";"))
-						lineBuffer.WriteString(ansiCode)
-						styleActive = true
-					}
-				}
-
-				// Update our tracking variables
-				currentFG = cell.FG
-				currentBG = cell.BG
-				currentMode = cell.Mode
-			}
-
-			// Write the character
-			if cell.Char != 0 && cell.Char != '\n' && cell.Char != '\r' {
-				lineBuffer.WriteRune(cell.Char)
-			} else {
-				lineBuffer.WriteRune(' ')
-			}
-		}
-
-		// Reset style at end of line if needed
-		if styleActive {
-			lineBuffer.WriteString("\033[0m")
-		}
-
-		content.WriteString(lineBuffer.String())
-		if y < height-1 {
-			content.WriteString("\n")
-		}
-	}
-
-	return content.String()
+	return renderTerminalScreen(terminal, termWidth, termHeight)
 }
 
 // renderScrolledView renders the terminal with scroll offset applied
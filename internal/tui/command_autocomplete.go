@@ -3,11 +3,13 @@ package tui
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/standardbeagle/brummer/internal/logs"
 	"github.com/standardbeagle/brummer/internal/process"
 )
 
@@ -157,7 +159,7 @@ func (c *CommandAutocomplete) updateSuggestions() {
 	// Always show dropdown if we have suggestions or if we're at the beginning
 	if len(c.suggestions) == 0 && c.currentIndex == 0 && (value == "" || value == "/") {
 		// Show initial commands when empty
-		c.suggestions = []string{"run", "restart", "stop", "clear", "show", "hide", "proxy", "toggle-proxy", "ai", "term", "help"}
+		c.suggestions = []string{"run", "restart", "stop", "clear", "show", "hide", "search", "proxy", "toggle-proxy", "env", "adopt", "port", "errors", "ai", "term", "help"}
 		c.showDropdown = true
 	}
 
@@ -170,7 +172,7 @@ func (c *CommandAutocomplete) updateSuggestions() {
 func (c *CommandAutocomplete) getSuggestionsForCurrentPosition() []string {
 	if c.currentIndex == 0 {
 		// First segment - show root commands
-		rootCommands := []string{"run", "restart", "stop", "clear", "show", "hide", "proxy", "toggle-proxy", "ai", "term", "help"}
+		rootCommands := []string{"run", "restart", "stop", "clear", "show", "hide", "search", "proxy", "toggle-proxy", "env", "adopt", "port", "errors", "ai", "term", "help"}
 		currentText := ""
 		if len(c.segments) > 0 {
 			currentText = c.segments[0]
@@ -204,6 +206,7 @@ func (c *CommandAutocomplete) getSuggestionsForCurrentPosition() []string {
 				}
 			}
 			sort.Strings(scripts)
+			scripts = append(scripts, c.groupSuggestions()...)
 
 			currentText := ""
 			if c.currentIndex < len(c.segments) {
@@ -222,6 +225,9 @@ func (c *CommandAutocomplete) getSuggestionsForCurrentPosition() []string {
 					}
 				}
 			}
+			if c.segments[0] == "/stop" {
+				processes = append(processes, c.groupSuggestions()...)
+			}
 
 			currentText := ""
 			if c.currentIndex < len(c.segments) {
@@ -246,6 +252,41 @@ func (c *CommandAutocomplete) getSuggestionsForCurrentPosition() []string {
 			}
 			return c.filterSuggestions(options, currentText)
 
+		case "/env":
+			// Any script can have its resolved environment shown
+			scripts := make([]string, 0, len(c.availableScripts))
+			for name := range c.availableScripts {
+				scripts = append(scripts, name)
+			}
+			sort.Strings(scripts)
+
+			currentText := ""
+			if c.currentIndex < len(c.segments) {
+				currentText = c.segments[c.currentIndex]
+			}
+			return c.filterSuggestions(scripts, currentText)
+
+		case "/adopt":
+			currentText := ""
+			if c.currentIndex < len(c.segments) {
+				currentText = c.segments[c.currentIndex]
+			}
+			return c.filterSuggestions([]string{"kill"}, currentText)
+
+		case "/port":
+			currentText := ""
+			if c.currentIndex < len(c.segments) {
+				currentText = c.segments[c.currentIndex]
+			}
+			return c.filterSuggestions([]string{"kill", "reassign"}, currentText)
+
+		case "/errors":
+			currentText := ""
+			if c.currentIndex < len(c.segments) {
+				currentText = c.segments[c.currentIndex]
+			}
+			return c.filterSuggestions([]string{"all", "resolve", "ignore", "reopen"}, currentText)
+
 		case "/show", "/hide":
 			// Common patterns for log filtering
 			patterns := []string{"error", "warn", "info", "debug", "^\\[", "\\]$", "|"}
@@ -413,6 +454,15 @@ func (c *CommandAutocomplete) ValidateInput() (bool, string) {
 			return false, "Please specify a script name (e.g., /run dev)"
 		}
 		scriptName := parts[1]
+		if group, ok := strings.CutPrefix(scriptName, "@"); ok {
+			return c.validateGroup(group)
+		}
+		if _, exists := c.availableScripts[scriptName]; !exists && process.IsTaskTarget(scriptName) && c.processMgr != nil {
+			if _, err := c.processMgr.PlanTask(scriptName); err != nil {
+				return false, err.Error()
+			}
+			return true, ""
+		}
 		// Check if script exists
 		if _, exists := c.availableScripts[scriptName]; !exists {
 			return false, fmt.Sprintf("Script '%s' not found. Available: %s", scriptName, c.getAvailableScriptsString())
@@ -438,6 +488,9 @@ func (c *CommandAutocomplete) ValidateInput() (bool, string) {
 		if processName == "all" {
 			return true, ""
 		}
+		if group, ok := strings.CutPrefix(processName, "@"); ok && command == "/stop" {
+			return c.validateGroup(group)
+		}
 
 		// Check if process exists and is running
 		if c.processMgr != nil {
@@ -482,6 +535,14 @@ func (c *CommandAutocomplete) ValidateInput() (bool, string) {
 		}
 		return true, ""
 
+	case "/search":
+		// Without a query, /search returns to the live logs
+		query := strings.TrimSpace(strings.TrimPrefix(value, "/search"))
+		if _, err := logs.ParseQuery(query); err != nil {
+			return false, fmt.Sprintf("Invalid query: %v", err)
+		}
+		return true, ""
+
 	case "/proxy":
 		if len(parts) < 2 {
 			return false, "Please specify a URL (e.g. /proxy http://localhost:3000)"
@@ -497,6 +558,48 @@ func (c *CommandAutocomplete) ValidateInput() (bool, string) {
 		// No additional parameters needed
 		return true, ""
 
+	case "/env":
+		// Script name is optional; without one only the .env files are shown
+		return true, ""
+
+	case "/adopt":
+		if len(parts) >= 2 && parts[1] != "kill" {
+			return false, "Usage: /adopt to reattach to processes from a previous session, or /adopt kill to stop them"
+		}
+		return true, ""
+
+	case "/port":
+		usage := "Usage: /port [port], /port kill <port> [force] or /port reassign <script>"
+		switch {
+		case len(parts) == 1:
+			return true, ""
+		case len(parts) == 2:
+			if _, err := strconv.Atoi(parts[1]); err != nil {
+				return false, usage
+			}
+			return true, ""
+		case (len(parts) == 3 || len(parts) == 4 && parts[3] == "force") && parts[1] == "kill":
+			if _, err := strconv.Atoi(parts[2]); err != nil {
+				return false, fmt.Sprintf("Invalid port '%s'", parts[2])
+			}
+			return true, ""
+		case len(parts) == 3 && parts[1] == "reassign":
+			return true, ""
+		default:
+			return false, usage
+		}
+
+	case "/errors":
+		usage := "Usage: /errors [all], or /errors resolve|ignore|reopen <fingerprint>"
+		switch {
+		case len(parts) == 1 || (len(parts) == 2 && parts[1] == "all"):
+			return true, ""
+		case len(parts) == 3 && (parts[1] == "resolve" || parts[1] == "ignore" || parts[1] == "reopen"):
+			return true, ""
+		default:
+			return false, usage
+		}
+
 	case "/ai":
 		if len(parts) < 2 {
 			if len(c.aiProviders) == 0 {
@@ -534,12 +637,12 @@ func (c *CommandAutocomplete) ValidateInput() (bool, string) {
 
 	default:
 		// Check if it's a partial command
-		for _, cmd := range []string{"run", "restart", "stop", "clear", "show", "hide", "proxy", "toggle-proxy", "ai", "term", "help"} {
+		for _, cmd := range []string{"run", "restart", "stop", "clear", "show", "hide", "search", "proxy", "toggle-proxy", "env", "adopt", "port", "errors", "ai", "term", "help"} {
 			if strings.HasPrefix(cmd, strings.TrimPrefix(command, "/")) {
 				return false, fmt.Sprintf("Incomplete command. Did you mean /%s?", cmd)
 			}
 		}
-		return false, fmt.Sprintf("Unknown command: %s. Available commands: /run, /restart, /stop, /clear, /show, /hide, /proxy, /toggle-proxy, /ai, /term, /help", command)
+		return false, fmt.Sprintf("Unknown command: %s. Available commands: /run, /restart, /stop, /clear, /show, /hide, /search, /proxy, /toggle-proxy, /env, /adopt, /port, /errors, /ai, /term, /help", command)
 	}
 }
 
@@ -582,3 +685,31 @@ func (c *CommandAutocomplete) ClearError() {
 func (c *CommandAutocomplete) SetError(message string) {
 	c.errorMessage = message
 }
+
+// groupSuggestions returns the service groups as @name, sorted
+func (c *CommandAutocomplete) groupSuggestions() []string {
+	if c.processMgr == nil {
+		return nil
+	}
+	groups := make([]string, 0)
+	for group := range c.processMgr.Groups() {
+		groups = append(groups, "@"+group)
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// validateGroup checks that a group named in /run @group or /stop @group exists
+func (c *CommandAutocomplete) validateGroup(group string) (bool, string) {
+	if c.processMgr == nil {
+		return true, ""
+	}
+	if _, exists := c.processMgr.Groups()[group]; exists {
+		return true, ""
+	}
+	available := c.groupSuggestions()
+	if len(available) == 0 {
+		return false, fmt.Sprintf("Group '%s' not found. Set group on services in .brum.toml", group)
+	}
+	return false, fmt.Sprintf("Group '%s' not found. Available: %s", group, strings.Join(available, ", "))
+}
@@ -108,9 +108,16 @@ func (c *CommandWindowController) IsShowingCommandWindow() bool {
 // ShowRunDialog shows the run dialog with detected commands
 func (c *CommandWindowController) ShowRunDialog(commands []parser.ExecutableCommand, monorepoInfo *parser.MonorepoInfo) {
 	c.showingRunDialog = true
-	c.detectedCommands = commands
 	c.monorepoInfo = monorepoInfo
 
+	// Cargo crates and Go modules carry commands that run from the workspace root
+	if monorepoInfo != nil {
+		for _, pkg := range monorepoInfo.Packages {
+			commands = append(commands, pkg.Commands...)
+		}
+	}
+	c.detectedCommands = commands
+
 	// Convert commands to list items
 	var items []list.Item
 	for _, cmd := range commands {
@@ -361,5 +368,8 @@ func (i cmdDialogItem) Title() string {
 }
 
 func (i cmdDialogItem) Description() string {
+	if i.command.Category != "" && i.command.Description != "" {
+		return fmt.Sprintf("%s · %s", i.command.Category, i.command.Description)
+	}
 	return i.command.Command
 }
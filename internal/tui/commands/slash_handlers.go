@@ -1,7 +1,9 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -66,6 +68,9 @@ func HandleSlashCommand(ctx *SlashCommandContext, input string) {
 		}
 		*ctx.HidePattern = strings.Join(parts[1:], " ")
 
+	case "/search":
+		handleSearchCommand(ctx, strings.TrimSpace(strings.TrimPrefix(input, "/search")))
+
 	case "/run":
 		handleRunCommand(ctx, parts)
 
@@ -92,6 +97,18 @@ func HandleSlashCommand(ctx *SlashCommandContext, input string) {
 	case "/toggle-proxy":
 		ctx.ToggleProxy()
 
+	case "/env":
+		handleEnvCommand(ctx, parts)
+
+	case "/adopt":
+		handleAdoptCommand(ctx, parts)
+
+	case "/port":
+		handlePortCommand(ctx, parts)
+
+	case "/errors":
+		handleErrorsCommand(ctx, parts)
+
 	case "/ai":
 		if len(parts) < 2 {
 			ctx.LogStore.Add("system", "System", "Error: /ai command requires a provider name", true)
@@ -109,7 +126,7 @@ func HandleSlashCommand(ctx *SlashCommandContext, input string) {
 	default:
 		// Unknown command - show error
 		ctx.LogStore.Add("system", "System", fmt.Sprintf("❌ Unknown command: %s", command), true)
-		ctx.LogStore.Add("system", "System", "Available commands: /run, /restart, /stop, /clear, /show, /hide, /proxy, /toggle-proxy, /ai, /term, /help", false)
+		ctx.LogStore.Add("system", "System", "Available commands: /run, /restart, /stop, /clear, /show, /hide, /search, /proxy, /toggle-proxy, /env, /adopt, /port, /errors, /ai, /term, /help", false)
 	}
 }
 
@@ -120,12 +137,26 @@ func handleRunCommand(ctx *SlashCommandContext, parts []string) {
 	}
 	scriptName := parts[1]
 
-	// Execute the script
+	// @name starts a whole group of services
+	if group, ok := strings.CutPrefix(scriptName, "@"); ok {
+		handleRunGroup(ctx, group)
+		*ctx.CurrentView = "processes"
+		return
+	}
+
+	// package#task runs a Turborepo or Nx task after its upstream tasks
+	if _, isScript := ctx.ProcessManager.GetScripts()[scriptName]; !isScript && process.IsTaskTarget(scriptName) {
+		handleRunTask(ctx, scriptName)
+		*ctx.CurrentView = "processes"
+		return
+	}
+
+	// Execute the script, service or project task
 	errorHandler := NewStandardErrorHandler(ctx.LogStore, ctx.UpdateChan)
 	SafeGoroutine(
 		fmt.Sprintf("start script '%s'", scriptName),
 		func() error {
-			_, err := ctx.ProcessManager.StartScript(scriptName)
+			_, err := ctx.ProcessManager.StartScriptOrTask(scriptName)
 			if err == nil {
 				ctx.UpdateChan <- processUpdateMsg{}
 			}
@@ -141,6 +172,51 @@ func handleRunCommand(ctx *SlashCommandContext, parts []string) {
 	*ctx.CurrentView = "logs"
 }
 
+func handleRunGroup(ctx *SlashCommandContext, group string) {
+	SafeGoroutineNoError(
+		fmt.Sprintf("start group '%s'", group),
+		func() {
+			started, err := ctx.ProcessManager.StartGroup(group)
+			if len(started) > 0 {
+				names := make([]string, 0, len(started))
+				for _, proc := range started {
+					names = append(names, proc.Name)
+				}
+				ctx.LogStore.Add("system", "System", fmt.Sprintf("▶️ Started @%s: %s", group, strings.Join(names, ", ")), false)
+			} else if err == nil {
+				ctx.LogStore.Add("system", "System", fmt.Sprintf("Everything in @%s is already running", group), false)
+			}
+			if err != nil {
+				ctx.LogStore.Add("system", "System", fmt.Sprintf("Error starting @%s: %v", group, err), true)
+			}
+			ctx.UpdateChan <- processUpdateMsg{}
+		},
+		func(err error) {
+			ctx.LogStore.Add("system", "System", fmt.Sprintf("Critical error while starting @%s: %v", group, err), true)
+			ctx.UpdateChan <- logUpdateMsg{}
+		},
+	)
+}
+
+func handleRunTask(ctx *SlashCommandContext, target string) {
+	SafeGoroutineNoError(
+		fmt.Sprintf("run task '%s'", target),
+		func() {
+			run, err := ctx.ProcessManager.RunTask(target, 0)
+			if err != nil {
+				ctx.LogStore.Add("system", "System", fmt.Sprintf("Error running %s: %v", target, err), true)
+			} else {
+				ctx.LogStore.Add("system", "System", fmt.Sprintf("▶️ Running %s (%d tasks)", target, run.Summary().Total), false)
+			}
+			ctx.UpdateChan <- processUpdateMsg{}
+		},
+		func(err error) {
+			ctx.LogStore.Add("system", "System", fmt.Sprintf("Critical error while running %s: %v", target, err), true)
+			ctx.UpdateChan <- logUpdateMsg{}
+		},
+	)
+}
+
 func handleRestartCommand(ctx *SlashCommandContext, parts []string) {
 	processName := "all"
 	if len(parts) >= 2 {
@@ -232,7 +308,28 @@ func handleStopCommand(ctx *SlashCommandContext, parts []string) {
 		processName = parts[1]
 	}
 
-	if processName == "all" {
+	if group, ok := strings.CutPrefix(processName, "@"); ok {
+		// Stop every service in a group
+		SafeGoroutineNoError(
+			fmt.Sprintf("stop group '%s'", group),
+			func() {
+				stopped, err := ctx.ProcessManager.StopGroup(group)
+				if err != nil {
+					ctx.LogStore.Add("system", "System", fmt.Sprintf("Error stopping @%s: %v", group, err), true)
+				}
+				if len(stopped) > 0 {
+					ctx.LogStore.Add("system", "System", fmt.Sprintf("⏹️ Stopped @%s: %s", group, strings.Join(stopped, ", ")), false)
+				} else if err == nil {
+					ctx.LogStore.Add("system", "System", fmt.Sprintf("Nothing in @%s is running", group), false)
+				}
+				ctx.UpdateChan <- processUpdateMsg{}
+			},
+			func(err error) {
+				ctx.LogStore.Add("system", "System", fmt.Sprintf("Critical error while stopping @%s: %v", group, err), true)
+				ctx.UpdateChan <- logUpdateMsg{}
+			},
+		)
+	} else if processName == "all" {
 		// Stop all running processes
 		SafeGoroutineNoError(
 			"stop all processes",
@@ -276,10 +373,10 @@ func handleStopCommand(ctx *SlashCommandContext, parts []string) {
 					return
 				}
 
-				if err := ctx.ProcessManager.StopProcess(targetProc.ID); err != nil {
+				if stopped, err := ctx.ProcessManager.StopProcessWithDependents(targetProc.ID); err != nil {
 					ctx.LogStore.Add("system", "System", fmt.Sprintf("Error stopping process %s: %v", processName, err), true)
 				} else {
-					ctx.LogStore.Add("system", "System", fmt.Sprintf("⏹️ Stopped process: %s", processName), false)
+					ctx.LogStore.Add("system", "System", fmt.Sprintf("⏹️ Stopped process: %s", strings.Join(stopped, ", ")), false)
 				}
 				ctx.UpdateChan <- processUpdateMsg{}
 			},
@@ -292,6 +389,249 @@ func handleStopCommand(ctx *SlashCommandContext, parts []string) {
 	*ctx.CurrentView = "processes"
 }
 
+func handleEnvCommand(ctx *SlashCommandContext, parts []string) {
+	scriptName := ""
+	if len(parts) >= 2 {
+		scriptName = parts[1]
+	}
+
+	resolved, err := ctx.ProcessManager.ResolveEnvironment(scriptName)
+	if err != nil {
+		ctx.LogStore.Add("system", "System", fmt.Sprintf("❌ Failed to load environment: %v", err), true)
+		*ctx.CurrentView = "logs"
+		return
+	}
+
+	header := "🌱 Environment"
+	if scriptName != "" {
+		header += fmt.Sprintf(" for '%s'", scriptName)
+	}
+	if profile := ctx.ProcessManager.GetEnvProfile(); profile != "" {
+		header += fmt.Sprintf(" (profile: %s)", profile)
+	}
+	if files := resolved.Files(); len(files) > 0 {
+		header += fmt.Sprintf(" from %s", strings.Join(files, ", "))
+	}
+	ctx.LogStore.Add("system", "System", header, false)
+
+	// Secrets are masked; system variables are left out to keep the list readable
+	vars := resolved.Masked(false)
+	if len(vars) == 0 {
+		ctx.LogStore.Add("system", "System", "  (no variables from .env files or .brum.toml)", false)
+	}
+	for _, v := range vars {
+		ctx.LogStore.Add("system", "System", fmt.Sprintf("  %s=%s  [%s]", v.Name, v.Value, v.Source), false)
+	}
+	*ctx.CurrentView = "logs"
+}
+
+// searchResultLimit is how many matches /search shows, newest first
+const searchResultLimit = 1000
+
+// handleSearchCommand shows the entries matching a query in the logs view, reaching
+// into the history on disk. Without a query it returns to the live logs.
+func handleSearchCommand(ctx *SlashCommandContext, query string) {
+	*ctx.CurrentView = "logs"
+	if query == "" {
+		ctx.UpdateLogsView()
+		return
+	}
+
+	results, err := ctx.LogStore.SearchPage(query, time.Time{}, searchResultLimit)
+	if err != nil {
+		ctx.LogStore.Add("system", "System", fmt.Sprintf("❌ Invalid query: %v", err), true)
+		return
+	}
+	if results == nil {
+		results = []logs.LogEntry{} // an empty search still replaces the live logs
+	}
+	*ctx.SearchResults = results
+	ctx.UpdateLogsView()
+}
+
+// handleAdoptCommand reattaches to, or with "kill" stops, processes left running
+// by a previous session
+func handleAdoptCommand(ctx *SlashCommandContext, parts []string) {
+	*ctx.CurrentView = "logs"
+	if len(ctx.ProcessManager.Orphans()) == 0 {
+		ctx.LogStore.Add("system", "System", "No processes from a previous session are waiting to be adopted", false)
+		return
+	}
+
+	if len(parts) >= 2 && parts[1] == "kill" {
+		for _, orphan := range ctx.ProcessManager.KillOrphans() {
+			ctx.LogStore.Add("system", "System", fmt.Sprintf("🛑 Stopped '%s' (PID %d) from a previous session", orphan.Name, orphan.PID), false)
+		}
+		return
+	}
+
+	// Adopted processes announce themselves through ProcessStarted events
+	if _, err := ctx.ProcessManager.AdoptOrphans(); err != nil {
+		ctx.LogStore.Add("system", "System", fmt.Sprintf("❌ %v", err), true)
+	}
+}
+
+// handlePortCommand shows who listens on a port, kills the owner, or moves a
+// script to another assigned port
+func handlePortCommand(ctx *SlashCommandContext, parts []string) {
+	*ctx.CurrentView = "logs"
+	usage := "Usage: /port [port], /port kill <port> [force] or /port reassign <script>"
+
+	switch {
+	case len(parts) == 1:
+		owners, err := ctx.ProcessManager.ListeningPorts()
+		if err != nil {
+			ctx.LogStore.Add("system", "System", fmt.Sprintf("❌ %v", err), true)
+			return
+		}
+		ctx.LogStore.Add("system", "System", fmt.Sprintf("🔌 %d listening ports:", len(owners)), false)
+		for _, owner := range owners {
+			ctx.LogStore.Add("system", "System", fmt.Sprintf("  %s:%d  %s", owner.Address, owner.Port, owner.Describe()), false)
+		}
+
+	case parts[1] == "kill" && (len(parts) == 3 || len(parts) == 4 && parts[3] == "force"):
+		port, err := strconv.Atoi(parts[2])
+		if err != nil {
+			ctx.LogStore.Add("system", "System", usage, true)
+			return
+		}
+		force := len(parts) == 4
+		SafeGoroutineNoError(
+			fmt.Sprintf("kill owner of port %d", port),
+			func() {
+				killed, err := ctx.ProcessManager.KillPortOwner(port, force)
+				for _, owner := range killed {
+					ctx.LogStore.Add("system", "System", fmt.Sprintf("🛑 Stopped %s holding port %d", owner.Describe(), port), false)
+				}
+				var unmanaged *process.UnmanagedPortOwnerError
+				switch {
+				case errors.As(err, &unmanaged):
+					ctx.LogStore.Add("system", "System", fmt.Sprintf("⚠️ %v. Run /port kill %d force to kill it", err, port), true)
+				case err != nil:
+					ctx.LogStore.Add("system", "System", fmt.Sprintf("❌ %v", err), true)
+				}
+				ctx.UpdateChan <- processUpdateMsg{}
+			},
+			func(err error) {
+				ctx.LogStore.Add("system", "System", fmt.Sprintf("Critical error while freeing port %d: %v", port, err), true)
+				ctx.UpdateChan <- logUpdateMsg{}
+			},
+		)
+
+	case parts[1] == "reassign" && len(parts) == 3:
+		name := parts[2]
+		SafeGoroutineNoError(
+			fmt.Sprintf("reassign port of '%s'", name),
+			func() {
+				port, err := ctx.ProcessManager.UseAnotherPort(name)
+				if err != nil {
+					ctx.LogStore.Add("system", "System", fmt.Sprintf("❌ Failed to move '%s' to another port: %v", name, err), true)
+				} else {
+					ctx.LogStore.Add("system", "System", fmt.Sprintf("🔌 Restarted '%s' with PORT=%d", name, port), false)
+				}
+				ctx.UpdateChan <- processUpdateMsg{}
+			},
+			func(err error) {
+				ctx.LogStore.Add("system", "System", fmt.Sprintf("Critical error while moving '%s' to another port: %v", name, err), true)
+				ctx.UpdateChan <- logUpdateMsg{}
+			},
+		)
+
+	case len(parts) == 2:
+		port, err := strconv.Atoi(parts[1])
+		if err != nil {
+			ctx.LogStore.Add("system", "System", usage, true)
+			return
+		}
+		owners, err := ctx.ProcessManager.InspectPort(port)
+		if err != nil {
+			ctx.LogStore.Add("system", "System", fmt.Sprintf("❌ %v", err), true)
+			return
+		}
+		if len(owners) == 0 {
+			ctx.LogStore.Add("system", "System", fmt.Sprintf("Nothing is listening on port %d", port), false)
+			return
+		}
+		for _, owner := range owners {
+			ctx.LogStore.Add("system", "System", fmt.Sprintf("🔌 Port %d (%s) is held by %s", port, owner.Address, owner.Describe()), false)
+		}
+
+	default:
+		ctx.LogStore.Add("system", "System", usage, true)
+	}
+}
+
+// handleErrorsCommand lists the errors seen in this project with their occurrences,
+// or marks one resolved, ignored or open again by its fingerprint
+func handleErrorsCommand(ctx *SlashCommandContext, parts []string) {
+	*ctx.CurrentView = "logs"
+	usage := "Usage: /errors [all], or /errors resolve|ignore|reopen <fingerprint>"
+
+	switch {
+	case len(parts) == 1 || (len(parts) == 2 && parts[1] == "all"):
+		fingerprints, err := ctx.LogStore.GetErrorFingerprints()
+		if err != nil {
+			ctx.LogStore.Add("system", "System", fmt.Sprintf("❌ %v", err), true)
+			return
+		}
+		// Shown like search results, so the listing is not logged and counted itself
+		results := []logs.LogEntry{}
+		for _, fp := range fingerprints {
+			if len(parts) == 1 && fp.Status != logs.ErrorOpen {
+				continue
+			}
+			results = append(results, logs.LogEntry{
+				ProcessID:   "system",
+				ProcessName: "errors",
+				Timestamp:   fp.LastSeen,
+				Content:     describeFingerprint(fp),
+				Level:       logs.LevelInfo,
+			})
+		}
+		*ctx.SearchResults = results
+		ctx.UpdateLogsView()
+
+	case len(parts) == 3:
+		statuses := map[string]logs.ErrorStatus{
+			"resolve": logs.ErrorResolved,
+			"ignore":  logs.ErrorIgnored,
+			"reopen":  logs.ErrorOpen,
+		}
+		status, ok := statuses[parts[1]]
+		if !ok {
+			ctx.LogStore.Add("system", "System", usage, true)
+			return
+		}
+		fp, err := ctx.LogStore.SetErrorStatus(parts[2], status)
+		if err != nil {
+			ctx.LogStore.Add("system", "System", fmt.Sprintf("❌ %v", err), true)
+			return
+		}
+		switch status {
+		case logs.ErrorResolved:
+			ctx.LogStore.Add("system", "System", fmt.Sprintf("✅ Marked %s resolved; it reopens if it happens again", fp.Fingerprint), false)
+		case logs.ErrorIgnored:
+			ctx.LogStore.Add("system", "System", fmt.Sprintf("🙈 Ignoring %s from now on", fp.Fingerprint), false)
+		default:
+			ctx.LogStore.Add("system", "System", fmt.Sprintf("↩️ Reopened %s", fp.Fingerprint), false)
+		}
+
+	default:
+		ctx.LogStore.Add("system", "System", usage, true)
+	}
+}
+
+// describeFingerprint summarizes the occurrences of an error on one line
+func describeFingerprint(fp logs.ErrorFingerprint) string {
+	status := string(fp.Status)
+	if fp.Regressed {
+		status += " (regressed)"
+	}
+	return fmt.Sprintf("%s  %-19s %4d×  since %s  [%s]  %s: %s",
+		fp.Fingerprint, status, fp.Count, fp.FirstSeen.Format("2006-01-02 15:04"),
+		strings.Join(fp.Processes, ", "), fp.Type, fp.Message)
+}
+
 // Message types used for updates
 type logUpdateMsg struct{}
 type processUpdateMsg struct{}
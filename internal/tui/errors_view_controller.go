@@ -139,6 +139,10 @@ func (v *ErrorsViewController) UpdateErrorsView() {
 				errorTypeStyle.Render(errorCtx.Type),
 			))
 
+			if occurrences := v.describeOccurrences(&errorCtx); occurrences != "" {
+				content.WriteString(timeStyle.Render(occurrences) + "\n")
+			}
+
 			// Main error message
 			content.WriteString(messageStyle.Render(errorCtx.Message) + "\n")
 
@@ -243,6 +247,10 @@ func (v *ErrorsViewController) UpdateErrorDetailView() {
 		headerStyle.Render(v.selectedError.Type),
 	))
 
+	if occurrences := v.describeOccurrences(v.selectedError); occurrences != "" {
+		content.WriteString(timeStyle.Render(occurrences) + "\n\n")
+	}
+
 	// Main error message
 	messageStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
 	content.WriteString(messageStyle.Render("Error Message:") + "\n")
@@ -259,12 +267,17 @@ func (v *ErrorsViewController) UpdateErrorDetailView() {
 	if len(v.selectedError.Stack) > 0 {
 		stackStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
 		content.WriteString(stackStyle.Render("Stack Trace:") + "\n")
+		generatedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("237"))
 		for i, stackLine := range v.selectedError.Stack {
 			if i > 10 { // Show more lines in detail view
 				content.WriteString(stackStyle.Render(fmt.Sprintf("  ... and %d more lines", len(v.selectedError.Stack)-i)) + "\n")
 				break
 			}
 			content.WriteString(stackStyle.Render("  "+strings.TrimSpace(stackLine)) + "\n")
+			// Keep the minified frame a source map replaced, for reference
+			if i < len(v.selectedError.GeneratedStack) && v.selectedError.GeneratedStack[i] != stackLine {
+				content.WriteString(generatedStyle.Render("    ↳ "+strings.TrimSpace(v.selectedError.GeneratedStack[i])) + "\n")
+			}
 		}
 		content.WriteString("\n")
 	}
@@ -283,6 +296,24 @@ func (v *ErrorsViewController) UpdateErrorDetailView() {
 	v.errorDetailView.SetContent(content.String())
 }
 
+// describeOccurrences tells how often an error has happened, across restarts, and
+// how to mark it resolved
+func (v *ErrorsViewController) describeOccurrences(errorCtx *logs.ErrorContext) string {
+	fp, ok := v.logStore.GetErrorFingerprint(errorCtx.Fingerprint)
+	if !ok {
+		return ""
+	}
+	seen := fmt.Sprintf("seen %d× since %s", fp.Count, fp.FirstSeen.Format("2006-01-02 15:04"))
+	if fp.Count == 1 {
+		seen = "first seen " + fp.FirstSeen.Format("2006-01-02 15:04")
+	}
+	if fp.Regressed {
+		seen += ", back after it was resolved"
+	}
+	return fmt.Sprintf("#%s  %s in %s  (/errors resolve|ignore %s)",
+		fp.Fingerprint, seen, strings.Join(fp.Processes, ", "), fp.Fingerprint[:min(8, len(fp.Fingerprint))])
+}
+
 // HandleClearErrors clears all errors and logs the action
 func (v *ErrorsViewController) HandleClearErrors() {
 	v.logStore.ClearErrors()
@@ -307,6 +338,9 @@ func (v *ErrorsViewController) HandleCopyError() tea.Cmd {
 			builder.WriteString(fmt.Sprintf("Process: %s\n", errorCtx.ProcessName))
 			builder.WriteString(fmt.Sprintf("Time: %s\n", errorCtx.Timestamp.Format("2006-01-02 15:04:05")))
 			builder.WriteString(fmt.Sprintf("Message: %s\n", errorCtx.Message))
+			if errorCtx.Fingerprint != "" {
+				builder.WriteString(fmt.Sprintf("Fingerprint: %s\n", errorCtx.Fingerprint))
+			}
 
 			if len(errorCtx.Stack) > 0 {
 				builder.WriteString("\nStack Trace:\n")
@@ -315,6 +349,13 @@ func (v *ErrorsViewController) HandleCopyError() tea.Cmd {
 				}
 			}
 
+			if len(errorCtx.GeneratedStack) > 0 {
+				builder.WriteString("\nGenerated Stack Trace:\n")
+				for _, line := range errorCtx.GeneratedStack {
+					builder.WriteString(fmt.Sprintf("  %s\n", strings.TrimSpace(line)))
+				}
+			}
+
 			if len(errorCtx.Context) > 0 {
 				builder.WriteString("\nContext:\n")
 				for _, line := range errorCtx.Context {
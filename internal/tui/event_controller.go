@@ -52,6 +52,65 @@ func (ec *EventController) SetupEventSubscriptions() {
 		ec.updateChan <- processUpdateMsg{}
 	})
 
+	// Redraw an attached process terminal as its screen changes
+	ec.eventBus.Subscribe(events.ProcessTerminal, func(e events.Event) {
+		if proc := ec.model.processViewController.AttachedTerminal(); proc != nil && proc.ID == e.ProcessID {
+			ec.updateChan <- terminalUpdateMsg{}
+		}
+	})
+
+	// Surface prompts from processes waiting on stdin
+	ec.eventBus.Subscribe(events.ProcessAwaitingInput, func(e events.Event) {
+		name, _ := e.Data["name"].(string)
+		prompt, _ := e.Data["prompt"].(string)
+		ec.updateChan <- system.SystemMessageMsg{
+			Level:   "info",
+			Context: "Process Input",
+			Message: fmt.Sprintf("⌨️ %s is waiting for input: %s (select it in Processes and press 'i')", name, prompt),
+		}
+		ec.updateChan <- processUpdateMsg{}
+	})
+
+	// Offer to resolve ports a process could not listen on
+	ec.eventBus.Subscribe(events.ProcessPortConflict, func(e events.Event) {
+		message, _ := e.Data["message"].(string)
+		ec.updateChan <- system.SystemMessageMsg{
+			Level:   "error",
+			Context: "Port Conflict",
+			Message: message,
+		}
+	})
+
+	// Report the outcome of a script run across workspace packages
+	ec.eventBus.Subscribe(events.MatrixFinished, func(e events.Event) {
+		message, _ := e.Data["message"].(string)
+		level := "success"
+		if failed, _ := e.Data["failed"].(int); failed > 0 {
+			level = "error"
+		}
+		ec.updateChan <- system.SystemMessageMsg{
+			Level:   level,
+			Context: "Matrix Run",
+			Message: message,
+		}
+		ec.updateChan <- processUpdateMsg{}
+	})
+
+	// Report the outcome of a task run with its upstream tasks
+	ec.eventBus.Subscribe(events.TaskRunFinished, func(e events.Event) {
+		message, _ := e.Data["message"].(string)
+		level := "success"
+		if failed, _ := e.Data["failed"].(int); failed > 0 {
+			level = "error"
+		}
+		ec.updateChan <- system.SystemMessageMsg{
+			Level:   level,
+			Context: "Task Run",
+			Message: message,
+		}
+		ec.updateChan <- processUpdateMsg{}
+	})
+
 	// Log events
 	ec.eventBus.Subscribe(events.LogLine, func(e events.Event) {
 		ec.updateChan <- logUpdateMsg{}
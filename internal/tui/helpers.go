@@ -39,6 +39,31 @@ func formatSize(bytes int64) string {
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
 
+// sparkline renders the last width values as a bar chart scaled to their maximum
+func sparkline(values []float64, width int) string {
+	bars := []rune("▁▂▃▄▅▆▇█")
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	max := 0.0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		idx := 0
+		if max > 0 {
+			idx = int(v / max * float64(len(bars)-1))
+		}
+		b.WriteRune(bars[idx])
+	}
+	return b.String()
+}
+
 // renderExitScreen renders the exit screen with Brummer bee logo
 func renderExitScreen() string {
 	bee := lipgloss.NewStyle().
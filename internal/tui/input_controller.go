@@ -41,6 +41,28 @@ func (ic *InputController) HandleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd, boo
 		return ic.handleCommandWindow(msg)
 	}
 
+	// An attached process terminal receives every key except the detach key
+	if ic.model.currentView() == ViewProcesses {
+		if proc := ic.model.processViewController.AttachedTerminal(); proc != nil {
+			if msg.String() == "ctrl+q" {
+				ic.model.processViewController.DetachTerminal()
+				return ic.model, nil, true
+			}
+			if input := keyMsgToTerminalInput(msg); len(input) > 0 {
+				_ = proc.WriteInput(input) // Input to an exited process is dropped
+			}
+			return ic.model, nil, true
+		}
+
+		if ic.model.processViewController.InputTarget() != nil {
+			cmd, err := ic.model.processViewController.HandleInputKey(msg)
+			if err != nil {
+				ic.model.systemController.AddMessage("error", "Process Input", err.Error())
+			}
+			return ic.model, cmd, true
+		}
+	}
+
 	// Handle "/" key for Brummer commands - check if we should intercept it
 	if msg.String() == "/" || (msg.Type == tea.KeyRunes && len(msg.Runes) == 1 && msg.Runes[0] == '/') {
 		// Check if we should intercept the slash command
@@ -161,7 +161,7 @@ func (lc *LayoutController) RenderSystemPanel() string {
 	}
 
 	// Get system logs
-	systemLogs := lc.logStore.GetByProcess("system")
+	systemLogs := lc.logStore.GetByProcessInMemory("system")
 
 	// Take last few logs that fit in panel
 	maxLogs := lc.systemPanelHeight - 2 // Account for border
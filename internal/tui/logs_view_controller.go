@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -34,7 +35,7 @@ type LogsViewController struct {
 // NewLogsViewController creates a new logs view controller
 func NewLogsViewController(logStore *logs.Store) *LogsViewController {
 	searchInput := textinput.New()
-	searchInput.Placeholder = "Commands: /show <pattern> | /hide <pattern>"
+	searchInput.Placeholder = "Commands: /show <pattern> | /hide <pattern> | /search <query>"
 	searchInput.Focus()
 
 	return &LogsViewController{
@@ -130,7 +131,17 @@ func (v *LogsViewController) GetLogsViewport() *viewport.Model {
 func (v *LogsViewController) UpdateLogsView() {
 	var collapsedEntries []logs.CollapsedLogEntry
 
-	if v.selectedProcess != "" {
+	if v.searchResults != nil {
+		// Show the matches of the last /search
+		for _, entry := range v.searchResults {
+			collapsedEntries = append(collapsedEntries, logs.CollapsedLogEntry{
+				LogEntry:  entry,
+				Count:     1,
+				FirstSeen: entry.Timestamp,
+				LastSeen:  entry.Timestamp,
+			})
+		}
+	} else if v.selectedProcess != "" {
 		// Show logs for specific process
 		collapsedEntries = v.logStore.GetByProcessCollapsed(v.selectedProcess)
 	} else {
@@ -213,6 +224,9 @@ func (v *LogsViewController) formatLogsForDisplay(entries []logs.CollapsedLogEnt
 
 		// Format content
 		cleanContent := v.cleanLogContent(entry.LogEntry.Content)
+		if entry.LogEntry.Structured != nil {
+			cleanContent = v.formatStructuredContent(entry.LogEntry)
+		}
 
 		// Add collapse indicator if needed
 		var collapseIndicator string
@@ -234,6 +248,52 @@ func (v *LogsViewController) formatLogsForDisplay(entries []logs.CollapsedLogEnt
 	return content.String()
 }
 
+// structuredLevelLabels name levels in front of the message of a structured line
+var structuredLevelLabels = map[logs.LogLevel]string{
+	logs.LevelDebug:    "DEBUG",
+	logs.LevelInfo:     "INFO ",
+	logs.LevelWarn:     "WARN ",
+	logs.LevelError:    "ERROR",
+	logs.LevelCritical: "FATAL",
+}
+
+// hiddenStructuredAttributes are written by pino and bunyan on every line
+var hiddenStructuredAttributes = map[string]bool{"pid": true, "hostname": true, "v": true}
+
+// formatStructuredContent shows a JSON or logfmt line as its level, message and
+// attributes, with the error and stack on the lines below
+func (v *LogsViewController) formatStructuredContent(entry logs.LogEntry) string {
+	structured := entry.Structured
+	var line strings.Builder
+	line.WriteString(structuredLevelLabels[entry.Level])
+	if structured.Message != "" {
+		line.WriteString(" " + structured.Message)
+	}
+	for _, key := range structured.SortedAttributes() {
+		if hiddenStructuredAttributes[key] {
+			continue
+		}
+		value := structured.Attributes[key]
+		if value == "" || strings.ContainsAny(value, " \t\"") {
+			value = strconv.Quote(value)
+		}
+		line.WriteString(fmt.Sprintf(" %s=%s", key, value))
+	}
+	if structured.Error != "" {
+		line.WriteString("\n    error: " + structured.Error)
+	}
+	if structured.Stack != "" {
+		frames := strings.Split(strings.TrimSpace(structured.Stack), "\n")
+		if strings.TrimSpace(frames[0]) == structured.Error {
+			frames = frames[1:] // JavaScript stacks start with the error itself
+		}
+		for _, frame := range frames {
+			line.WriteString("\n    " + strings.TrimSpace(frame))
+		}
+	}
+	return line.String()
+}
+
 // cleanLogContent cleans log content for display
 func (v *LogsViewController) cleanLogContent(content string) string {
 	// Keep the original content with ANSI codes
@@ -185,6 +185,8 @@ type keyMap struct {
 	Stop          key.Binding
 	Restart       key.Binding
 	RestartAll    key.Binding
+	Attach        key.Binding
+	Input         key.Binding
 	CopyError     key.Binding
 	Priority      key.Binding
 	ClearLogs     key.Binding
@@ -242,6 +244,14 @@ var keys = keyMap{
 		key.WithKeys("ctrl+r"),
 		key.WithHelp("ctrl+r", "restart all"),
 	),
+	Attach: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "attach terminal"),
+	),
+	Input: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "send input"),
+	),
 	CopyError: key.NewBinding(
 		key.WithKeys("c"),
 		key.WithHelp("c", "copy recent error"),
@@ -292,7 +302,7 @@ func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Enter, k.Back},
 		{k.Tab, k.Command, k.Filter, k.Priority},
-		{k.Stop, k.Restart, k.RestartAll, k.CopyError},
+		{k.Stop, k.Restart, k.RestartAll, k.Attach, k.Input, k.CopyError},
 		{k.ClearLogs, k.ClearErrors, k.ToggleError, k.ClearMessages, k.Help, k.Quit},
 	}
 }
@@ -327,6 +337,7 @@ type processItem struct {
 	process    *process.Process
 	isHeader   bool
 	headerText string
+	restart    *process.RestartState
 }
 
 func (i processItem) FilterValue() string {
@@ -357,6 +368,9 @@ func (i processItem) Title() string {
 	switch state.Status {
 	case process.StatusRunning:
 		statusEmoji = "🟢"
+		if i.process.GetHealth() == process.HealthUnhealthy {
+			statusEmoji = "🟠"
+		}
 	case process.StatusStopped:
 		statusEmoji = "✓" // Thin checkmark for gracefully stopped
 	case process.StatusFailed:
@@ -402,6 +416,9 @@ func (i processItem) Description() string {
 	if state.IsFinished() && state.ExitCode != nil {
 		parts = append(parts, fmt.Sprintf("Exit: %d", *state.ExitCode))
 	}
+	if i.process.ExitReason() == process.ExitReasonOOM {
+		parts = append(parts, "💥 Out of memory")
+	}
 
 	// Add runtime if process has ended
 	if state.EndTime != nil {
@@ -409,10 +426,51 @@ func (i processItem) Description() string {
 		parts = append(parts, fmt.Sprintf("Runtime: %s", runtime.Round(time.Millisecond).String()))
 	}
 
+	// Add health probe state
+	switch i.process.GetHealth() {
+	case process.HealthHealthy:
+		parts = append(parts, "💚 Healthy")
+	case process.HealthUnhealthy:
+		parts = append(parts, "💔 Unhealthy")
+	}
+
+	// Add restart policy state
+	if i.restart != nil {
+		switch {
+		case i.restart.CrashLoop:
+			parts = append(parts, fmt.Sprintf("⚠️ Crash loop (%d restarts)", i.restart.Restarts))
+		case i.restart.NextRestart != nil:
+			wait := time.Until(*i.restart.NextRestart).Round(time.Second)
+			parts = append(parts, fmt.Sprintf("🔁 Restart %d/%d in %s", i.restart.Restarts, i.restart.MaxRetries, wait))
+		default:
+			parts = append(parts, fmt.Sprintf("🔁 %s (%d/%d)", i.restart.Policy, i.restart.Restarts, i.restart.MaxRetries))
+		}
+	}
+
+	// Add resource usage trend
+	if state.IsRunning() {
+		if samples := i.process.Metrics(); len(samples) > 0 {
+			cpu := make([]float64, len(samples))
+			mem := make([]float64, len(samples))
+			for j, sample := range samples {
+				cpu[j] = sample.CPUPercent
+				mem[j] = float64(sample.RSSBytes)
+			}
+			latest := samples[len(samples)-1]
+			parts = append(parts, fmt.Sprintf("CPU %s %.0f%% · Mem %s %s · FDs %d",
+				sparkline(cpu, 12), latest.CPUPercent, sparkline(mem, 12), formatSize(int64(latest.RSSBytes)), latest.OpenFDs))
+		}
+	}
+
+	// Add pending prompt
+	if prompt := i.process.AwaitingInput(); prompt != "" && state.IsRunning() {
+		parts = append(parts, fmt.Sprintf("⌨️ Waiting for input: %s", prompt))
+	}
+
 	// Add actions
 	var actions string
 	if state.IsRunning() {
-		actions = "Press 's' to stop, 'r' to restart"
+		actions = "Press 's' to stop, 'r' to restart, 'i' to send input"
 	} else {
 		actions = "Press 'Enter' to view logs"
 	}
@@ -563,7 +621,7 @@ func NewModel(processMgr *process.Manager, logStore *logs.Store, eventBus *event
 }
 
 func NewModelWithView(processMgr *process.Manager, logStore *logs.Store, eventBus *events.EventBus, mcpServer MCPServerInterface, proxyServer *proxy.Server, mcpPort int, initialView View, debugMode bool, cfg *config.Config) *Model {
-	scripts := processMgr.GetScripts()
+	scripts := runnableScripts(processMgr)
 
 	// processesList initialization moved to ProcessViewController
 
@@ -803,6 +861,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.updateProcessList()
 		case ViewLogs, ViewURLs:
 			m.updateLogsView()
+		case ViewErrors:
+			// Picks up stacks resolved through source maps since the error was logged
+			m.updateErrorsList()
 		}
 
 	case mcpActivityMsg:
@@ -1465,7 +1526,7 @@ func (m *Model) handleClearCommand(target string) {
 		}
 	default:
 		// Check if it's a script name
-		scripts := m.processMgr.GetScripts()
+		scripts := runnableScripts(m.processMgr)
 		if _, ok := scripts[target]; ok {
 			// Clear logs for a specific process
 			m.logStore.ClearLogsForProcess(target)
@@ -1544,7 +1605,7 @@ func (m *Model) handleToggleProxy() {
 }
 
 func (m *Model) showCommandWindow() {
-	scripts := m.processMgr.GetScripts()
+	scripts := runnableScripts(m.processMgr)
 
 	// Get available AI providers from AI coder controller (with nil check)
 	var aiProviders []string
@@ -115,7 +115,7 @@ func (ma *ModelAdapter) IsCommandWindowVisible() bool {
 
 func (ma *ModelAdapter) ShowCommandWindow() {
 	// Get scripts and AI providers
-	scripts := ma.model.processMgr.GetScripts()
+	scripts := runnableScripts(ma.model.processMgr)
 	var aiProviders []string
 	if ma.model.aiCoderController != nil {
 		aiProviders = ma.model.aiCoderController.GetProviders()
@@ -248,6 +248,14 @@ func TestProcessStatusFormatting(t *testing.T) {
 	}
 }
 
+// TestSparkline tests that values are scaled to the maximum and trimmed to width
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "▁▄█", sparkline([]float64{0, 50, 100}, 10))
+	assert.Equal(t, "▁█", sparkline([]float64{100, 0, 100}, 2))
+	assert.Equal(t, "▁▁", sparkline([]float64{0, 0}, 5))
+	assert.Equal(t, "", sparkline(nil, 5))
+}
+
 // TestURLValidation tests URL parsing and validation
 func TestURLValidation(t *testing.T) {
 	testURLs := []struct {
@@ -15,7 +15,7 @@ func NewProcessMessageHandler() MessageHandler {
 // CanHandle checks if this handler can process the message
 func (h *ProcessMessageHandler) CanHandle(msg tea.Msg) bool {
 	switch msg.(type) {
-	case processUpdateMsg, processStoppedMsg, processStartedMsg:
+	case processUpdateMsg, processStoppedMsg, processStartedMsg, terminalUpdateMsg:
 		return true
 	default:
 		return false
@@ -41,6 +41,10 @@ func (h *ProcessMessageHandler) HandleMessage(msg tea.Msg, model *Model) (tea.Mo
 		// Handle process started - update process list
 		model.updateProcessList()
 		cmds = append(cmds, model.waitForUpdates())
+
+	case terminalUpdateMsg:
+		// The attached terminal is redrawn by the view; just keep listening
+		cmds = append(cmds, model.waitForUpdates())
 	}
 
 	return model, tea.Batch(cmds...)
@@ -3,9 +3,11 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/standardbeagle/brummer/internal/process"
@@ -14,6 +16,9 @@ import (
 // Message types for process operations
 type processUpdateMsg struct{}
 
+// terminalUpdateMsg signals new output on the attached process terminal
+type terminalUpdateMsg struct{}
+
 type restartProcessMsg struct {
 	processName string
 	message     string
@@ -40,6 +45,13 @@ type ProcessViewController struct {
 	headerHeight  int
 	footerHeight  int
 	contentHeight int // Pre-calculated content height
+
+	// Process whose terminal is shown and receives keystrokes, if any
+	attached atomic.Pointer[process.Process]
+
+	// Line input sent to a process's stdin
+	inputTarget *process.Process
+	input       textinput.Model
 }
 
 // NewProcessViewController creates a new process view controller
@@ -48,9 +60,13 @@ func NewProcessViewController(processMgr *process.Manager) *ProcessViewControlle
 	processesList.Title = "Running Processes"
 	processesList.SetShowStatusBar(false)
 
+	input := textinput.New()
+	input.Placeholder = "type a response and press enter"
+
 	return &ProcessViewController{
 		processesList: processesList,
 		processMgr:    processMgr,
+		input:         input,
 	}
 }
 
@@ -62,7 +78,10 @@ func (v *ProcessViewController) UpdateSize(width, height, headerHeight, footerHe
 	v.footerHeight = footerHeight
 	v.contentHeight = contentHeight
 
-	v.processesList.SetSize(width, contentHeight)
+	v.processesList.SetSize(width, v.listHeight())
+	if proc := v.attached.Load(); proc != nil {
+		v.resizeTerminal(proc)
+	}
 }
 
 // SetSelectedProcess sets the currently selected process
@@ -109,9 +128,7 @@ func (v *ProcessViewController) UpdateProcessList() {
 				isHeader:   true,
 			})
 			for _, proc := range running {
-				items = append(items, processItem{
-					process: proc,
-				})
+				items = append(items, v.newProcessItem(proc))
 			}
 		}
 
@@ -129,9 +146,7 @@ func (v *ProcessViewController) UpdateProcessList() {
 				isHeader:   true,
 			})
 			for _, proc := range stopped {
-				items = append(items, processItem{
-					process: proc,
-				})
+				items = append(items, v.newProcessItem(proc))
 			}
 		}
 	}
@@ -139,8 +154,130 @@ func (v *ProcessViewController) UpdateProcessList() {
 	v.processesList.SetItems(items)
 }
 
+// newProcessItem builds a list item for a process, including its restart state
+func (v *ProcessViewController) newProcessItem(proc *process.Process) processItem {
+	item := processItem{process: proc}
+	if restart, ok := v.processMgr.GetRestartState(proc.Name); ok {
+		item.restart = &restart
+	}
+	return item
+}
+
+// listHeight is the process list height, leaving room for the input line when it is open
+func (v *ProcessViewController) listHeight() int {
+	if v.inputTarget != nil && v.contentHeight > 2 {
+		return v.contentHeight - 2
+	}
+	return v.contentHeight
+}
+
+// StartInput opens a line input whose text is sent to the process's stdin
+func (v *ProcessViewController) StartInput(proc *process.Process) error {
+	if proc.GetStatus() != process.StatusRunning {
+		return fmt.Errorf("process '%s' is not running", proc.Name)
+	}
+	v.inputTarget = proc
+	v.input.Reset()
+	v.input.Focus()
+	v.processesList.SetSize(v.width, v.listHeight())
+	return nil
+}
+
+// CancelInput closes the line input without sending anything
+func (v *ProcessViewController) CancelInput() {
+	v.inputTarget = nil
+	v.input.Blur()
+	v.processesList.SetSize(v.width, v.listHeight())
+}
+
+// InputTarget returns the process the line input is addressed to, or nil
+func (v *ProcessViewController) InputTarget() *process.Process {
+	return v.inputTarget
+}
+
+// HandleInputKey handles a key while the line input is open. Enter sends the line,
+// ctrl+d sends end-of-file and esc cancels.
+func (v *ProcessViewController) HandleInputKey(msg tea.KeyMsg) (tea.Cmd, error) {
+	proc := v.inputTarget
+
+	switch msg.String() {
+	case "esc":
+		v.CancelInput()
+		return nil, nil
+	case "enter":
+		text := v.input.Value()
+		v.CancelInput()
+		return nil, proc.WriteInput([]byte(text + "\n"))
+	case "ctrl+d":
+		v.CancelInput()
+		return nil, proc.CloseInput()
+	}
+
+	var cmd tea.Cmd
+	v.input, cmd = v.input.Update(msg)
+	return cmd, nil
+}
+
+// renderInput renders the line input shown beneath the process list
+func (v *ProcessViewController) renderInput() string {
+	label := fmt.Sprintf("⌨️  Input to %s", v.inputTarget.Name)
+	if prompt := v.inputTarget.AwaitingInput(); prompt != "" {
+		label += fmt.Sprintf(" (%s)", prompt)
+	}
+	hint := lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Render("  enter send · ctrl+d EOF · esc cancel")
+	return lipgloss.NewStyle().Bold(true).Render(label) + hint + "\n" + v.input.View()
+}
+
+// AttachTerminal shows a PTY process's terminal and routes keystrokes to it
+func (v *ProcessViewController) AttachTerminal(proc *process.Process) error {
+	if !proc.IsPTY() {
+		return fmt.Errorf("process '%s' is not running in a terminal; set pty = true under [scripts.%s]", proc.Name, proc.Name)
+	}
+	v.attached.Store(proc)
+	v.resizeTerminal(proc)
+	return nil
+}
+
+// DetachTerminal returns to the process list
+func (v *ProcessViewController) DetachTerminal() {
+	v.attached.Store(nil)
+}
+
+// AttachedTerminal returns the process whose terminal is shown, or nil
+func (v *ProcessViewController) AttachedTerminal() *process.Process {
+	return v.attached.Load()
+}
+
+// resizeTerminal fits the process terminal to the content area below the title line
+func (v *ProcessViewController) resizeTerminal(proc *process.Process) {
+	if v.width > 0 && v.contentHeight > 1 && proc.GetStatus() == process.StatusRunning {
+		_ = proc.ResizeTerminal(v.width, v.contentHeight-1) // Best effort
+	}
+}
+
+// renderTerminal renders the attached process's screen
+func (v *ProcessViewController) renderTerminal(proc *process.Process) string {
+	status := "ctrl+q to detach"
+	if proc.GetStatus() != process.StatusRunning {
+		status = fmt.Sprintf("%s · ctrl+q to detach", proc.GetStatus())
+	}
+	title := lipgloss.NewStyle().Bold(true).Render("🖥️  "+proc.Name) +
+		lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Render("  "+status)
+
+	terminal := proc.Terminal()
+	terminal.Lock()
+	screen := renderTerminalScreen(terminal, v.width, v.contentHeight-1)
+	terminal.Unlock()
+
+	return title + "\n" + screen
+}
+
 // Render renders the processes view
 func (v *ProcessViewController) Render() string {
+	if proc := v.attached.Load(); proc != nil {
+		return v.renderTerminal(proc)
+	}
+
 	processes := v.processMgr.GetAllProcesses()
 	if len(processes) == 0 {
 		// Show empty state centered using the pre-calculated content height
@@ -153,6 +290,10 @@ func (v *ProcessViewController) Render() string {
 		return emptyState
 	}
 
+	if v.inputTarget != nil {
+		return v.processesList.View() + "\n" + v.renderInput()
+	}
+
 	// Just return the list view directly
 	return v.processesList.View()
 }
@@ -39,6 +39,25 @@ func NewScriptSelectorAutocompleteWithProcessManager(scripts map[string]string,
 	return c
 }
 
+// runnableScripts returns everything that can be started by name: the scripts and
+// services, and the project tasks labelled with the runner that runs them
+func runnableScripts(processMgr *process.Manager) map[string]string {
+	scripts := processMgr.GetScripts()
+	tasks := processMgr.ProjectTasks()
+	if len(tasks) == 0 {
+		return scripts
+	}
+
+	runnable := make(map[string]string, len(scripts)+len(tasks))
+	for name, script := range scripts {
+		runnable[name] = script
+	}
+	for _, task := range tasks {
+		runnable[task.Name] = fmt.Sprintf("[%s] %s", task.Category, strings.Join(append([]string{task.Command}, task.Args...), " "))
+	}
+	return runnable
+}
+
 // updateScriptSelectorSuggestions updates suggestions for script selector mode
 func (c *CommandAutocomplete) updateScriptSelectorSuggestions() {
 	value := strings.ToLower(c.input.Value())
@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -181,7 +182,7 @@ func (c *ScriptSelectorController) handleEnter() (bool, tea.Cmd) {
 				if c.processMgr == nil {
 					return fmt.Errorf(ErrProcessManagerNotInitialized)
 				}
-				_, err := c.processMgr.StartScript(scriptName)
+				_, err := c.processMgr.StartScriptOrTask(scriptName)
 				if err == nil && c.navController != nil {
 					// Success - switch to logs view
 					c.navController.SwitchTo(ViewLogs)
@@ -385,11 +386,70 @@ func (c *ScriptSelectorController) renderContent(termWidth int, input, dropdown
 		sections = append(sections, contentStyle.Render(dropdown))
 	}
 
+	// Add recurring tasks with their next and last runs
+	if schedules := c.renderSchedules(); schedules != "" {
+		sections = append(sections, contentStyle.Render(schedules))
+	}
+
 	// Join sections and center
 	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
 	return containerStyle.Render(content)
 }
 
+// renderSchedules lists the schedules from .brum.toml, or returns "" when there are none
+func (c *ScriptSelectorController) renderSchedules() string {
+	if c.processMgr == nil {
+		return ""
+	}
+	schedules := c.processMgr.Schedules()
+	if len(schedules) == 0 {
+		return ""
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("226")).MarginTop(1)
+	nameStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+	detailStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("242")).Italic(true)
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+
+	lines := []string{headerStyle.Render("⏰ Schedules")}
+	for _, sched := range schedules {
+		var details []string
+		switch {
+		case sched.Error != "":
+			lines = append(lines, "  "+nameStyle.Render(sched.Name)+"  "+errorStyle.Render(sched.Error))
+			continue
+		case sched.Disabled:
+			details = append(details, sched.Schedule, "disabled")
+		default:
+			details = append(details, sched.Schedule)
+			if sched.NextRun != nil {
+				details = append(details, "next "+formatScheduleTime(*sched.NextRun))
+			}
+		}
+		if sched.LastRun != nil {
+			last := "last " + formatScheduleTime(*sched.LastRun)
+			if sched.LastStatus != "" {
+				last += " " + string(sched.LastStatus)
+			}
+			details = append(details, last)
+		}
+		if sched.Skipped > 0 {
+			details = append(details, fmt.Sprintf("%d skipped", sched.Skipped))
+		}
+		lines = append(lines, "  "+nameStyle.Render(sched.Name)+"  "+detailStyle.Render(strings.Join(details, " · ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatScheduleTime shows a time of day, with the date when it is not today
+func formatScheduleTime(t time.Time) string {
+	now := time.Now()
+	if t.Year() == now.Year() && t.YearDay() == now.YearDay() {
+		return t.Format("15:04")
+	}
+	return t.Format("Jan 2 15:04")
+}
+
 // renderHelpText creates the centered help text using Lipgloss
 func (c *ScriptSelectorController) renderHelpText(termWidth int) string {
 	helpStyle := lipgloss.NewStyle().
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -129,18 +130,49 @@ func (h *ViewSpecificHandler) handleWebViewKeys(msg tea.KeyMsg, model *Model) (t
 func (h *ViewSpecificHandler) handleProcessViewKeys(msg tea.KeyMsg, model *Model) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	// Keystrokes belong to the attached terminal or the line input; the input
+	// controller forwards them
+	if model.processViewController.AttachedTerminal() != nil || model.processViewController.InputTarget() != nil {
+		return model, nil
+	}
+
 	switch {
-	case key.Matches(msg, model.keys.Stop):
+	case key.Matches(msg, model.keys.Attach):
+		if i, ok := model.processViewController.GetProcessesList().SelectedItem().(processItem); ok && !i.isHeader && i.process != nil {
+			if err := model.processViewController.AttachTerminal(i.process); err != nil {
+				model.systemController.AddMessage("error", "Process Control", err.Error())
+			}
+		}
+		return model, nil
+
+	case key.Matches(msg, model.keys.Input):
 		if i, ok := model.processViewController.GetProcessesList().SelectedItem().(processItem); ok && !i.isHeader && i.process != nil {
-			if err := model.processMgr.StopProcess(i.process.ID); err != nil {
-				msg := fmt.Sprintf("Failed to stop process %s: %v", i.process.Name, err)
-				model.logStore.Add("system", "System", msg, true)
-				model.systemController.AddMessage("error", "Process Control", msg)
-			} else {
-				msg := fmt.Sprintf("Stopping process: %s", i.process.Name)
-				model.logStore.Add("system", "System", msg, false)
-				model.systemController.AddMessage("info", "Process Control", msg)
+			if err := model.processViewController.StartInput(i.process); err != nil {
+				model.systemController.AddMessage("error", "Process Input", err.Error())
 			}
+		}
+		return model, nil
+
+	case key.Matches(msg, model.keys.Stop):
+		if i, ok := model.processViewController.GetProcessesList().SelectedItem().(processItem); ok && !i.isHeader && i.process != nil {
+			proc := i.process
+			model.systemController.AddMessage("info", "Process Control", fmt.Sprintf("Stopping process: %s", proc.Name))
+			// Stopping waits through pre_stop and stop_timeout, so keep it off the UI
+			SafeGoroutineNoError(
+				fmt.Sprintf("stop process '%s'", proc.Name),
+				func() {
+					if stopped, err := model.processMgr.StopProcessWithDependents(proc.ID); err != nil {
+						model.logStore.Add("system", "System", fmt.Sprintf("Failed to stop process %s: %v", proc.Name, err), true)
+					} else {
+						model.logStore.Add("system", "System", fmt.Sprintf("⏹️ Stopped: %s", strings.Join(stopped, ", ")), false)
+					}
+					model.updateChan <- processUpdateMsg{}
+				},
+				func(err error) {
+					model.logStore.Add("system", "System", fmt.Sprintf("Critical error while stopping %s: %v", proc.Name, err), true)
+					model.updateChan <- logUpdateMsg{}
+				},
+			)
 			cmds = append(cmds, model.waitForUpdates())
 		} else {
 			msg := "No process selected to stop"
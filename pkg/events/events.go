@@ -12,16 +12,28 @@ import (
 type EventType string
 
 const (
-	ProcessStarted  EventType = "process.started"
-	ProcessExited   EventType = "process.exited"
-	LogLine         EventType = "log.line"
-	ErrorDetected   EventType = "error.detected"
-	BuildEvent      EventType = "build.event"
-	TestFailed      EventType = "test.failed"
-	TestPassed      EventType = "test.passed"
-	MCPActivity     EventType = "mcp.activity"
-	MCPConnected    EventType = "mcp.connected"
-	MCPDisconnected EventType = "mcp.disconnected"
+	ProcessStarted       EventType = "process.started"
+	ProcessExited        EventType = "process.exited"
+	ProcessRestarting    EventType = "process.restarting"
+	ProcessCrashLoop     EventType = "process.crashloop"
+	ProcessReady         EventType = "process.ready"
+	ProcessNotReady      EventType = "process.notready"
+	ProcessHealthy       EventType = "process.healthy"
+	ProcessUnhealthy     EventType = "process.unhealthy"
+	ProcessTerminal      EventType = "process.terminal"
+	ProcessAwaitingInput EventType = "process.awaiting_input"
+	ProcessFileChanged   EventType = "process.file_changed"
+	ProcessPortConflict  EventType = "process.port_conflict"
+	MatrixFinished       EventType = "matrix.finished"
+	TaskRunFinished      EventType = "task.finished"
+	LogLine              EventType = "log.line"
+	ErrorDetected        EventType = "error.detected"
+	BuildEvent           EventType = "build.event"
+	TestFailed           EventType = "test.failed"
+	TestPassed           EventType = "test.passed"
+	MCPActivity          EventType = "mcp.activity"
+	MCPConnected         EventType = "mcp.connected"
+	MCPDisconnected      EventType = "mcp.disconnected"
 )
 
 type Event struct {
@@ -69,3 +69,8 @@ func FindAvailablePortInRange(minPort, maxPort int) (int, error) {
 
 	return 0, fmt.Errorf("unable to find available port after %d attempts in range %d-%d", maxAttempts, minPort, maxPort)
 }
+
+// IsPortAvailable reports whether nothing is listening on port
+func IsPortAvailable(port int) bool {
+	return isPortAvailable(port)
+}